@@ -0,0 +1,70 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Environment variables overriding the matching Logger fields.
+const (
+	EnvLogLevel      = "LOG_LEVEL"
+	EnvLogFileName   = "LOG_FILE_NAME"
+	EnvLogMaxSizeMB  = "LOG_MAX_SIZE_MB"
+	EnvLogMaxBackups = "LOG_MAX_BACKUPS"
+	EnvLogMaxAgeDays = "LOG_MAX_AGE_DAYS"
+	EnvLogCompress   = "LOG_COMPRESS"
+)
+
+// LoadFromEnv returns a Logger populated from the LOG_* environment variables,
+// leaving fields whose variable is unset (or unparsable) at their zero value.
+// Use MergeEnv to find out about unparsable values.
+func LoadFromEnv() Logger {
+	conf, _ := MergeEnv(Logger{})
+	return conf
+}
+
+// MergeEnv returns conf with every LOG_* environment variable that is set
+// overriding the corresponding field, so env takes precedence over values
+// read from YAML. Values that fail to parse leave the field untouched and are
+// reported in the returned error.
+func MergeEnv(conf Logger) (Logger, error) {
+	var errs []error
+
+	if v, ok := os.LookupEnv(EnvLogLevel); ok {
+		conf.LoggingLevel = v
+	}
+	if v, ok := os.LookupEnv(EnvLogFileName); ok {
+		conf.LogFileName = v
+	}
+	for _, e := range []struct {
+		name  string
+		field *int
+	}{
+		{EnvLogMaxSizeMB, &conf.LogFileSizeCappingInMBs},
+		{EnvLogMaxBackups, &conf.MaxLogBackupsCount},
+		{EnvLogMaxAgeDays, &conf.MaxOldLogRetentionInDays},
+	} {
+		v, ok := os.LookupEnv(e.name)
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("config: invalid %s %q: must be an integer", e.name, v))
+			continue
+		}
+		*e.field = n
+	}
+	if v, ok := os.LookupEnv(EnvLogCompress); ok {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("config: invalid %s %q: must be a boolean", EnvLogCompress, v))
+		} else {
+			conf.OldLogsCompressionRequired = b
+		}
+	}
+
+	return conf, errors.Join(errs...)
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMergeEnv(t *testing.T) {
+	t.Setenv(EnvLogLevel, "DEBUG")
+	t.Setenv(EnvLogFileName, "/var/log/app.log")
+	t.Setenv(EnvLogMaxSizeMB, "50")
+	t.Setenv(EnvLogMaxBackups, "3")
+	t.Setenv(EnvLogMaxAgeDays, "7")
+	t.Setenv(EnvLogCompress, "true")
+
+	conf, err := MergeEnv(Logger{LoggingLevel: "INFO", LogFileName: "app.log", MaxLogBackupsCount: 10, Encoding: "json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Logger{
+		LoggingLevel:               "DEBUG",
+		LogFileName:                "/var/log/app.log",
+		LogFileSizeCappingInMBs:    50,
+		MaxLogBackupsCount:         3,
+		MaxOldLogRetentionInDays:   7,
+		OldLogsCompressionRequired: true,
+		Encoding:                   "json",
+	}
+	if conf.LoggingLevel != want.LoggingLevel || conf.LogFileName != want.LogFileName ||
+		conf.LogFileSizeCappingInMBs != want.LogFileSizeCappingInMBs || conf.MaxLogBackupsCount != want.MaxLogBackupsCount ||
+		conf.MaxOldLogRetentionInDays != want.MaxOldLogRetentionInDays || conf.OldLogsCompressionRequired != want.OldLogsCompressionRequired ||
+		conf.Encoding != want.Encoding {
+		t.Errorf("MergeEnv = %+v, want %+v", conf, want)
+	}
+}
+
+func TestMergeEnvKeepsUnsetFields(t *testing.T) {
+	conf, err := MergeEnv(Logger{LoggingLevel: "WARN", MaxLogBackupsCount: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.LoggingLevel != "WARN" || conf.MaxLogBackupsCount != 10 {
+		t.Errorf("MergeEnv = %+v, want the fields untouched", conf)
+	}
+}
+
+func TestMergeEnvReportsInvalidValues(t *testing.T) {
+	t.Setenv(EnvLogMaxSizeMB, "big")
+	t.Setenv(EnvLogCompress, "maybe")
+	t.Setenv(EnvLogMaxBackups, "4")
+
+	conf, err := MergeEnv(Logger{LogFileSizeCappingInMBs: 20})
+	if err == nil {
+		t.Fatal("MergeEnv accepted invalid values")
+	}
+	for _, name := range []string{EnvLogMaxSizeMB, EnvLogCompress} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("error %q does not name %s", err, name)
+		}
+	}
+	if conf.LogFileSizeCappingInMBs != 20 || conf.MaxLogBackupsCount != 4 {
+		t.Errorf("MergeEnv = %+v, want the invalid value ignored and the valid one applied", conf)
+	}
+	if LoadFromEnv().MaxLogBackupsCount != 4 {
+		t.Error("LoadFromEnv ignored the valid value")
+	}
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// knownLevels are the level names understood by the logger package.
+var knownLevels = map[string]bool{
+	"TRACE":  true,
+	"DEBUG":  true,
+	"INFO":   true,
+	"WARN":   true,
+	"ERROR":  true,
+	"DPANIC": true,
+	"PANIC":  true,
+	"FATAL":  true,
+}
+
+// Load reads the YAML config file at path and applies the LOG_* environment
+// overrides on top of it (see MergeEnv). An unknown logging level falls back
+// to INFO.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+
+	var conf Config
+	if err := yaml.Unmarshal(data, &conf); err != nil {
+		return nil, fmt.Errorf("config: parsing %s: %w", path, err)
+	}
+
+	conf.Logger, err = MergeEnv(conf.Logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if !knownLevels[conf.Logger.LoggingLevel] {
+		conf.Logger.LoggingLevel = "INFO"
+	}
+	for name, l := range conf.Loggers {
+		if !knownLevels[l.LoggingLevel] {
+			l.LoggingLevel = "INFO"
+			conf.Loggers[name] = l
+		}
+	}
+	return &conf, nil
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	path := writeConfig(t, `
+logger:
+  logging_level: WARN
+  log_file_name: /var/log/app.log
+  log_file_size_capping_in_mbs: 20
+  logs_compression_required: true
+loggers:
+  audit:
+    logging_level: DEBUG
+`)
+	conf, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l := conf.Logger; l.LoggingLevel != "WARN" || l.LogFileName != "/var/log/app.log" || l.LogFileSizeCappingInMBs != 20 || !l.OldLogsCompressionRequired {
+		t.Errorf("Logger = %+v", l)
+	}
+	if conf.Loggers["audit"].LoggingLevel != "DEBUG" {
+		t.Errorf("Loggers = %+v", conf.Loggers)
+	}
+}
+
+func TestLoadAppliesTheEnvironment(t *testing.T) {
+	t.Setenv(EnvLogLevel, "ERROR")
+	conf, err := Load(writeConfig(t, "logger:\n  logging_level: WARN\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.Logger.LoggingLevel != "ERROR" {
+		t.Errorf("logging_level = %s, want the ERROR of the environment", conf.Logger.LoggingLevel)
+	}
+}
+
+func TestLoadDefaultsUnknownLevelsToInfo(t *testing.T) {
+	conf, err := Load(writeConfig(t, "logger:\n  logging_level: LOUD\nloggers:\n  db:\n    logging_level: quiet\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.Logger.LoggingLevel != "INFO" || conf.Loggers["db"].LoggingLevel != "INFO" {
+		t.Errorf("levels = %s and %s, want INFO", conf.Logger.LoggingLevel, conf.Loggers["db"].LoggingLevel)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Load accepted a missing file")
+	}
+	path := writeConfig(t, "logger: [not, a, map]\n")
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("Load = %v, want a parse error naming the file", err)
+	}
+}
@@ -1,14 +1,214 @@
 package config
 
+import "time"
+
+// FileSink is an additional log file with its own level and level of detail.
+type FileSink struct {
+	FileName        string `yaml:"file_name"`
+	Level           string `yaml:"level"`
+	IncludeCaller   bool   `yaml:"include_caller"`
+	StacktraceLevel string `yaml:"stacktrace_level"` // entries at or above it carry a stacktrace, empty for none
+}
+
+// MaskRule replaces the matches of the regular expression Pattern in string
+// fields and messages with Replacement, which may refer to submatches as in
+// regexp.Regexp.ReplaceAllString, e.g. "****-$1".
+type MaskRule struct {
+	Pattern     string `yaml:"pattern"`
+	Replacement string `yaml:"replacement"`
+}
+
+// SamplingRule keeps, per message, the first Initial entries of every tick
+// then every Thereafter-th, dropping the others. A Thereafter of 0 drops
+// every entry past Initial.
+type SamplingRule struct {
+	Initial    int `yaml:"initial"`
+	Thereafter int `yaml:"thereafter"`
+}
+
+// Sampling applies its rule to every level when Initial is set, Levels
+// replacing it for the levels they name, e.g. {"DEBUG": {1, 100}}; with
+// Levels alone the levels they don't name are left unsampled. Entries are
+// counted per Tick (default one second). How many were dropped per level is
+// logged every ReportInterval (default one minute) in which any were.
+type Sampling struct {
+	SamplingRule   `yaml:",inline"`
+	Levels         map[string]SamplingRule `yaml:"levels"`
+	Tick           time.Duration           `yaml:"tick"`
+	ReportInterval time.Duration           `yaml:"report_interval"`
+}
+
+// QuietHoursLayout is the time layout of QuietHours bounds.
+const QuietHoursLayout = "15:04"
+
+// QuietHours is a daily window, from Start to End in local time, e.g. "22:00"
+// to "06:00", during which entries below Level (default and at most "ERROR")
+// are dropped.
+type QuietHours struct {
+	Start string `yaml:"start"`
+	End   string `yaml:"end"`
+	Level string `yaml:"level"`
+}
+
 type Logger struct {
-	LogFileName                string `yaml:"log_file_name"`
-	LoggingLevel               string `yaml:"logging_level"`
-	LogFileSizeCappingInMBs    int    `yaml:"log_file_size_capping_in_mbs"`
-	MaxLogBackupsCount         int    `yaml:"max_log_backups_count"`
-	MaxOldLogRetentionInDays   int    `yaml:"max_old_log_retention_in_days"`
-	OldLogsCompressionRequired bool   `yaml:"logs_compression_required"`
+	LogFileName                string   `yaml:"log_file_name"`
+	AdditionalLogFiles         []string `yaml:"additional_log_files"` // written alongside LogFileName
+	ErrorLogFileName           string   `yaml:"error_log_file_name"`
+	AuditLogFileName           string   `yaml:"audit_log_file_name"` // receives the entries of Audit
+	SyslogAddr                 string   `yaml:"syslog_addr"`         // "udp://host:514", "tcp://host:514" or "local"
+	SyslogTag                  string   `yaml:"syslog_tag"`          // also the journald SYSLOG_IDENTIFIER
+	Journald                   bool     `yaml:"journald"`            // entries and their fields sent to journald, Linux only
+	EventLogSource             string   `yaml:"event_log_source"`    // Windows event log source, Windows only
+	LoggingLevel               string   `yaml:"logging_level"`
+	Encoding                   string   `yaml:"encoding"`    // "json" (default), "console", "csv", "gcp", "ecs", "logfmt" or "datadog"
+	CSVColumns                 []string `yaml:"csv_columns"` // columns of the csv encoding, standard keys or field names
+	CompactConsole             bool     `yaml:"compact_console"`
+	ColorOutput                bool     `yaml:"color_output"`   // colored levels, console encoding only
+	LevelEncoding              string   `yaml:"level_encoding"` // "capital" (default), "lowercase", "capitalColor" or "lowercaseColor"
+	PrettyPrint                bool     `yaml:"pretty_print"`   // indented json entries for development, breaks line based parsers
+	DualEncoding               bool     `yaml:"dual_encoding"`  // console encoding on stdout and stderr, Encoding in the files
+	LineEnding                 string   `yaml:"line_ending"`    // "lf" (default) or "crlf", for collectors splitting on \r\n
+	LogFileSizeCappingInMBs    int      `yaml:"log_file_size_capping_in_mbs"`
+	MaxLogBackupsCount         int      `yaml:"max_log_backups_count"`
+	MaxOldLogRetentionInDays   int      `yaml:"max_old_log_retention_in_days"`
+	OldLogsCompressionRequired bool     `yaml:"logs_compression_required"`
+	LogFileMode                string   `yaml:"log_file_mode"`       // octal permissions of new log files, e.g. "0640"
+	CurrentLogSymlink          string   `yaml:"current_log_symlink"` // kept pointing at LogFileName for tailers
+	DisableCaller              bool     `yaml:"disable_caller"`
+	ShortCaller                bool     `yaml:"short_caller"`
+	TrimCallerPrefix           string   `yaml:"trim_caller_prefix"` // trimmed from caller paths, short form when they lack it
+	NilFieldsAsNull            bool     `yaml:"nil_fields_as_null"`
+	Development                bool     `yaml:"development"` // DPanic entries panic after being logged
+
+	// ErrorResponseMessage makes Error add a response_message field holding
+	// the message of a trailing error argument, as ErrorWithResponse does.
+	ErrorResponseMessage bool `yaml:"error_response_message"`
+
+	// IncludeGoroutineID adds the id of the logging goroutine to every entry
+	// as a goroutine field. Getting it costs a few microseconds and an
+	// allocation per entry, it is meant for debugging only.
+	IncludeGoroutineID bool `yaml:"include_goroutine_id"`
+
+	// MessageKey, LevelKey, TimeKey and CallerKey rename the standard keys of
+	// the entries, "message", "level", "time" and "caller" when unset. An
+	// empty key leaves its value out.
+	MessageKey *string `yaml:"message_key"`
+	LevelKey   *string `yaml:"level_key"`
+	TimeKey    *string `yaml:"time_key"`
+	CallerKey  *string `yaml:"caller_key"`
+
+	// ServiceName, ServiceVersion and Environment are added to every entry
+	// when set, as are the hostname and pid with IncludeHostPid.
+	ServiceName    string `yaml:"service_name"`
+	ServiceVersion string `yaml:"service_version"`
+	Environment    string `yaml:"environment"`
+	IncludeHostPid bool   `yaml:"include_host_pid"`
+
+	// IncludeUptime adds an uptime field to every entry, the time elapsed
+	// since the logger was built, which logs the start time once.
+	IncludeUptime bool `yaml:"include_uptime"`
+
+	// StatsInterval, when set, samples the time spent writing each entry and
+	// logs a logger_stats entry with its p50/p99 every interval.
+	StatsInterval time.Duration `yaml:"stats_interval"`
+
+	// StackDedupWindow, when set, drops Error and above entries whose
+	// stacktrace was already logged within the window and reports how many
+	// were dropped once the window closes.
+	StackDedupWindow time.Duration `yaml:"stack_dedup_window"`
+
+	// DedupWindow, when set, drops entries repeating the level and message of
+	// the previous one within the window and reports how many were dropped
+	// once it closes.
+	DedupWindow time.Duration `yaml:"dedup_window"`
+
+	// Sampling, when set, limits the entries logged per level and message,
+	// AUDIT entries excepted.
+	Sampling *Sampling `yaml:"sampling"`
+
+	// QuietHours drop entries below ERROR during known noisy windows.
+	QuietHours []QuietHours `yaml:"quiet_hours"`
+
+	// ErrorDemoteThreshold, when set, logs ERROR entries at WARN once their
+	// message was logged more than ErrorDemoteThreshold times within
+	// ErrorDemoteWindow (default one minute), until the window closes.
+	ErrorDemoteThreshold int           `yaml:"error_demote_threshold"`
+	ErrorDemoteWindow    time.Duration `yaml:"error_demote_window"`
+
+	// SlowStackThreshold, when set, attaches a stacktrace to entries whose
+	// SlowStackField duration field (default "duration") exceeds it.
+	SlowStackThreshold time.Duration `yaml:"slow_stack_threshold"`
+	SlowStackField     string        `yaml:"slow_stack_field"`
+
+	// MaskRules are applied, in order, to the message and string fields of
+	// every entry.
+	MaskRules []MaskRule `yaml:"mask_rules"`
+
+	// MaxFieldLength, when set, truncates the message and string fields
+	// longer than it, in bytes, and records their original length.
+	MaxFieldLength int `yaml:"max_field_length"`
+
+	// RotateDaily additionally rotates the log files at every local midnight,
+	// naming the rotated file after the day it holds.
+	RotateDaily bool `yaml:"rotate_daily"`
+
+	// CircularFileSize, when set, replaces rotation of the log files with a
+	// single file per output keeping the last CircularFileSize bytes, the
+	// oldest being overwritten. Read it back with logger.ReadCircularFile.
+	CircularFileSize int `yaml:"circular_file_size"`
+
+	// FallbackToStderr writes entries to stderr when writing them to a log
+	// file fails, e.g. on a full disk, trying the file again after a while.
+	FallbackToStderr bool `yaml:"fallback_to_stderr"`
+
+	// AsyncBuffer hands file writes to a background goroutine through a queue
+	// of AsyncBufferSize entries, so logging only waits when the queue is
+	// full. Queued entries are lost if the process crashes, Sync and Close
+	// wait for them to be written. With AsyncBufferDropOnFull, entries are
+	// dropped instead of waiting while the queue is full and a warning
+	// reports how many every minute.
+	AsyncBuffer           bool `yaml:"async_buffer"`
+	AsyncBufferSize       int  `yaml:"async_buffer_size"`
+	AsyncBufferDropOnFull bool `yaml:"async_buffer_drop_on_full"`
+
+	// BackgroundFlushInterval, when set, buffers writes to log files and
+	// flushes them every interval as well as on Sync and Close.
+	BackgroundFlushInterval time.Duration `yaml:"background_flush_interval"`
+
+	// FileSinks are extra log files written alongside the outputs above, each
+	// filtered by its own level regardless of LoggingLevel.
+	FileSinks []FileSink `yaml:"file_sinks"`
+
+	// RecentEntries, when set, keeps the last RecentEntries entries at or
+	// above RecentEntriesLevel (default "DEBUG") in memory, regardless of
+	// LoggingLevel, for DumpRecent.
+	RecentEntries      int    `yaml:"recent_entries"`
+	RecentEntriesLevel string `yaml:"recent_entries_level"`
+
+	// LevelColors overrides the console color of a level with the parameters
+	// of an ANSI SGR sequence, e.g. {"WARN": "38;5;208"} for orange.
+	LevelColors map[string]string `yaml:"level_colors"`
+
+	// ErrorsToStderr writes entries at or above StderrLevel (default "WARN")
+	// to stderr instead of stdout. Log files still receive every entry.
+	ErrorsToStderr bool   `yaml:"errors_to_stderr"`
+	StderrLevel    string `yaml:"stderr_level"`
+
+	// DisableStdout stops writing entries to stdout, which then needs another
+	// output such as LogFileName.
+	DisableStdout bool `yaml:"disable_stdout"`
+
+	// LevelOutputs maps a level name to the outputs ("stdout", "stderr" or a
+	// file path) receiving entries from that level up to, but excluding, the
+	// next configured level. When set it replaces stdout and LogFileName.
+	LevelOutputs map[string][]string `yaml:"level_outputs"`
 }
 
 type Config struct {
 	Logger Logger `yaml:"logger"`
+
+	// Loggers are named loggers, e.g. "app", "audit" and "access", each with
+	// its own outputs and level, built by logger.NewServices. The LOG_*
+	// environment variables only apply to Logger.
+	Loggers map[string]Logger `yaml:"loggers"`
 }
@@ -0,0 +1,350 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Defaults applied by WithDefaults.
+const (
+	DefaultLoggingLevel             = "INFO"
+	DefaultEncoding                 = "json"
+	DefaultLogFileSizeCappingInMBs  = 100
+	DefaultMaxLogBackupsCount       = 5
+	DefaultMaxOldLogRetentionInDays = 30
+	DefaultSlowStackField           = "duration"
+	DefaultAsyncBufferSize          = 1024
+	DefaultStderrLevel              = "WARN"
+	DefaultLevelEncoding            = "capital"
+	DefaultLineEnding               = "lf"
+	DefaultRecentEntriesLevel       = "DEBUG"
+	DefaultSamplingTick             = time.Second
+	DefaultSamplingReportInterval   = time.Minute
+	DefaultErrorDemoteWindow        = time.Minute
+)
+
+// sgrParams matches the parameters of an ANSI SGR sequence, e.g. "33" or
+// "38;5;208".
+var sgrParams = regexp.MustCompile(`^[0-9]{1,3}(;[0-9]{1,3})*$`)
+
+// ValidColor reports whether code is a valid LevelColors value, the
+// parameters of an ANSI SGR sequence.
+func ValidColor(code string) bool {
+	return sgrParams.MatchString(code)
+}
+
+var knownEncodings = map[string]bool{
+	"json":    true,
+	"console": true,
+	"csv":     true,
+	"gcp":     true,
+	"ecs":     true,
+	"logfmt":  true,
+	"datadog": true,
+}
+
+var knownLineEndings = map[string]bool{
+	"lf":   true,
+	"crlf": true,
+}
+
+var knownLevelEncodings = map[string]bool{
+	"capital":        true,
+	"lowercase":      true,
+	"capitalColor":   true,
+	"lowercaseColor": true,
+}
+
+// WithDefaults returns a copy of l with empty or non-positive settings
+// replaced by their documented defaults, so lumberjack never falls back to its
+// own implicit ones.
+func (l Logger) WithDefaults() Logger {
+	if l.LoggingLevel == "" {
+		l.LoggingLevel = DefaultLoggingLevel
+	}
+	if l.Encoding == "" {
+		l.Encoding = DefaultEncoding
+	}
+	if l.LogFileSizeCappingInMBs <= 0 {
+		l.LogFileSizeCappingInMBs = DefaultLogFileSizeCappingInMBs
+	}
+	if l.MaxLogBackupsCount <= 0 {
+		l.MaxLogBackupsCount = DefaultMaxLogBackupsCount
+	}
+	if l.MaxOldLogRetentionInDays <= 0 {
+		l.MaxOldLogRetentionInDays = DefaultMaxOldLogRetentionInDays
+	}
+	if l.SlowStackField == "" {
+		l.SlowStackField = DefaultSlowStackField
+	}
+	if l.AsyncBufferSize <= 0 {
+		l.AsyncBufferSize = DefaultAsyncBufferSize
+	}
+	if l.LevelEncoding == "" {
+		l.LevelEncoding = DefaultLevelEncoding
+	}
+	if l.LineEnding == "" {
+		l.LineEnding = DefaultLineEnding
+	}
+	if l.StderrLevel == "" {
+		l.StderrLevel = DefaultStderrLevel
+	}
+	if l.Sampling != nil {
+		sampling := *l.Sampling
+		if sampling.Tick <= 0 {
+			sampling.Tick = DefaultSamplingTick
+		}
+		if sampling.ReportInterval <= 0 {
+			sampling.ReportInterval = DefaultSamplingReportInterval
+		}
+		l.Sampling = &sampling
+	}
+	if l.ErrorDemoteWindow <= 0 {
+		l.ErrorDemoteWindow = DefaultErrorDemoteWindow
+	}
+	if l.RecentEntriesLevel == "" {
+		l.RecentEntriesLevel = DefaultRecentEntriesLevel
+	}
+	return l
+}
+
+// hasOutputBesidesStdout reports whether entries below ERROR are written
+// somewhere other than stdout.
+func (l Logger) hasOutputBesidesStdout() bool {
+	if l.LogFileName != "" || l.SyslogAddr != "" || l.Journald || l.EventLogSource != "" || l.ErrorsToStderr || len(l.FileSinks) > 0 || len(l.LevelOutputs) > 0 {
+		return true
+	}
+	for _, path := range l.AdditionalLogFiles {
+		if path != "" && path != "stdout" {
+			return true
+		}
+	}
+	return false
+}
+
+func (r SamplingRule) validate(path string) []error {
+	var errs []error
+	// the first entry of every message, including the reports, is kept
+	if r.Initial < 1 {
+		errs = append(errs, fmt.Errorf("config: %s.initial must be at least 1, got %d", path, r.Initial))
+	}
+	if r.Thereafter < 0 {
+		errs = append(errs, fmt.Errorf("config: %s.thereafter must not be negative, got %d", path, r.Thereafter))
+	}
+	return errs
+}
+
+// ValidateLoggers validates every named logger of c and reports log files
+// shared by several of them, which would rotate under each other's feet.
+func (c Config) ValidateLoggers() error {
+	var errs []error
+	files := make(map[string]string) // file name -> logger writing it
+	for _, name := range sortedKeys(c.Loggers) {
+		l := c.Loggers[name]
+		if err := l.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("loggers[%q]: %w", name, err))
+		}
+		for _, file := range []string{l.LogFileName, l.ErrorLogFileName, l.AuditLogFileName} {
+			if file == "" {
+				continue
+			}
+			if other, ok := files[file]; ok {
+				errs = append(errs, fmt.Errorf("config: loggers %q and %q both write %s", other, name, file))
+				continue
+			}
+			files[file] = name
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func sortedKeys(m map[string]Logger) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Validate reports settings that are invalid on their own or in combination.
+// Zero values are valid, WithDefaults fills them in.
+func (l Logger) Validate() error {
+	var errs []error
+
+	if l.LoggingLevel != "" && !knownLevels[l.LoggingLevel] {
+		errs = append(errs, fmt.Errorf("config: unknown logging_level %q", l.LoggingLevel))
+	}
+	if l.StderrLevel != "" && !knownLevels[l.StderrLevel] {
+		errs = append(errs, fmt.Errorf("config: unknown stderr_level %q", l.StderrLevel))
+	}
+	if l.RecentEntriesLevel != "" && !knownLevels[l.RecentEntriesLevel] {
+		errs = append(errs, fmt.Errorf("config: unknown recent_entries_level %q", l.RecentEntriesLevel))
+	}
+	if l.Encoding != "" && !knownEncodings[l.Encoding] {
+		errs = append(errs, fmt.Errorf("config: unknown encoding %q", l.Encoding))
+	}
+	if l.LevelEncoding != "" && !knownLevelEncodings[l.LevelEncoding] {
+		errs = append(errs, fmt.Errorf("config: unknown level_encoding %q", l.LevelEncoding))
+	}
+	if l.LineEnding != "" && !knownLineEndings[l.LineEnding] {
+		errs = append(errs, fmt.Errorf("config: line_ending must be \"lf\" or \"crlf\", got %q", l.LineEnding))
+	}
+	if l.LogFileSizeCappingInMBs < 0 {
+		errs = append(errs, fmt.Errorf("config: log_file_size_capping_in_mbs must not be negative, got %d", l.LogFileSizeCappingInMBs))
+	}
+	if l.LogFileMode != "" {
+		if mode, err := strconv.ParseUint(l.LogFileMode, 8, 32); err != nil || mode > 0o777 {
+			errs = append(errs, fmt.Errorf("config: log_file_mode must be octal permissions like \"0640\", got %q", l.LogFileMode))
+		}
+	}
+	if l.MaxLogBackupsCount < 0 {
+		errs = append(errs, fmt.Errorf("config: max_log_backups_count must not be negative, got %d", l.MaxLogBackupsCount))
+	}
+	if l.MaxOldLogRetentionInDays < 0 {
+		errs = append(errs, fmt.Errorf("config: max_old_log_retention_in_days must not be negative, got %d", l.MaxOldLogRetentionInDays))
+	}
+	if l.StatsInterval < 0 {
+		errs = append(errs, fmt.Errorf("config: stats_interval must not be negative, got %s", l.StatsInterval))
+	}
+	if l.StackDedupWindow < 0 {
+		errs = append(errs, fmt.Errorf("config: stack_dedup_window must not be negative, got %s", l.StackDedupWindow))
+	}
+	if l.DedupWindow < 0 {
+		errs = append(errs, fmt.Errorf("config: dedup_window must not be negative, got %s", l.DedupWindow))
+	}
+	if l.Sampling != nil {
+		switch {
+		case l.Sampling.Initial == 0 && l.Sampling.Thereafter == 0:
+			if len(l.Sampling.Levels) == 0 {
+				errs = append(errs, errors.New("config: sampling needs initial or levels"))
+			}
+		default:
+			errs = append(errs, l.Sampling.SamplingRule.validate("sampling")...)
+		}
+		for name, rule := range l.Sampling.Levels {
+			if !knownLevels[name] {
+				errs = append(errs, fmt.Errorf("config: unknown level %q in sampling.levels", name))
+			}
+			errs = append(errs, rule.validate(fmt.Sprintf("sampling.levels[%s]", name))...)
+		}
+		if l.Sampling.Tick < 0 {
+			errs = append(errs, fmt.Errorf("config: sampling.tick must not be negative, got %s", l.Sampling.Tick))
+		}
+		if l.Sampling.ReportInterval < 0 {
+			errs = append(errs, fmt.Errorf("config: sampling.report_interval must not be negative, got %s", l.Sampling.ReportInterval))
+		}
+	}
+	if l.ErrorDemoteThreshold < 0 {
+		errs = append(errs, fmt.Errorf("config: error_demote_threshold must not be negative, got %d", l.ErrorDemoteThreshold))
+	}
+	if l.ErrorDemoteWindow < 0 {
+		errs = append(errs, fmt.Errorf("config: error_demote_window must not be negative, got %s", l.ErrorDemoteWindow))
+	}
+	if l.SlowStackThreshold < 0 {
+		errs = append(errs, fmt.Errorf("config: slow_stack_threshold must not be negative, got %s", l.SlowStackThreshold))
+	}
+	if l.MaxFieldLength < 0 {
+		errs = append(errs, fmt.Errorf("config: max_field_length must not be negative, got %d", l.MaxFieldLength))
+	}
+	if l.CircularFileSize < 0 {
+		errs = append(errs, fmt.Errorf("config: circular_file_size must not be negative, got %d", l.CircularFileSize))
+	}
+	if l.RecentEntries < 0 {
+		errs = append(errs, fmt.Errorf("config: recent_entries must not be negative, got %d", l.RecentEntries))
+	}
+	if l.AsyncBufferSize < 0 {
+		errs = append(errs, fmt.Errorf("config: async_buffer_size must not be negative, got %d", l.AsyncBufferSize))
+	}
+	if l.BackgroundFlushInterval < 0 {
+		errs = append(errs, fmt.Errorf("config: background_flush_interval must not be negative, got %s", l.BackgroundFlushInterval))
+	}
+	if l.ErrorLogFileName != "" && l.ErrorLogFileName == l.LogFileName {
+		errs = append(errs, errors.New("config: error_log_file_name must differ from log_file_name"))
+	}
+	if l.DualEncoding && len(l.LevelOutputs) > 0 {
+		errs = append(errs, errors.New("config: dual_encoding has no effect with level_outputs"))
+	}
+	if l.ErrorsToStderr && len(l.LevelOutputs) > 0 {
+		errs = append(errs, errors.New("config: errors_to_stderr has no effect with level_outputs"))
+	}
+	if l.CurrentLogSymlink != "" && l.LogFileName == "" {
+		errs = append(errs, errors.New("config: current_log_symlink needs log_file_name"))
+	}
+	if l.CompactConsole && l.Encoding != "console" {
+		errs = append(errs, errors.New("config: compact_console needs the console encoding"))
+	}
+	if l.PrettyPrint && l.Encoding != "" && l.Encoding != "json" {
+		errs = append(errs, errors.New("config: pretty_print needs the json encoding"))
+	}
+	if l.AuditLogFileName != "" && (l.AuditLogFileName == l.LogFileName || l.AuditLogFileName == l.ErrorLogFileName) {
+		errs = append(errs, errors.New("config: audit_log_file_name must differ from log_file_name and error_log_file_name"))
+	}
+	if l.DisableStdout && !l.hasOutputBesidesStdout() {
+		errs = append(errs, errors.New("config: disable_stdout needs another output, e.g. log_file_name"))
+	}
+	if l.DisableCaller && l.ShortCaller {
+		errs = append(errs, errors.New("config: short_caller has no effect with disable_caller"))
+	}
+	if l.TrimCallerPrefix != "" && (l.DisableCaller || l.ShortCaller) {
+		errs = append(errs, errors.New("config: trim_caller_prefix has no effect with disable_caller or short_caller"))
+	}
+	for i, fs := range l.FileSinks {
+		if fs.FileName == "" {
+			errs = append(errs, fmt.Errorf("config: file_sinks[%d] has no file_name", i))
+		}
+		if fs.Level != "" && !knownLevels[fs.Level] {
+			errs = append(errs, fmt.Errorf("config: unknown level %q in file_sinks[%d]", fs.Level, i))
+		}
+		if fs.StacktraceLevel != "" && !knownLevels[fs.StacktraceLevel] {
+			errs = append(errs, fmt.Errorf("config: unknown stacktrace_level %q in file_sinks[%d]", fs.StacktraceLevel, i))
+		}
+	}
+	for i, h := range l.QuietHours {
+		for _, bound := range []string{h.Start, h.End} {
+			if _, err := time.Parse(QuietHoursLayout, bound); err != nil {
+				errs = append(errs, fmt.Errorf("config: quiet_hours[%d] bound %q must be a time like \"22:00\"", i, bound))
+			}
+		}
+		switch h.Level {
+		case "", "TRACE", "DEBUG", "INFO", "WARN", "ERROR":
+		case "DPANIC", "PANIC", "FATAL":
+			errs = append(errs, fmt.Errorf("config: level %q in quiet_hours[%d] is above ERROR, which always passes", h.Level, i))
+		default:
+			errs = append(errs, fmt.Errorf("config: unknown level %q in quiet_hours[%d]", h.Level, i))
+		}
+	}
+	for i, rule := range l.MaskRules {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("config: invalid pattern in mask_rules[%d]: %w", i, err))
+			continue
+		}
+		// a pattern matching nothing would insert the replacement everywhere
+		if re.MatchString("") {
+			errs = append(errs, fmt.Errorf("config: pattern %q in mask_rules[%d] matches the empty string", rule.Pattern, i))
+		}
+	}
+	for name, code := range l.LevelColors {
+		if !knownLevels[name] {
+			errs = append(errs, fmt.Errorf("config: unknown level %q in level_colors", name))
+		}
+		if !ValidColor(code) {
+			errs = append(errs, fmt.Errorf("config: invalid color %q for %s in level_colors", code, name))
+		}
+	}
+	for name, outputs := range l.LevelOutputs {
+		if !knownLevels[name] {
+			errs = append(errs, fmt.Errorf("config: unknown level %q in level_outputs", name))
+		}
+		if len(outputs) == 0 {
+			errs = append(errs, fmt.Errorf("config: level_outputs %q has no outputs", name))
+		}
+	}
+
+	return errors.Join(errs...)
+}
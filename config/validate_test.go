@@ -0,0 +1,146 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidColor(t *testing.T) {
+	for code, want := range map[string]bool{
+		"33":       true,
+		"1;31":     true,
+		"38;5;208": true,
+		"":         false,
+		"orange":   false,
+		"33;":      false,
+		"1234":     false,
+		"\x1b[33m": false,
+	} {
+		if got := ValidColor(code); got != want {
+			t.Errorf("ValidColor(%q) = %v, want %v", code, got, want)
+		}
+	}
+}
+
+func TestValidateLevelColors(t *testing.T) {
+	if err := (Logger{LevelColors: map[string]string{"WARN": "38;5;208"}}).WithDefaults().Validate(); err != nil {
+		t.Errorf("Validate() = %v for a valid color", err)
+	}
+	if err := (Logger{LevelColors: map[string]string{"WARN": "orange"}}).WithDefaults().Validate(); err == nil {
+		t.Error("Validate() accepted an invalid color")
+	}
+	if err := (Logger{LevelColors: map[string]string{"LOUD": "31"}}).WithDefaults().Validate(); err == nil {
+		t.Error("Validate() accepted an unknown level")
+	}
+}
+
+func TestWithDefaults(t *testing.T) {
+	l := Logger{}.WithDefaults()
+	if l.LoggingLevel != DefaultLoggingLevel || l.Encoding != DefaultEncoding ||
+		l.LogFileSizeCappingInMBs != DefaultLogFileSizeCappingInMBs || l.MaxLogBackupsCount != DefaultMaxLogBackupsCount ||
+		l.MaxOldLogRetentionInDays != DefaultMaxOldLogRetentionInDays {
+		t.Errorf("WithDefaults() = %+v", l)
+	}
+
+	set := Logger{LoggingLevel: "WARN", Encoding: "logfmt", LogFileSizeCappingInMBs: 5, MaxLogBackupsCount: -1}.WithDefaults()
+	if set.LoggingLevel != "WARN" || set.Encoding != "logfmt" || set.LogFileSizeCappingInMBs != 5 {
+		t.Errorf("WithDefaults() replaced set values: %+v", set)
+	}
+	if set.MaxLogBackupsCount != DefaultMaxLogBackupsCount {
+		t.Errorf("max_log_backups_count = %d, want the default for a negative value", set.MaxLogBackupsCount)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (Logger{}).Validate(); err != nil {
+		t.Errorf("Validate() = %v for the zero config", err)
+	}
+	if err := (Logger{}).WithDefaults().Validate(); err != nil {
+		t.Errorf("Validate() = %v for the defaults", err)
+	}
+
+	for _, tt := range []struct {
+		conf Logger
+		want string
+	}{
+		{Logger{LoggingLevel: "LOUD"}, `unknown logging_level "LOUD"`},
+		{Logger{Encoding: "xml"}, `unknown encoding "xml"`},
+		{Logger{LogFileSizeCappingInMBs: -1}, "log_file_size_capping_in_mbs must not be negative"},
+		{Logger{MaxLogBackupsCount: -1}, "max_log_backups_count must not be negative"},
+		{Logger{MaxOldLogRetentionInDays: -1}, "max_old_log_retention_in_days must not be negative"},
+		{Logger{StatsInterval: -1}, "stats_interval must not be negative"},
+		{Logger{SlowStackThreshold: -1}, "slow_stack_threshold must not be negative"},
+		{Logger{AsyncBufferSize: -1}, "async_buffer_size must not be negative"},
+		{Logger{CircularFileSize: -1}, "circular_file_size must not be negative"},
+		{Logger{MaxFieldLength: -1}, "max_field_length must not be negative"},
+		{Logger{StderrLevel: "LOUD"}, `unknown stderr_level "LOUD"`},
+		{Logger{ErrorsToStderr: true, LevelOutputs: map[string][]string{"INFO": {"stdout"}}}, "errors_to_stderr has no effect"},
+		{Logger{LogFileMode: "rw-r--r--"}, "log_file_mode must be octal permissions"},
+		{Logger{LogFileMode: "1777"}, "log_file_mode must be octal permissions"},
+		{Logger{CurrentLogSymlink: "current.log"}, "current_log_symlink needs log_file_name"},
+		{Logger{TrimCallerPrefix: "/src/", ShortCaller: true}, "trim_caller_prefix has no effect"},
+		{Logger{LevelEncoding: "upper"}, `unknown level_encoding "upper"`},
+		{Logger{PrettyPrint: true, Encoding: "console"}, "pretty_print needs the json encoding"},
+		{Logger{CompactConsole: true}, "compact_console needs the console encoding"},
+		{Logger{CompactConsole: true, Encoding: "json"}, "compact_console needs the console encoding"},
+		{Logger{LogFileName: "app.log", AuditLogFileName: "app.log"}, "audit_log_file_name must differ"},
+		{Logger{MaskRules: []MaskRule{{Pattern: "(card"}}}, "invalid pattern in mask_rules[0]"},
+		{Logger{MaskRules: []MaskRule{{Pattern: `\d{4}`}, {Pattern: "x*"}}}, `pattern "x*" in mask_rules[1] matches the empty string`},
+		{Logger{RecentEntries: -1}, "recent_entries must not be negative"},
+		{Logger{RecentEntriesLevel: "LOUD"}, `unknown recent_entries_level "LOUD"`},
+		{Logger{Sampling: &Sampling{}}, "sampling needs initial or levels"},
+		{Logger{Sampling: &Sampling{SamplingRule: SamplingRule{Thereafter: 10}}}, "sampling.initial must be at least 1"},
+		{Logger{Sampling: &Sampling{SamplingRule: SamplingRule{Initial: 1, Thereafter: -1}}}, "sampling.thereafter must not be negative"},
+		{Logger{Sampling: &Sampling{SamplingRule: SamplingRule{Initial: 1}, Tick: -1}}, "sampling.tick must not be negative"},
+		{Logger{Sampling: &Sampling{SamplingRule: SamplingRule{Initial: 1}, ReportInterval: -1}}, "sampling.report_interval must not be negative"},
+		{Logger{ErrorDemoteThreshold: -1}, "error_demote_threshold must not be negative"},
+		{Logger{ErrorDemoteWindow: -1}, "error_demote_window must not be negative"},
+		{Logger{DualEncoding: true, LevelOutputs: map[string][]string{"INFO": {"stdout"}}}, "dual_encoding has no effect"},
+		{Logger{Sampling: &Sampling{Levels: map[string]SamplingRule{"LOUD": {Initial: 1}}}}, `unknown level "LOUD" in sampling.levels`},
+		{Logger{Sampling: &Sampling{Levels: map[string]SamplingRule{"DEBUG": {}}}}, "sampling.levels[DEBUG].initial must be at least 1"},
+		{Logger{QuietHours: []QuietHours{{Start: "10pm", End: "06:00"}}}, `quiet_hours[0] bound "10pm" must be a time like "22:00"`},
+		{Logger{QuietHours: []QuietHours{{Start: "22:00", End: "06:00", Level: "FATAL"}}}, `level "FATAL" in quiet_hours[0] is above ERROR`},
+		{Logger{QuietHours: []QuietHours{{Start: "22:00", End: "06:00", Level: "LOUD"}}}, `unknown level "LOUD" in quiet_hours[0]`},
+		{Logger{LineEnding: "cr"}, `line_ending must be "lf" or "crlf", got "cr"`},
+		{Logger{LogFileName: "app.log", ErrorLogFileName: "app.log"}, "error_log_file_name must differ"},
+		{Logger{DisableCaller: true, ShortCaller: true}, "short_caller has no effect"},
+		{Logger{LevelOutputs: map[string][]string{"LOUD": {"stdout"}}}, `unknown level "LOUD" in level_outputs`},
+		{Logger{LevelOutputs: map[string][]string{"INFO": nil}}, `level_outputs "INFO" has no outputs`},
+		{Logger{FileSinks: []FileSink{{Level: "DEBUG"}}}, "file_sinks[0] has no file_name"},
+		{Logger{FileSinks: []FileSink{{FileName: "a.log", Level: "LOUD"}}}, `unknown level "LOUD" in file_sinks[0]`},
+		{Logger{FileSinks: []FileSink{{FileName: "a.log", StacktraceLevel: "LOUD"}}}, `unknown stacktrace_level "LOUD" in file_sinks[0]`},
+	} {
+		err := tt.conf.Validate()
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Validate() = %v, want %q", err, tt.want)
+		}
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	err := Logger{LoggingLevel: "LOUD", Encoding: "xml"}.Validate()
+	if err == nil || !strings.Contains(err.Error(), "logging_level") || !strings.Contains(err.Error(), "encoding") {
+		t.Errorf("Validate() = %v, want both problems", err)
+	}
+}
+
+func TestValidateLoggers(t *testing.T) {
+	valid := Config{Loggers: map[string]Logger{
+		"app":    {LogFileName: "app.log"},
+		"access": {LogFileName: "access.log"},
+	}}
+	if err := valid.ValidateLoggers(); err != nil {
+		t.Errorf("ValidateLoggers() = %v for valid loggers", err)
+	}
+
+	err := Config{Loggers: map[string]Logger{
+		"app":    {LogFileName: "app.log", Encoding: "xml"},
+		"access": {LogFileName: "shared.log"},
+		"audit":  {AuditLogFileName: "shared.log"},
+	}}.ValidateLoggers()
+	for _, want := range []string{`loggers["app"]: config: unknown encoding "xml"`, `loggers "access" and "audit" both write shared.log`} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ValidateLoggers() = %v, want %q", err, want)
+		}
+	}
+}
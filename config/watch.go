@@ -0,0 +1,78 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is how long Watch waits after the last change to the file
+// before reloading it, editors often write a file in several steps.
+const watchDebounce = 100 * time.Millisecond
+
+// Watch calls onChange with the Logger section of the config file at path,
+// loaded with Load, every time the file is written or replaced. A file that
+// fails to load or validate is ignored until its next change. stop ends the
+// watching, onChange is not called once it has returned.
+func Watch(path string, onChange func(Logger)) (stop func(), err error) {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	// the directory is watched so a file replaced by a rename is still seen
+	if err := w.Add(filepath.Dir(path)); err != nil {
+		w.Close()
+		return nil, fmt.Errorf("config: watching %s: %w", path, err)
+	}
+
+	done := make(chan struct{})
+	go watch(w, filepath.Clean(path), onChange, done)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			w.Close()
+			<-done
+		})
+	}, nil
+}
+
+func watch(w *fsnotify.Watcher, path string, onChange func(Logger), done chan struct{}) {
+	defer close(done)
+
+	debounce := time.NewTimer(watchDebounce)
+	debounce.Stop()
+	defer debounce.Stop()
+
+	for {
+		select {
+		case ev, ok := <-w.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(ev.Name) != path || !ev.Has(fsnotify.Write|fsnotify.Create) {
+				continue
+			}
+			if !debounce.Stop() {
+				select {
+				case <-debounce.C:
+				default:
+				}
+			}
+			debounce.Reset(watchDebounce)
+		case _, ok := <-w.Errors:
+			if !ok {
+				return
+			}
+		case <-debounce.C:
+			conf, err := Load(path)
+			if err != nil || conf.Logger.Validate() != nil {
+				continue
+			}
+			onChange(conf.Logger)
+		}
+	}
+}
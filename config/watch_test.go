@@ -0,0 +1,70 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	path := writeConfig(t, "logger:\n  logging_level: INFO\n")
+	changes := make(chan Logger, 10)
+	stop, err := Watch(path, func(l Logger) { changes <- l })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	// configs failing to load or validate are skipped, the next valid one
+	// applied
+	os.WriteFile(path, []byte("logger: [\n"), 0o644)
+	time.Sleep(3 * watchDebounce)
+	os.WriteFile(path, []byte("logger:\n  encoding: xml\n"), 0o644)
+	time.Sleep(3 * watchDebounce)
+	os.WriteFile(path, []byte("logger:\n  logging_level: DEBUG\n"), 0o644)
+	select {
+	case l := <-changes:
+		if l.LoggingLevel != "DEBUG" {
+			t.Errorf("onChange got level %s, want DEBUG", l.LoggingLevel)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("onChange was not called")
+	}
+
+	// replaced by a rename, as editors do
+	tmp := filepath.Join(filepath.Dir(path), "config.yaml.tmp")
+	os.WriteFile(tmp, []byte("logger:\n  logging_level: ERROR\n"), 0o644)
+	os.Rename(tmp, path)
+	select {
+	case l := <-changes:
+		if l.LoggingLevel != "ERROR" {
+			t.Errorf("onChange got level %s, want ERROR", l.LoggingLevel)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("onChange was not called for the renamed file")
+	}
+}
+
+func TestWatchStop(t *testing.T) {
+	path := writeConfig(t, "logger:\n  logging_level: INFO\n")
+	changes := make(chan Logger, 10)
+	stop, err := Watch(path, func(l Logger) { changes <- l })
+	if err != nil {
+		t.Fatal(err)
+	}
+	stop()
+	stop()
+
+	os.WriteFile(path, []byte("logger:\n  logging_level: DEBUG\n"), 0o644)
+	time.Sleep(3 * watchDebounce)
+	if len(changes) != 0 {
+		t.Error("onChange was called after stop")
+	}
+}
+
+func TestWatchMissingDirectory(t *testing.T) {
+	if _, err := Watch(filepath.Join(t.TempDir(), "missing", "config.yaml"), func(Logger) {}); err == nil {
+		t.Error("Watch() = nil error for a missing directory")
+	}
+}
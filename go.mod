@@ -3,8 +3,11 @@ module github.com/dazzling420/go-logger
 go 1.22.5
 
 require (
+	github.com/fsnotify/fsnotify v1.8.0
 	go.uber.org/zap v1.27.0
+	golang.org/x/sys v0.24.0
 	gopkg.in/natefinch/lumberjack.v2 v2.2.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require go.uber.org/multierr v1.10.0 // indirect
@@ -0,0 +1,45 @@
+package logger
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dazzling420/go-logger/config"
+)
+
+func TestAudit(t *testing.T) {
+	dir := t.TempDir()
+	conf := config.Logger{
+		LogFileName:      filepath.Join(dir, "app.log"),
+		ErrorLogFileName: filepath.Join(dir, "error.log"),
+		AuditLogFileName: filepath.Join(dir, "audit.log"),
+		LoggingLevel:     "FATAL",
+		DedupWindow:      time.Hour,
+		DisableStdout:    true,
+	}
+	svc, err := BuildService(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.Errorz("below the level")
+	svc.Audit("user deleted")
+	svc.Audit("user deleted")
+	svc.SetLevel("ERROR")
+	svc.Errorz("failed")
+	svc.Close()
+
+	for name, want := range map[string]string{
+		"app.log":   "user deleted,user deleted,failed",
+		"error.log": "failed",
+		"audit.log": "user deleted,user deleted",
+	} {
+		if got := strings.Join(messages(readEntries(t, filepath.Join(dir, name))), ","); got != want {
+			t.Errorf("%s = %s, want %s", name, got, want)
+		}
+	}
+	if e := readEntries(t, filepath.Join(dir, "audit.log"))[0]; e["level"] != "AUDIT" {
+		t.Errorf("audit entry = %v, want level AUDIT", e)
+	}
+}
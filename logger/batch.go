@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"math/rand/v2"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Batch accumulates the outcomes of processing many items, e.g. the records
+// of an ingest job, and logs them as a single entry on Flush instead of one
+// entry per item. It is safe for concurrent use.
+type Batch struct {
+	s          *standardLogger
+	msg        string
+	maxSamples int
+
+	mu        sync.Mutex
+	succeeded int64
+	failed    int64
+	samples   []batchFailure
+}
+
+// batchFailure is a failed item kept as a sample by a Batch.
+type batchFailure struct {
+	item string
+	err  error
+}
+
+func (f batchFailure) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("item", f.item)
+	if f.err != nil {
+		enc.AddString("error", f.err.Error())
+	}
+	return nil
+}
+
+type batchFailures []batchFailure
+
+func (fs batchFailures) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, f := range fs {
+		if err := enc.AppendObject(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Batch returns a Batch logging msg when flushed, keeping up to maxSamples
+// failed items, sampled uniformly among all failures, to attach to the entry.
+func (s *standardLogger) Batch(msg string, maxSamples int) *Batch {
+	return &Batch{s: s, msg: msg, maxSamples: maxSamples}
+}
+
+// Success records an item processed successfully.
+func (b *Batch) Success() {
+	b.mu.Lock()
+	b.succeeded++
+	b.mu.Unlock()
+}
+
+// Failure records an item that failed with err, item identifying it in the
+// samples.
+func (b *Batch) Failure(item string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failed++
+	// reservoir sampling, every failure has the same chance of being kept
+	if len(b.samples) < b.maxSamples {
+		b.samples = append(b.samples, batchFailure{item: item, err: err})
+	} else if i := rand.Int64N(b.failed); i < int64(b.maxSamples) {
+		b.samples[i] = batchFailure{item: item, err: err}
+	}
+}
+
+// Flush logs the counts recorded since the last Flush, at INFO when every
+// item succeeded and WARN otherwise, with the sampled failures as a
+// "failures" array, and starts counting again. Nothing is logged when no item
+// was recorded.
+func (b *Batch) Flush(fields ...Field) {
+	b.mu.Lock()
+	succeeded, failed, samples := b.succeeded, b.failed, b.samples
+	b.succeeded, b.failed, b.samples = 0, 0, nil
+	b.mu.Unlock()
+
+	if succeeded+failed == 0 {
+		return
+	}
+	batchFields := []Field{
+		zap.Int64("total", succeeded+failed),
+		zap.Int64("succeeded", succeeded),
+		zap.Int64("failed", failed),
+	}
+	if len(samples) > 0 {
+		batchFields = append(batchFields, zap.Array("failures", batchFailures(samples)))
+	}
+	if failed > 0 {
+		b.s.log.Warn(b.msg, append(batchFields, fields...)...)
+	} else {
+		b.s.log.Info(b.msg, append(batchFields, fields...)...)
+	}
+}
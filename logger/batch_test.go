@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestBatch(t *testing.T) {
+	svc, logs := NewTestLogger()
+	b := svc.(*standardLogger).Batch("ingested", 3)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%4 == 0 {
+				b.Failure(fmt.Sprint("record ", i), errors.New("invalid"))
+			} else {
+				b.Success()
+			}
+		}(i)
+	}
+	wg.Wait()
+	b.Flush(zap.String("job", "import"))
+
+	entries := logs.AllUntimed()
+	if len(entries) != 1 {
+		t.Fatalf("logged %d entries, want 1", len(entries))
+	}
+	e := entries[0]
+	f := e.ContextMap()
+	if e.Level != WARN || e.Message != "ingested" || f["job"] != "import" {
+		t.Errorf("logged %s %q %v", LevelName(e.Level), e.Message, f)
+	}
+	if f["total"] != int64(20) || f["succeeded"] != int64(15) || f["failed"] != int64(5) {
+		t.Errorf("counts = %v, want 20 total, 15 succeeded, 5 failed", f)
+	}
+	failures, _ := f["failures"].([]interface{})
+	if len(failures) != 3 {
+		t.Fatalf("failures = %v, want 3 samples", f["failures"])
+	}
+	for _, s := range failures {
+		if sample, _ := s.(map[string]interface{}); sample["error"] != "invalid" {
+			t.Errorf("sample = %v, want the item and its error", s)
+		}
+	}
+}
+
+func TestBatchFlushStartsOver(t *testing.T) {
+	svc, logs := NewTestLogger()
+	b := svc.(*standardLogger).Batch("ingested", 3)
+	b.Flush()
+	if logs.Len() != 0 {
+		t.Fatalf("logged %v for an empty batch", logs.AllUntimed())
+	}
+	b.Failure("a", nil)
+	b.Flush()
+	b.Success()
+	b.Flush()
+
+	entries := logs.AllUntimed()
+	if len(entries) != 2 {
+		t.Fatalf("logged %d entries, want 2", len(entries))
+	}
+	if f := entries[1].ContextMap(); entries[1].Level != INFO || f["total"] != int64(1) || f["failures"] != nil {
+		t.Errorf("second flush = %s %v, want INFO for the one success", LevelName(entries[1].Level), f)
+	}
+}
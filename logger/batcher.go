@@ -0,0 +1,183 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Batching defaults of the shipping cores (Loki, Elasticsearch, ...).
+const (
+	defaultBatchSize     = 100
+	defaultBatchInterval = time.Second
+)
+
+// batchEntry is an entry queued by a batchCore.
+type batchEntry struct {
+	Entry  zapcore.Entry
+	Fields []zapcore.Field // context fields followed by the entry's own
+	Line   []byte          // the entry encoded by the core's encoder, sans line ending
+}
+
+// batcher queues entries and hands them to flush in batches of size, or
+// whatever accumulated when interval elapses, from a background goroutine.
+// When limit is set, entries arriving while limit entries are queued are
+// dropped and counted instead.
+type batcher struct {
+	size  int
+	limit int
+	flush func([]batchEntry) error
+
+	dropped atomic.Uint64
+
+	mu      sync.Mutex
+	pending []batchEntry
+	flushMu sync.Mutex // serializes calls to flush
+
+	kick     chan struct{}
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+func newBatcher(size int, interval time.Duration, flush func([]batchEntry) error) *batcher {
+	b := &batcher{
+		size:  size,
+		flush: flush,
+		kick:  make(chan struct{}, 1),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	go b.run(interval)
+	return b
+}
+
+func (b *batcher) add(e batchEntry) {
+	b.mu.Lock()
+	if b.limit > 0 && len(b.pending) >= b.limit {
+		b.mu.Unlock()
+		b.dropped.Add(1)
+		return
+	}
+	b.pending = append(b.pending, e)
+	full := len(b.pending) >= b.size
+	b.mu.Unlock()
+
+	if full {
+		select {
+		case b.kick <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (b *batcher) run(interval time.Duration) {
+	defer close(b.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-b.kick:
+		case <-b.stop:
+			return
+		}
+		if err := b.Sync(); err != nil {
+			fmt.Fprintf(os.Stderr, "%v write error: %v\n", time.Now(), err)
+		}
+	}
+}
+
+// Sync flushes everything queued so far.
+func (b *batcher) Sync() error {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	var errs []error
+	for {
+		b.mu.Lock()
+		n := len(b.pending)
+		if n > b.size {
+			n = b.size
+		}
+		batch := b.pending[:n:n]
+		b.pending = b.pending[n:]
+		b.mu.Unlock()
+
+		if len(batch) == 0 {
+			break
+		}
+		if err := b.flush(batch); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// Close stops the background flushing and flushes what is left.
+func (b *batcher) Close() error {
+	b.stopOnce.Do(func() { close(b.stop) })
+	<-b.done
+	return b.Sync()
+}
+
+// batchCore encodes entries enabled by its LevelEnabler and queues them on a
+// batcher. It implements io.Closer to flush and stop the batcher.
+type batchCore struct {
+	zapcore.LevelEnabler
+	enc     zapcore.Encoder
+	context []zapcore.Field
+	b       *batcher
+}
+
+func newBatchCore(enc zapcore.Encoder, enab zapcore.LevelEnabler, b *batcher) *batchCore {
+	return &batchCore{LevelEnabler: enab, enc: enc, b: b}
+}
+
+func (c *batchCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	context := make([]zapcore.Field, 0, len(c.context)+len(fields))
+	context = append(append(context, c.context...), fields...)
+	return &batchCore{LevelEnabler: c.LevelEnabler, enc: enc, context: context, b: c.b}
+}
+
+func (c *batchCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *batchCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	line := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+	line = append([]byte(nil), line...)
+	buf.Free()
+
+	all := make([]zapcore.Field, 0, len(c.context)+len(fields))
+	all = append(append(all, c.context...), fields...)
+	c.b.add(batchEntry{Entry: ent, Fields: all, Line: line})
+	return nil
+}
+
+func (c *batchCore) Sync() error {
+	return c.b.Sync()
+}
+
+func (c *batchCore) Close() error {
+	return c.b.Close()
+}
@@ -0,0 +1,109 @@
+package logger
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/dazzling420/go-logger/config"
+)
+
+// newBenchLogger returns a logger at level writing JSON to a file only.
+func newBenchLogger(b *testing.B, level string) *standardLogger {
+	b.Helper()
+	svc, err := BuildService(config.Logger{
+		LogFileName:   filepath.Join(b.TempDir(), "bench.log"),
+		DisableStdout: true,
+		LoggingLevel:  level,
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { svc.Close() })
+	return svc
+}
+
+func BenchmarkInfoz(b *testing.B) {
+	svc := newBenchLogger(b, "INFO")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		svc.Infoz("request served")
+	}
+}
+
+func BenchmarkInfozWithFields(b *testing.B) {
+	svc := newBenchLogger(b, "INFO")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		svc.Infoz("request served", String("path", "/users"), Int("status", 200))
+	}
+}
+
+func BenchmarkErrorz(b *testing.B) {
+	svc := newBenchLogger(b, "INFO")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		svc.Errorz("request failed", Int("status", 500))
+	}
+}
+
+// The sugared methods box their arguments and format the message, compare
+// with BenchmarkInfozWithFields.
+func BenchmarkInfof(b *testing.B) {
+	svc := newBenchLogger(b, "INFO")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		svc.Infof("request served %s %d", "/users", 200)
+	}
+}
+
+func BenchmarkInfo(b *testing.B) {
+	svc := newBenchLogger(b, "INFO")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		svc.Info("request served ", "/users ", 200)
+	}
+}
+
+func BenchmarkDebugzBelowLevel(b *testing.B) {
+	svc := newBenchLogger(b, "INFO")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		svc.Debugz("not written", Int("status", 200))
+	}
+}
+
+func BenchmarkDebugfBelowLevel(b *testing.B) {
+	svc := newBenchLogger(b, "INFO")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		svc.Debugf("not written %s %d", "/users", 200)
+	}
+}
+
+// TestInfozAllocations keeps the structured path as cheap as measured by the
+// benchmarks above: the two allocations left are zap's, capturing the caller
+// and formatting its path.
+func TestInfozAllocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector allocates")
+	}
+	svc, err := BuildService(config.Logger{LogFileName: tempLog(t, "app.log"), DisableStdout: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer svc.Close()
+
+	if n := testing.AllocsPerRun(100, func() { svc.Infoz("request served") }); n > 2 {
+		t.Errorf("Infoz allocates %v times per entry, want at most 2", n)
+	}
+	if n := testing.AllocsPerRun(100, func() { svc.Debugf("not written %d", 200) }); n > 0 {
+		t.Errorf("Debugf below the level allocates %v times, want none", n)
+	}
+}
@@ -0,0 +1,274 @@
+package logger
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	lumberjack "gopkg.in/natefinch/lumberjack.v2"
+)
+
+// errBufwriterClosed is returned by writes to a closed bufwriter.
+var errBufwriterClosed = errors.New("logger: write to closed bufwriter")
+
+// bufwriter hands writes to a background goroutine through a buffered
+// channel, so the caller only waits when the channel is full, or, when it
+// drops on full, never waits and drops the write instead. Writes are reported
+// successful once queued: their errors are lost, and so are the queued entries
+// if the process dies before they are drained.
+type bufwriter struct {
+	queue         chan bufItem
+	out           zapcore.WriteSyncer
+	closeOut      func() error  // closes out once drained, may be nil
+	flushInterval time.Duration // out is synced every interval when set
+	dropOnFull    bool
+	dropped       atomic.Uint64
+	done          chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// bufItem is either bytes to write or, when flushed is set, a request to sync
+// everything queued before it and be sent the result.
+type bufItem struct {
+	p       []byte
+	flushed chan error
+}
+
+// newBufwriter returns a bufwriter queuing up to n writes to out, dropping
+// writes while the queue is full when dropOnFull is set.
+func newBufwriter(n int, out zapcore.WriteSyncer, dropOnFull bool) *bufwriter {
+	bw := &bufwriter{
+		queue:      make(chan bufItem, n),
+		out:        out,
+		dropOnFull: dropOnFull,
+		done:       make(chan struct{}),
+	}
+	go bw.run()
+	return bw
+}
+
+// BufwriterOption configures NewBufwriter.
+type BufwriterOption func(*bufwriterOptions)
+
+type bufwriterOptions struct {
+	gzip          bool
+	flushInterval time.Duration
+	dropOnFull    bool
+}
+
+// WithDropOnFull makes writes to a full NewBufwriter queue return at once,
+// dropping the entry, instead of waiting for room. Dropped reports how many
+// were.
+func WithDropOnFull() BufwriterOption {
+	return func(o *bufwriterOptions) {
+		o.dropOnFull = true
+	}
+}
+
+// WithGzip gzips what NewBufwriter writes to its file, stdout staying
+// readable. The compressed stream is completed every flushInterval, on Sync
+// and on Close, so the file is a series of gzip members that gzip tools read
+// as one; a crash only loses what was written since the last completion.
+// Rotation may cut a member in two, leaving the end of a rotated file and the
+// start of the next unreadable on their own.
+func WithGzip(flushInterval time.Duration) BufwriterOption {
+	return func(o *bufwriterOptions) {
+		o.gzip = true
+		o.flushInterval = flushInterval
+	}
+}
+
+// NewBufwriter returns a bufwriter writing to stdout and to a rotating
+// logFile, queuing up to n writes.
+func NewBufwriter(n int, logFile string, opts ...BufwriterOption) *bufwriter {
+	var o bufwriterOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	logwriter := &lumberjack.Logger{
+		Filename:   logFile,
+		MaxSize:    500, // megabytes
+		MaxBackups: 10,
+		MaxAge:     7, //days
+	}
+	if !o.gzip {
+		return newBufwriter(n, zapcore.NewMultiWriteSyncer(zapcore.AddSync(os.Stdout), zapcore.AddSync(logwriter)), o.dropOnFull)
+	}
+
+	gz := newGzipSink(logwriter)
+	bw := &bufwriter{
+		queue:         make(chan bufItem, n),
+		out:           zapcore.NewMultiWriteSyncer(zapcore.AddSync(os.Stdout), gz),
+		closeOut:      gz.Close,
+		flushInterval: o.flushInterval,
+		dropOnFull:    o.dropOnFull,
+		done:          make(chan struct{}),
+	}
+	go bw.run()
+	return bw
+}
+
+func (bw *bufwriter) run() {
+	defer close(bw.done)
+
+	var tick <-chan time.Time
+	if bw.flushInterval > 0 {
+		ticker := time.NewTicker(bw.flushInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case item, ok := <-bw.queue:
+			if !ok {
+				return
+			}
+			if item.flushed != nil {
+				item.flushed <- bw.out.Sync()
+				continue
+			}
+			bw.out.Write(item.p)
+		case <-tick:
+			bw.out.Sync()
+		}
+	}
+}
+
+// Write queues a copy of p, zap reuses its buffers once Write returns.
+func (bw *bufwriter) Write(p []byte) (int, error) {
+	bw.mu.RLock()
+	defer bw.mu.RUnlock()
+	if bw.closed {
+		return 0, errBufwriterClosed
+	}
+	item := bufItem{p: append([]byte(nil), p...)}
+	if !bw.dropOnFull {
+		bw.queue <- item
+		return len(p), nil
+	}
+	select {
+	case bw.queue <- item:
+	default:
+		bw.dropped.Add(1)
+	}
+	return len(p), nil
+}
+
+// Dropped returns the number of writes dropped because the queue was full.
+func (bw *bufwriter) Dropped() uint64 {
+	return bw.dropped.Load()
+}
+
+// Sync waits for the writes queued so far to be written and synced.
+func (bw *bufwriter) Sync() error {
+	bw.mu.RLock()
+	if bw.closed {
+		bw.mu.RUnlock()
+		return nil
+	}
+	flushed := make(chan error, 1)
+	bw.queue <- bufItem{flushed: flushed}
+	bw.mu.RUnlock()
+	return <-flushed
+}
+
+// Close drains the queue, stops the background goroutine and closes the
+// output when it needs to. Later writes fail with an error.
+func (bw *bufwriter) Close() error {
+	bw.mu.Lock()
+	if bw.closed {
+		bw.mu.Unlock()
+		return nil
+	}
+	bw.closed = true
+	close(bw.queue)
+	bw.mu.Unlock()
+
+	<-bw.done
+	err := bw.out.Sync()
+	if bw.closeOut != nil {
+		err = errors.Join(err, bw.closeOut())
+	}
+	return err
+}
+
+// dropReportInterval is how often NewService reports the entries dropped by
+// full async buffers.
+const dropReportInterval = time.Minute
+
+// reportDropped logs a warning every interval in which dropped, the running
+// count of dropped entries, went up. The returned stop function ends the
+// reporting.
+func reportDropped(log *zap.Logger, dropped func() uint64, interval time.Duration) func() error {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		var last uint64
+		for {
+			select {
+			case <-ticker.C:
+				n := dropped()
+				if n < last { // counting restarted with a new log file
+					last = 0
+				}
+				if n > last {
+					log.Warn("Dropped log entries, the async buffer was full!", zap.Uint64("dropped", n-last))
+					last = n
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() error {
+		once.Do(func() { close(stop) })
+		<-done
+		return nil
+	}
+}
+
+// gzipSink compresses writes to w, completing the gzip member written so far
+// on Sync. It is only used from the bufwriter goroutine.
+type gzipSink struct {
+	w     io.WriteCloser
+	gz    *gzip.Writer
+	dirty bool // written since the member was last completed
+}
+
+func newGzipSink(w io.WriteCloser) *gzipSink {
+	return &gzipSink{w: w, gz: gzip.NewWriter(w)}
+}
+
+func (s *gzipSink) Write(p []byte) (int, error) {
+	s.dirty = true
+	return s.gz.Write(p)
+}
+
+func (s *gzipSink) Sync() error {
+	if !s.dirty {
+		return nil
+	}
+	s.dirty = false
+	err := s.gz.Close()
+	s.gz.Reset(s.w)
+	return err
+}
+
+func (s *gzipSink) Close() error {
+	return errors.Join(s.Sync(), s.w.Close())
+}
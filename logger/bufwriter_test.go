@@ -0,0 +1,193 @@
+package logger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// blockingSink records writes, each waiting for release to be closed first.
+type blockingSink struct {
+	release chan struct{}
+	started chan struct{}
+
+	mu     sync.Mutex
+	writes []string
+}
+
+func newBlockingSink() *blockingSink {
+	return &blockingSink{release: make(chan struct{}), started: make(chan struct{}, 1)}
+}
+
+func (s *blockingSink) Write(p []byte) (int, error) {
+	select {
+	case s.started <- struct{}{}:
+	default:
+	}
+	<-s.release
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.writes = append(s.writes, string(p))
+	return len(p), nil
+}
+
+func (s *blockingSink) Sync() error { return nil }
+
+func TestAsyncBuffer(t *testing.T) {
+	svc, path := newFileService(t, config.Logger{AsyncBuffer: true, AsyncBufferSize: 8})
+	for i := 0; i < 20; i++ {
+		svc.Infoz("queued")
+	}
+	if err := svc.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if got := readEntries(t, path); len(got) != 20 {
+		t.Errorf("wrote %d entries after Sync, want 20", len(got))
+	}
+	svc.Infoz("last")
+	svc.Close()
+	if got := messages(readEntries(t, path)); len(got) != 21 || got[20] != "last" {
+		t.Errorf("wrote %d entries after Close, want 21", len(got))
+	}
+}
+
+func TestBufwriterDropOnFull(t *testing.T) {
+	out := newBlockingSink()
+	bw := newBufwriter(2, out, true)
+	bw.Write([]byte("taken"))
+	<-out.started
+	for _, p := range []string{"one", "two", "dropped", "dropped"} {
+		if n, err := bw.Write([]byte(p)); n != len(p) || err != nil {
+			t.Errorf("Write(%q) = %d, %v", p, n, err)
+		}
+	}
+	if got := bw.Dropped(); got != 2 {
+		t.Errorf("Dropped() = %d, want 2", got)
+	}
+
+	close(out.release)
+	if err := bw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(out.writes, ","); got != "taken,one,two" {
+		t.Errorf("wrote %s, want taken,one,two", got)
+	}
+	if _, err := bw.Write([]byte("late")); err != errBufwriterClosed {
+		t.Errorf("Write after Close = %v, want %v", err, errBufwriterClosed)
+	}
+}
+
+func TestBufwriterCopiesWrites(t *testing.T) {
+	out := newBlockingSink()
+	bw := newBufwriter(4, out, false)
+	p := []byte("first")
+	bw.Write(p)
+	copy(p, "reuse")
+	close(out.release)
+	bw.Close()
+
+	if len(out.writes) != 1 || out.writes[0] != "first" {
+		t.Errorf("wrote %q, want the bytes as they were written", out.writes)
+	}
+}
+
+func TestReportDropped(t *testing.T) {
+	core, logs := observer.New(WARN)
+	var mu sync.Mutex
+	var dropped uint64
+	stop := reportDropped(zap.New(core), func() uint64 {
+		mu.Lock()
+		defer mu.Unlock()
+		return dropped
+	}, 10*time.Millisecond)
+
+	mu.Lock()
+	dropped = 3
+	mu.Unlock()
+	deadline := time.Now().Add(5 * time.Second)
+	for logs.Len() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	time.Sleep(30 * time.Millisecond) // no new drops, no new warning
+	stop()
+
+	entries := logs.AllUntimed()
+	if len(entries) != 1 || entries[0].ContextMap()["dropped"] != uint64(3) {
+		t.Errorf("warnings = %v, want one for the 3 dropped entries", entries)
+	}
+}
+
+func TestNewBufwriterWithGzip(t *testing.T) {
+	path := tempLog(t, "app.log.gz")
+	stdout := captureStdout(t, func() {
+		bw := NewBufwriter(8, path, WithGzip(time.Hour))
+		// syncing the captured stdout, a pipe, fails
+		bw.Write([]byte("first\n"))
+		bw.Sync()
+		bw.Write([]byte("second\n"))
+		bw.Close()
+	})
+	if stdout != "first\nsecond\n" {
+		t.Errorf("stdout = %q, want it uncompressed", stdout)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "first\nsecond\n" {
+		t.Errorf("file = %q, want both members", got)
+	}
+}
+
+func TestGzipSinkCompletesAMemberOnSync(t *testing.T) {
+	var buf closingBuffer
+	gz := newGzipSink(&buf)
+	gz.Write([]byte("one"))
+	gz.Sync()
+	n := buf.Len()
+	gz.Sync() // nothing written since, no empty member
+	if buf.Len() != n {
+		t.Errorf("Sync without writes grew the output from %d to %d bytes", n, buf.Len())
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := io.ReadAll(zr); err != nil || string(got) != "one" {
+		t.Errorf("read %q, %v after Sync, want one", got, err)
+	}
+	gz.Write([]byte("two"))
+	if err := gz.Close(); err != nil || !buf.closed {
+		t.Errorf("Close() = %v, closed %v", err, buf.closed)
+	}
+}
+
+// closingBuffer is a bytes.Buffer recording whether it was closed.
+type closingBuffer struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (b *closingBuffer) Close() error {
+	b.closed = true
+	return nil
+}
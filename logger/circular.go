@@ -0,0 +1,149 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// A circular file starts with a header holding circularMagic, the size of its
+// data region and the number of bytes ever written, followed by the data
+// region written round robin.
+const (
+	circularMagic      = "GLCIRC01"
+	circularHeaderSize = len(circularMagic) + 16
+)
+
+// circularFile is a WriteSyncer keeping the last size bytes written to it in
+// a file of fixed size, overwriting the oldest data once full. The file is
+// opened on the first write.
+type circularFile struct {
+	path string
+	size int64
+
+	mu      sync.Mutex
+	f       *os.File
+	written int64 // bytes ever written, the next write goes at written % size
+}
+
+func newCircularFile(path string, size int) *circularFile {
+	return &circularFile{path: path, size: int64(size)}
+}
+
+// open opens the file, resuming after its last write when it was written with
+// the same size and starting over otherwise.
+func (c *circularFile) open() error {
+	f, err := os.OpenFile(c.path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	header := make([]byte, circularHeaderSize)
+	if _, err := f.ReadAt(header, 0); err == nil && string(header[:len(circularMagic)]) == circularMagic &&
+		int64(binary.BigEndian.Uint64(header[len(circularMagic):])) == c.size {
+		c.written = int64(binary.BigEndian.Uint64(header[len(circularMagic)+8:]))
+	} else {
+		c.written = 0
+		if err := f.Truncate(0); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	c.f = f
+	return c.writeHeader()
+}
+
+func (c *circularFile) writeHeader() error {
+	header := make([]byte, circularHeaderSize)
+	copy(header, circularMagic)
+	binary.BigEndian.PutUint64(header[len(circularMagic):], uint64(c.size))
+	binary.BigEndian.PutUint64(header[len(circularMagic)+8:], uint64(c.written))
+	_, err := c.f.WriteAt(header, 0)
+	return err
+}
+
+func (c *circularFile) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.f == nil {
+		if err := c.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := len(p)
+	c.written += int64(n)
+	// only the tail of a write longer than the file survives anyway
+	if int64(len(p)) > c.size {
+		p = p[int64(len(p))-c.size:]
+	}
+	for len(p) > 0 {
+		off := (c.written - int64(len(p))) % c.size
+		chunk := p
+		if rest := c.size - off; int64(len(chunk)) > rest {
+			chunk = chunk[:rest]
+		}
+		if _, err := c.f.WriteAt(chunk, int64(circularHeaderSize)+off); err != nil {
+			return 0, err
+		}
+		p = p[len(chunk):]
+	}
+	// the header is written last so it never counts data that wasn't written
+	if err := c.writeHeader(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (c *circularFile) Sync() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.f == nil {
+		return nil
+	}
+	return c.f.Sync()
+}
+
+func (c *circularFile) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.f == nil {
+		return nil
+	}
+	err := c.f.Close()
+	c.f = nil
+	return err
+}
+
+// ReadCircularFile returns the content of a file written with
+// CircularFileSize, oldest first. Once the file has wrapped around, the
+// partly overwritten oldest line is left out.
+func ReadCircularFile(path string) ([]byte, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < circularHeaderSize || string(b[:len(circularMagic)]) != circularMagic {
+		return nil, fmt.Errorf("logger: %s is not a circular log file", path)
+	}
+	size := int64(binary.BigEndian.Uint64(b[len(circularMagic):]))
+	written := int64(binary.BigEndian.Uint64(b[len(circularMagic)+8:]))
+	data := b[circularHeaderSize:]
+
+	if written <= size {
+		if int64(len(data)) < written {
+			return nil, fmt.Errorf("logger: circular log file %s is truncated: %w", path, io.ErrUnexpectedEOF)
+		}
+		return data[:written], nil
+	}
+	if int64(len(data)) < size {
+		return nil, fmt.Errorf("logger: circular log file %s is truncated: %w", path, io.ErrUnexpectedEOF)
+	}
+
+	off := written % size
+	out := make([]byte, 0, size)
+	out = append(append(out, data[off:size]...), data[:off]...)
+	return out[bytes.IndexByte(out, '\n')+1:], nil
+}
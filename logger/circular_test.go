@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/dazzling420/go-logger/config"
+)
+
+func TestCircularFile(t *testing.T) {
+	path := tempLog(t, "app.log")
+	c := newCircularFile(path, 16)
+	c.Write([]byte("one\n"))
+	c.Write([]byte("two\n"))
+	got, err := ReadCircularFile(path)
+	if err != nil || string(got) != "one\ntwo\n" {
+		t.Fatalf("ReadCircularFile() = %q, %v before wrapping around", got, err)
+	}
+
+	c.Write([]byte("three\n"))
+	c.Write([]byte("four\n"))
+	got, err = ReadCircularFile(path)
+	if err != nil || string(got) != "two\nthree\nfour\n" {
+		t.Errorf("ReadCircularFile() = %q, %v, want the whole lines kept", got, err)
+	}
+	c.Close()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != int64(circularHeaderSize+16) {
+		t.Errorf("file size = %d, want %d", info.Size(), circularHeaderSize+16)
+	}
+}
+
+func TestCircularFileWriteLongerThanTheFile(t *testing.T) {
+	path := tempLog(t, "app.log")
+	c := newCircularFile(path, 8)
+	if n, err := c.Write([]byte("0123456789\nabcdef\n")); n != 18 || err != nil {
+		t.Fatalf("Write() = %d, %v", n, err)
+	}
+	c.Close()
+
+	if got, err := ReadCircularFile(path); err != nil || string(got) != "abcdef\n" {
+		t.Errorf("ReadCircularFile() = %q, %v, want the last line", got, err)
+	}
+}
+
+func TestCircularFileResumes(t *testing.T) {
+	path := tempLog(t, "app.log")
+	c := newCircularFile(path, 32)
+	c.Write([]byte("first run\n"))
+	c.Close()
+	c = newCircularFile(path, 32)
+	c.Write([]byte("second run\n"))
+	c.Close()
+	if got, _ := ReadCircularFile(path); string(got) != "first run\nsecond run\n" {
+		t.Errorf("ReadCircularFile() = %q, want both runs", got)
+	}
+
+	// another size starts over
+	c = newCircularFile(path, 64)
+	c.Write([]byte("resized\n"))
+	c.Close()
+	if got, _ := ReadCircularFile(path); string(got) != "resized\n" {
+		t.Errorf("ReadCircularFile() = %q after a resize, want the new run alone", got)
+	}
+}
+
+func TestReadCircularFileRejectsOtherFiles(t *testing.T) {
+	path := tempLog(t, "app.log")
+	os.WriteFile(path, []byte(`{"message":"plain"}`+"\n"), 0o644)
+	if _, err := ReadCircularFile(path); err == nil {
+		t.Error("ReadCircularFile() read a plain log file")
+	}
+}
+
+func TestCircularFileSize(t *testing.T) {
+	svc, path := newFileService(t, config.Logger{CircularFileSize: 1024})
+	for i := 0; i < 100; i++ {
+		svc.Infoz(fmt.Sprintf("entry %d", i))
+	}
+	svc.Close()
+
+	data, err := ReadCircularFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var last string
+	n := 0
+	for sc := bufio.NewScanner(bytes.NewReader(data)); sc.Scan(); n++ {
+		var entry map[string]interface{}
+		if err := json.Unmarshal(sc.Bytes(), &entry); err != nil {
+			t.Fatalf("line %q: %v", sc.Text(), err)
+		}
+		last, _ = entry["message"].(string)
+	}
+	if n == 0 || n == 100 || last != "entry 99" {
+		t.Errorf("read %d entries ending with %q, want the last ones", n, last)
+	}
+}
@@ -0,0 +1,171 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Limits of a PutLogEvents call.
+const (
+	cloudWatchMaxBatchEvents = 10000
+	cloudWatchMaxBatchBytes  = 1048576
+	cloudWatchMaxBatchSpan   = 24 * time.Hour
+	cloudWatchEventOverhead  = 26 // bytes counted per event on top of its message
+	cloudWatchMaxEventBytes  = 262144 - cloudWatchEventOverhead
+)
+
+// cloudWatchBufferSize is the number of entries a CloudWatchCore queues before
+// it starts dropping them.
+const cloudWatchBufferSize = 10000
+
+// ErrCloudWatchAlreadyExists is returned, possibly wrapped, by a
+// CloudWatchLogsClient creating a log group or stream that already exists.
+var ErrCloudWatchAlreadyExists = errors.New("cloudwatch: resource already exists")
+
+// CloudWatchSequenceTokenError is returned by a CloudWatchLogsClient when
+// PutLogEvents was given a stale sequence token (InvalidSequenceTokenException)
+// or the events were already accepted (DataAlreadyAcceptedException).
+type CloudWatchSequenceTokenError struct {
+	Expected        string // the sequence token the next call has to carry
+	AlreadyAccepted bool
+}
+
+func (e *CloudWatchSequenceTokenError) Error() string {
+	if e.AlreadyAccepted {
+		return "cloudwatch: the log events were already accepted"
+	}
+	return "cloudwatch: invalid sequence token, expected " + e.Expected
+}
+
+// CloudWatchEvent is a log event ready to be put to CloudWatch Logs.
+type CloudWatchEvent struct {
+	Timestamp time.Time
+	Message   string // the JSON encoded entry
+}
+
+// CloudWatchLogsClient calls the CloudWatch Logs API. It is the only part of
+// an AWS client a CloudWatchCore depends on, NewCloudWatchCore provides one
+// signing its requests itself.
+type CloudWatchLogsClient interface {
+	CreateLogGroup(ctx context.Context, group string) error
+	CreateLogStream(ctx context.Context, group, stream string) error
+	// PutLogEvents puts events, in chronological order, to the stream and
+	// returns the sequence token of the next call. sequenceToken is empty for
+	// the first call.
+	PutLogEvents(ctx context.Context, group, stream string, events []CloudWatchEvent, sequenceToken string) (string, error)
+}
+
+// CloudWatchCore puts entries to a CloudWatch Logs stream. Use Close to put
+// whatever is still buffered.
+type CloudWatchCore struct {
+	*batchCore
+}
+
+// NewCloudWatchClientCore returns a core putting entries at or above minLevel
+// to the log stream of group through client, one JSON event per entry. The
+// group and stream are created when missing before the first events are put.
+// Entries are put once 100 of them are buffered or every second, split so
+// that each call stays within the count, size and time span limits of
+// PutLogEvents; entries larger than an event may be are truncated. A stale
+// sequence token is replaced by the expected one and the call retried. Once
+// the buffer is full further entries are dropped and counted, see Dropped.
+func NewCloudWatchClientCore(client CloudWatchLogsClient, group, stream string, minLevel zapcore.Level) *CloudWatchCore {
+	// flush calls are serialized by the batcher, so ready and token need no
+	// locking
+	var ready bool
+	var token string
+
+	put := func(ctx context.Context, events []CloudWatchEvent) error {
+		next, err := client.PutLogEvents(ctx, group, stream, events, token)
+		var tokenErr *CloudWatchSequenceTokenError
+		if errors.As(err, &tokenErr) {
+			token = tokenErr.Expected
+			if tokenErr.AlreadyAccepted {
+				return nil
+			}
+			next, err = client.PutLogEvents(ctx, group, stream, events, token)
+			if errors.As(err, &tokenErr) {
+				token = tokenErr.Expected
+			}
+		}
+		if err != nil {
+			return err
+		}
+		token = next
+		return nil
+	}
+
+	flush := func(batch []batchEntry) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if !ready {
+			if err := createCloudWatchStream(ctx, client, group, stream); err != nil {
+				return err
+			}
+			ready = true
+		}
+
+		var errs []error
+		for _, events := range cloudWatchBatches(batch) {
+			if err := put(ctx, events); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}
+
+	b := newBatcher(defaultBatchSize, defaultBatchInterval, flush)
+	b.limit = cloudWatchBufferSize
+	return &CloudWatchCore{newBatchCore(zapcore.NewJSONEncoder(newEncoderConfig()), minLevel, b)}
+}
+
+// Dropped returns the number of entries dropped because the buffer was full.
+func (c *CloudWatchCore) Dropped() uint64 {
+	return c.b.dropped.Load()
+}
+
+func createCloudWatchStream(ctx context.Context, client CloudWatchLogsClient, group, stream string) error {
+	if err := client.CreateLogGroup(ctx, group); err != nil && !errors.Is(err, ErrCloudWatchAlreadyExists) {
+		return err
+	}
+	if err := client.CreateLogStream(ctx, group, stream); err != nil && !errors.Is(err, ErrCloudWatchAlreadyExists) {
+		return err
+	}
+	return nil
+}
+
+// cloudWatchBatches turns batch into chronologically ordered events split
+// into batches PutLogEvents accepts.
+func cloudWatchBatches(batch []batchEntry) [][]CloudWatchEvent {
+	events := make([]CloudWatchEvent, len(batch))
+	for i, e := range batch {
+		msg := string(e.Line)
+		if len(msg) > cloudWatchMaxEventBytes {
+			msg = strings.ToValidUTF8(msg[:cloudWatchMaxEventBytes], "")
+		}
+		events[i] = CloudWatchEvent{Timestamp: e.Entry.Time, Message: msg}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Timestamp.Before(events[j].Timestamp) })
+
+	var batches [][]CloudWatchEvent
+	start, size := 0, 0
+	for i, ev := range events {
+		n := len(ev.Message) + cloudWatchEventOverhead
+		if i > start && (i-start >= cloudWatchMaxBatchEvents || size+n > cloudWatchMaxBatchBytes ||
+			ev.Timestamp.Sub(events[start].Timestamp) >= cloudWatchMaxBatchSpan) {
+			batches = append(batches, events[start:i])
+			start, size = i, 0
+		}
+		size += n
+	}
+	if start < len(events) {
+		batches = append(batches, events[start:])
+	}
+	return batches
+}
@@ -0,0 +1,313 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// The endpoints credentials and the region are looked up at when they
+// aren't set in the environment.
+const (
+	ecsCredentialsEndpoint = "http://169.254.170.2"
+	imdsEndpoint           = "http://169.254.169.254"
+)
+
+// NewCloudWatchCore returns a core putting entries at or above minLevel to the
+// log stream of group, see NewCloudWatchClientCore. Requests are signed with
+// the credentials of AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and
+// AWS_SESSION_TOKEN when set, otherwise those of the ECS task role or EC2
+// instance profile. The region is AWS_REGION or AWS_DEFAULT_REGION, otherwise
+// that of the EC2 instance. Errors, e.g. missing credentials, surface when the
+// entries are put.
+func NewCloudWatchCore(group, stream string, minLevel zapcore.Level) *CloudWatchCore {
+	return NewCloudWatchClientCore(newAWSLogsClient(), group, stream, minLevel)
+}
+
+type awsCredentials struct {
+	AccessKeyID     string    `json:"AccessKeyId"`
+	SecretAccessKey string    `json:"SecretAccessKey"`
+	Token           string    `json:"Token"`
+	Expiration      time.Time `json:"Expiration"`
+}
+
+// awsLogsClient calls the CloudWatch Logs JSON API with requests signed with
+// AWS Signature Version 4.
+type awsLogsClient struct {
+	http *http.Client
+
+	mu     sync.Mutex
+	region string
+	creds  awsCredentials
+}
+
+func newAWSLogsClient() *awsLogsClient {
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	return &awsLogsClient{http: &http.Client{Timeout: 10 * time.Second}, region: region}
+}
+
+func (c *awsLogsClient) CreateLogGroup(ctx context.Context, group string) error {
+	return c.call(ctx, "CreateLogGroup", map[string]string{"logGroupName": group}, nil)
+}
+
+func (c *awsLogsClient) CreateLogStream(ctx context.Context, group, stream string) error {
+	return c.call(ctx, "CreateLogStream", map[string]string{"logGroupName": group, "logStreamName": stream}, nil)
+}
+
+func (c *awsLogsClient) PutLogEvents(ctx context.Context, group, stream string, events []CloudWatchEvent, sequenceToken string) (string, error) {
+	type logEvent struct {
+		Timestamp int64  `json:"timestamp"`
+		Message   string `json:"message"`
+	}
+	input := struct {
+		LogGroupName  string     `json:"logGroupName"`
+		LogStreamName string     `json:"logStreamName"`
+		LogEvents     []logEvent `json:"logEvents"`
+		SequenceToken string     `json:"sequenceToken,omitempty"`
+	}{LogGroupName: group, LogStreamName: stream, SequenceToken: sequenceToken}
+	for _, ev := range events {
+		input.LogEvents = append(input.LogEvents, logEvent{ev.Timestamp.UnixMilli(), ev.Message})
+	}
+
+	var output struct {
+		NextSequenceToken string `json:"nextSequenceToken"`
+	}
+	err := c.call(ctx, "PutLogEvents", input, &output)
+	return output.NextSequenceToken, err
+}
+
+// call invokes action with input and decodes the response into output unless
+// nil. Errors of the API are turned into ErrCloudWatchAlreadyExists and
+// CloudWatchSequenceTokenError where they apply.
+func (c *awsLogsClient) call(ctx context.Context, action string, input, output interface{}) error {
+	region, creds, err := c.credentials(ctx)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	host := "logs." + region + ".amazonaws.com"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://"+host+"/", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "Logs_20140328."+action)
+	signAWSRequest(req, host, body, region, "logs", creds, time.Now())
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return awsLogsError(action, resp.Status, respBody)
+	}
+	if output == nil {
+		return nil
+	}
+	return json.Unmarshal(respBody, output)
+}
+
+func awsLogsError(action, status string, body []byte) error {
+	var apiErr struct {
+		Type                  string `json:"__type"`
+		Message               string `json:"message"`
+		ExpectedSequenceToken string `json:"expectedSequenceToken"`
+	}
+	json.Unmarshal(body, &apiErr)
+	// the type may be prefixed with a namespace, e.g. "com.amazonaws...#"
+	typ := apiErr.Type[strings.LastIndex(apiErr.Type, "#")+1:]
+	switch typ {
+	case "ResourceAlreadyExistsException":
+		return ErrCloudWatchAlreadyExists
+	case "InvalidSequenceTokenException":
+		return &CloudWatchSequenceTokenError{Expected: apiErr.ExpectedSequenceToken}
+	case "DataAlreadyAcceptedException":
+		return &CloudWatchSequenceTokenError{Expected: apiErr.ExpectedSequenceToken, AlreadyAccepted: true}
+	}
+	return fmt.Errorf("cloudwatch %s: unexpected status %s: %s %s", action, status, typ, apiErr.Message)
+}
+
+// credentials returns the region and credentials to sign requests with,
+// looking them up when unknown or about to expire.
+func (c *awsLogsClient) credentials(ctx context.Context) (string, awsCredentials, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.region == "" {
+		region, err := c.imds(ctx, "/latest/meta-data/placement/region")
+		if err != nil {
+			return "", awsCredentials{}, fmt.Errorf("cloudwatch: no AWS region: %w", err)
+		}
+		c.region = region
+	}
+	if c.creds.AccessKeyID != "" && (c.creds.Expiration.IsZero() || time.Until(c.creds.Expiration) > 5*time.Minute) {
+		return c.region, c.creds, nil
+	}
+
+	creds, err := c.lookupCredentials(ctx)
+	if err != nil {
+		return "", awsCredentials{}, fmt.Errorf("cloudwatch: no AWS credentials: %w", err)
+	}
+	c.creds = creds
+	return c.region, c.creds, nil
+}
+
+func (c *awsLogsClient) lookupCredentials(ctx context.Context) (awsCredentials, error) {
+	if id := os.Getenv("AWS_ACCESS_KEY_ID"); id != "" {
+		return awsCredentials{
+			AccessKeyID:     id,
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			Token:           os.Getenv("AWS_SESSION_TOKEN"),
+		}, nil
+	}
+
+	var creds awsCredentials
+	if uri := os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"); uri != "" {
+		err := c.getJSON(ctx, ecsCredentialsEndpoint+uri, nil, &creds)
+		return creds, err
+	}
+	if uri := os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI"); uri != "" {
+		header := http.Header{}
+		if token := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN"); token != "" {
+			header.Set("Authorization", token)
+		}
+		err := c.getJSON(ctx, uri, header, &creds)
+		return creds, err
+	}
+
+	role, err := c.imds(ctx, "/latest/meta-data/iam/security-credentials/")
+	if err != nil {
+		return creds, err
+	}
+	role, _, _ = strings.Cut(role, "\n")
+	body, err := c.imds(ctx, "/latest/meta-data/iam/security-credentials/"+role)
+	if err != nil {
+		return creds, err
+	}
+	err = json.Unmarshal([]byte(body), &creds)
+	return creds, err
+}
+
+// imds gets path from the EC2 instance metadata service, using IMDSv2.
+func (c *awsLogsClient) imds(ctx context.Context, path string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, imdsEndpoint+"/latest/api/token", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", "60")
+	token, err := c.get(req)
+	if err != nil {
+		return "", err
+	}
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, imdsEndpoint+path, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-aws-ec2-metadata-token", token)
+	return c.get(req)
+}
+
+func (c *awsLogsClient) getJSON(ctx context.Context, url string, header http.Header, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	for k, vs := range header {
+		req.Header[k] = vs
+	}
+	body, err := c.get(req)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(body), v)
+}
+
+func (c *awsLogsClient) get(req *http.Request) (string, error) {
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode >= 300 {
+		return "", errors.New(req.URL.Path + ": unexpected status " + resp.Status)
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+// signAWSRequest adds the headers of AWS Signature Version 4 to req, whose
+// body is body and whose only other headers are Content-Type and X-Amz-Target.
+func signAWSRequest(req *http.Request, host string, body []byte, region, service string, creds awsCredentials, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	if creds.Token != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.Token)
+	}
+
+	headers := []string{"content-type", "host", "x-amz-date"}
+	if creds.Token != "" {
+		headers = append(headers, "x-amz-security-token")
+	}
+	headers = append(headers, "x-amz-target")
+	var canonicalHeaders strings.Builder
+	for _, h := range headers {
+		v := req.Header.Get(h)
+		if h == "host" {
+			v = host
+		}
+		canonicalHeaders.WriteString(h + ":" + strings.TrimSpace(v) + "\n")
+	}
+	signedHeaders := strings.Join(headers, ";")
+
+	payloadHash := sha256.Sum256(body)
+	canonicalRequest := strings.Join([]string{
+		req.Method, "/", "", canonicalHeaders.String(), signedHeaders, hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+creds.AccessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,254 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// fakeCloudWatch is a CloudWatchLogsClient holding a single stream whose
+// log group already exists. The stream expects the sequence token
+// "token-<token>".
+type fakeCloudWatch struct {
+	mu             sync.Mutex
+	creates        []string
+	puts           []string // the sequence token of every call
+	events         []CloudWatchEvent
+	token          int
+	alreadyAccepts int // the number of calls to answer with DataAlreadyAcceptedException
+}
+
+func (c *fakeCloudWatch) CreateLogGroup(ctx context.Context, group string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.creates = append(c.creates, "group "+group)
+	return fmt.Errorf("create %s: %w", group, ErrCloudWatchAlreadyExists)
+}
+
+func (c *fakeCloudWatch) CreateLogStream(ctx context.Context, group, stream string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.creates = append(c.creates, "stream "+group+"/"+stream)
+	return nil
+}
+
+func (c *fakeCloudWatch) PutLogEvents(ctx context.Context, group, stream string, events []CloudWatchEvent, sequenceToken string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.puts = append(c.puts, sequenceToken)
+	expected := fmt.Sprint("token-", c.token)
+	if c.alreadyAccepts > 0 {
+		c.alreadyAccepts--
+		return "", &CloudWatchSequenceTokenError{Expected: expected, AlreadyAccepted: true}
+	}
+	if sequenceToken != expected {
+		return "", &CloudWatchSequenceTokenError{Expected: expected}
+	}
+	c.events = append(c.events, events...)
+	c.token++
+	return fmt.Sprint("token-", c.token), nil
+}
+
+func TestCloudWatchCoreResyncsTheSequenceToken(t *testing.T) {
+	client := &fakeCloudWatch{token: 5}
+	core := NewCloudWatchClientCore(client, "app", "host-1", INFO)
+	log := zap.New(core)
+	log.Info("first")
+	log.Debug("left out")
+	log.Warn("second")
+	if err := core.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	log.Error("third")
+	if err := core.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if got, want := strings.Join(client.creates, ","), "group app,stream app/host-1"; got != want {
+		t.Errorf("created %s, want %s", got, want)
+	}
+	// the stale empty token is replaced by the expected one, which is then
+	// followed by the token returned
+	if got, want := strings.Join(client.puts, ","), ",token-5,token-6"; got != want {
+		t.Errorf("sequence tokens = %s, want %s", got, want)
+	}
+	var got []string
+	for _, ev := range client.events {
+		if ev.Timestamp.IsZero() {
+			t.Errorf("event %s has no timestamp", ev.Message)
+		}
+		got = append(got, ev.Message)
+	}
+	if len(got) != 3 || !strings.Contains(got[0], `"message":"first"`) || !strings.Contains(got[1], `"message":"second"`) || !strings.Contains(got[2], `"message":"third"`) {
+		t.Errorf("events = %q, want first, second and third", got)
+	}
+}
+
+func TestCloudWatchCoreDataAlreadyAccepted(t *testing.T) {
+	client := &fakeCloudWatch{alreadyAccepts: 1}
+	core := NewCloudWatchClientCore(client, "app", "host-1", INFO)
+	log := zap.New(core)
+	log.Info("accepted before")
+	if err := core.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	log.Info("next")
+	if err := core.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	// accepted events aren't put again, but the expected token is kept
+	if got, want := strings.Join(client.puts, ","), ",token-0"; got != want {
+		t.Errorf("sequence tokens = %s, want %s", got, want)
+	}
+	if len(client.events) != 1 || !strings.Contains(client.events[0].Message, `"message":"next"`) {
+		t.Errorf("events = %v, want only next", client.events)
+	}
+}
+
+// failingCloudWatch fails to create the log group.
+type failingCloudWatch struct {
+	fakeCloudWatch
+}
+
+func (c *failingCloudWatch) CreateLogGroup(ctx context.Context, group string) error {
+	return errors.New("access denied")
+}
+
+func TestCloudWatchCoreCreateError(t *testing.T) {
+	client := &failingCloudWatch{}
+	core := NewCloudWatchClientCore(client, "app", "host-1", INFO)
+	zap.New(core).Info("lost")
+	if err := core.Close(); err == nil || err.Error() != "access denied" {
+		t.Errorf("Close() = %v, want the error creating the group", err)
+	}
+	if len(client.puts) != 0 {
+		t.Errorf("put %d times without a stream", len(client.puts))
+	}
+}
+
+// blockingCloudWatch signals received on its first call to PutLogEvents and
+// holds every call until release is closed.
+type blockingCloudWatch struct {
+	fakeCloudWatch
+	received chan struct{}
+	release  chan struct{}
+}
+
+func (c *blockingCloudWatch) PutLogEvents(ctx context.Context, group, stream string, events []CloudWatchEvent, sequenceToken string) (string, error) {
+	select {
+	case c.received <- struct{}{}:
+	default:
+	}
+	<-c.release
+	return c.fakeCloudWatch.PutLogEvents(ctx, group, stream, events, sequenceToken)
+}
+
+func TestCloudWatchCoreDropsOnceTheBufferIsFull(t *testing.T) {
+	client := &blockingCloudWatch{received: make(chan struct{}, 1), release: make(chan struct{})}
+	core := NewCloudWatchClientCore(client, "app", "host-1", INFO)
+	log := zap.New(core)
+	// a full batch is put at once, and CloudWatch holds on to it
+	for i := 0; i < defaultBatchSize; i++ {
+		log.Info("batch")
+	}
+	<-client.received
+	for i := 0; i < cloudWatchBufferSize+5; i++ {
+		log.Info("queued")
+	}
+	if got := core.Dropped(); got != 5 {
+		t.Errorf("Dropped() = %d, want 5", got)
+	}
+	close(client.release)
+	core.Close()
+}
+
+func TestCloudWatchBatches(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	entry := func(at time.Time, msg string) batchEntry {
+		e := batchEntry{Line: []byte(msg)}
+		e.Entry.Time = at
+		return e
+	}
+
+	t.Run("chronological order", func(t *testing.T) {
+		batches := cloudWatchBatches([]batchEntry{entry(start.Add(time.Second), "later"), entry(start, "earlier")})
+		if len(batches) != 1 || batches[0][0].Message != "earlier" || batches[0][1].Message != "later" {
+			t.Errorf("batches = %v, want one in chronological order", batches)
+		}
+	})
+
+	t.Run("time span", func(t *testing.T) {
+		batches := cloudWatchBatches([]batchEntry{
+			entry(start, "a"),
+			entry(start.Add(23*time.Hour), "b"),
+			entry(start.Add(24*time.Hour), "c"),
+		})
+		if len(batches) != 2 || len(batches[0]) != 2 || batches[1][0].Message != "c" {
+			t.Errorf("batches = %v, want a new one after 24 hours", batches)
+		}
+	})
+
+	t.Run("count", func(t *testing.T) {
+		entries := make([]batchEntry, cloudWatchMaxBatchEvents+1)
+		for i := range entries {
+			entries[i] = entry(start, "x")
+		}
+		batches := cloudWatchBatches(entries)
+		if len(batches) != 2 || len(batches[0]) != cloudWatchMaxBatchEvents || len(batches[1]) != 1 {
+			t.Errorf("batches of %d events, want %d and 1", len(batches[0]), cloudWatchMaxBatchEvents)
+		}
+	})
+
+	t.Run("size", func(t *testing.T) {
+		big := strings.Repeat("x", cloudWatchMaxEventBytes)
+		entries := make([]batchEntry, 5)
+		for i := range entries {
+			entries[i] = entry(start, big)
+		}
+		batches := cloudWatchBatches(entries)
+		if len(batches) != 2 || len(batches[0]) != 4 || len(batches[1]) != 1 {
+			t.Errorf("%d batches, want 4 events then 1", len(batches))
+		}
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		msg := strings.Repeat("x", cloudWatchMaxEventBytes-1) + "é"
+		batches := cloudWatchBatches([]batchEntry{entry(start, msg)})
+		if got := batches[0][0].Message; len(got) != cloudWatchMaxEventBytes-1 || !strings.HasSuffix(got, "x") {
+			t.Errorf("truncated to %d bytes, want %d without the cut character", len(got), cloudWatchMaxEventBytes-1)
+		}
+	})
+}
+
+func TestAWSLogsError(t *testing.T) {
+	err := awsLogsError("CreateLogGroup", "400 Bad Request", []byte(`{"__type":"com.amazonaws.logs#ResourceAlreadyExistsException"}`))
+	if !errors.Is(err, ErrCloudWatchAlreadyExists) {
+		t.Errorf("error = %v, want ErrCloudWatchAlreadyExists", err)
+	}
+
+	var tokenErr *CloudWatchSequenceTokenError
+	err = awsLogsError("PutLogEvents", "400 Bad Request", []byte(`{"__type":"InvalidSequenceTokenException","expectedSequenceToken":"49"}`))
+	if !errors.As(err, &tokenErr) || tokenErr.Expected != "49" || tokenErr.AlreadyAccepted {
+		t.Errorf("error = %v, want an invalid sequence token expecting 49", err)
+	}
+	err = awsLogsError("PutLogEvents", "400 Bad Request", []byte(`{"__type":"DataAlreadyAcceptedException","expectedSequenceToken":"50"}`))
+	if !errors.As(err, &tokenErr) || tokenErr.Expected != "50" || !tokenErr.AlreadyAccepted {
+		t.Errorf("error = %v, want the data already accepted", err)
+	}
+
+	err = awsLogsError("PutLogEvents", "403 Forbidden", []byte(`{"__type":"AccessDeniedException","message":"no"}`))
+	if err == nil || !strings.Contains(err.Error(), "403 Forbidden: AccessDeniedException no") {
+		t.Errorf("error = %v, want the status and message", err)
+	}
+}
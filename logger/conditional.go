@@ -0,0 +1,44 @@
+package logger
+
+import "go.uber.org/zap"
+
+// TracezIf, DebugzIf, InfozIf, WarnzIf and ErrorzIf log msg at their level
+// only when cond is true, sparing the if around the call.
+func (s *standardLogger) TracezIf(cond bool, msg string, fields ...Field) {
+	if cond {
+		s.log.Log(TRACE, msg, fields...)
+	}
+}
+
+func (s *standardLogger) DebugzIf(cond bool, msg string, fields ...Field) {
+	if cond {
+		s.log.Debug(msg, fields...)
+	}
+}
+
+func (s *standardLogger) InfozIf(cond bool, msg string, fields ...Field) {
+	if cond {
+		s.log.Info(msg, fields...)
+	}
+}
+
+func (s *standardLogger) WarnzIf(cond bool, msg string, fields ...Field) {
+	if cond {
+		s.log.Warn(msg, fields...)
+	}
+}
+
+func (s *standardLogger) ErrorzIf(cond bool, msg string, fields ...Field) {
+	if cond {
+		s.log.Error(msg, fields...)
+	}
+}
+
+// ErrorOn logs msg at ERROR with err as the "error" field, only when err is
+// not nil, including a nil pointer stored in the error interface.
+func (s *standardLogger) ErrorOn(err error, msg string, fields ...Field) {
+	if isNilError(err) {
+		return
+	}
+	s.log.Error(msg, append(fields[:len(fields):len(fields)], zap.Error(err))...)
+}
@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// pointerError is an error implemented on a pointer, to store a nil one in
+// the error interface.
+type pointerError struct{}
+
+func (*pointerError) Error() string { return "pointer error" }
+
+func TestConditionalHelpers(t *testing.T) {
+	svc, logs := NewTestLogger()
+	s := svc.(*standardLogger)
+
+	for _, cond := range []bool{false, true} {
+		s.TracezIf(cond, "trace")
+		s.DebugzIf(cond, "debug")
+		s.InfozIf(cond, "info", zap.Bool("cond", cond))
+		s.WarnzIf(cond, "warn")
+		s.ErrorzIf(cond, "error")
+	}
+
+	want := []string{"TRACE trace", "DEBUG debug", "INFO info", "WARN warn", "ERROR error"}
+	entries := logs.AllUntimed()
+	if len(entries) != len(want) {
+		t.Fatalf("logged %d entries, want %d", len(entries), len(want))
+	}
+	for i, e := range entries {
+		if got := LevelName(e.Level) + " " + e.Message; got != want[i] {
+			t.Errorf("entry %d = %s, want %s", i, got, want[i])
+		}
+		if !strings.HasSuffix(e.Caller.File, "conditional_test.go") {
+			t.Errorf("entry %d caller = %s, want this file", i, e.Caller.File)
+		}
+	}
+	if entries[2].ContextMap()["cond"] != true {
+		t.Errorf("fields = %v, want cond=true", entries[2].ContextMap())
+	}
+}
+
+func TestErrorOn(t *testing.T) {
+	svc, logs := NewTestLogger()
+	s := svc.(*standardLogger)
+	var nilPointer *pointerError
+
+	s.ErrorOn(nil, "nil")
+	s.ErrorOn(nilPointer, "nil pointer")
+	s.ErrorOn(errors.New("boom"), "failed", zap.String("user", "bob"))
+
+	entries := logs.AllUntimed()
+	if len(entries) != 1 {
+		t.Fatalf("logged %d entries, want only the non-nil error", len(entries))
+	}
+	e := entries[0]
+	if e.Level != ERROR || e.Message != "failed" {
+		t.Errorf("entry = %s %q, want ERROR failed", LevelName(e.Level), e.Message)
+	}
+	if fields := e.ContextMap(); fields["error"] != "boom" || fields["user"] != "bob" {
+		t.Errorf("fields = %v, want error=boom user=bob", fields)
+	}
+	if !strings.HasSuffix(e.Caller.File, "conditional_test.go") {
+		t.Errorf("caller = %s, want this file", e.Caller.File)
+	}
+}
+
+func TestErrorOnLeavesTheFieldsAlone(t *testing.T) {
+	svc, _ := NewTestLogger()
+	fields := make([]Field, 1, 2)
+	fields[0] = zap.String("user", "bob")
+
+	svc.(*standardLogger).ErrorOn(errors.New("boom"), "failed", fields...)
+	if extra := fields[:2][1]; extra.Key != "" {
+		t.Errorf("ErrorOn appended %q to the fields of the caller", extra.Key)
+	}
+}
+
+func TestPackageLevelConditionalHelpers(t *testing.T) {
+	svc, logs := NewTestLogger()
+	useGlobal(t, svc.(*standardLogger))
+
+	TracezIf(true, "trace")
+	DebugzIf(false, "skipped")
+	InfozIf(true, "info")
+	WarnzIf(false, "skipped")
+	ErrorzIf(true, "error")
+	ErrorOn(errors.New("boom"), "failed")
+
+	got := strings.Join(observedMessages(logs), ",")
+	if want := "trace,info,error,failed"; got != want {
+		t.Errorf("logged %s, want %s", got, want)
+	}
+	for _, e := range logs.All() {
+		if !strings.HasSuffix(e.Caller.File, "conditional_test.go") {
+			t.Errorf("%s caller = %s, want this file", e.Message, e.Caller.File)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/dazzling420/go-logger/config"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigHandler returns a handler responding with the config svc was built
+// with, defaults applied and changes made by Reconfigure included, as JSON
+// keyed like the YAML file, logging_level holding the live level of svc. It
+// is meant for debug endpoints: nothing is redacted, so keep it away from
+// untrusted clients. Loggers not built by NewService have no config and get
+// a 404.
+func ConfigHandler(svc Service) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s := svc.GetLogger()
+		conf := s.config()
+		if conf == nil {
+			http.Error(w, "the logger has no config", http.StatusNotFound)
+			return
+		}
+		conf.LoggingLevel = LevelName(s.level.Level())
+
+		// through YAML so the keys are the ones of the config file
+		b, err := yaml.Marshal(conf)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		var fields map[string]interface{}
+		if err := yaml.Unmarshal(b, &fields); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(fields)
+	})
+}
+
+func newConfigPointer(conf *config.Logger) *atomic.Pointer[config.Logger] {
+	p := &atomic.Pointer[config.Logger]{}
+	p.Store(conf)
+	return p
+}
+
+// config returns a copy of the config s was built with, nil when it wasn't
+// built by NewService.
+func (s *standardLogger) config() *config.Logger {
+	if s.conf == nil {
+		return nil
+	}
+	conf := *s.conf.Load()
+	return &conf
+}
+
+// reconfigured returns old with the settings Reconfigure applies taken from
+// conf.
+func reconfigured(old, conf config.Logger) config.Logger {
+	old.LoggingLevel = conf.LoggingLevel
+	old.LogFileName = conf.LogFileName
+	old.LogFileSizeCappingInMBs = conf.LogFileSizeCappingInMBs
+	old.MaxLogBackupsCount = conf.MaxLogBackupsCount
+	old.MaxOldLogRetentionInDays = conf.MaxOldLogRetentionInDays
+	old.OldLogsCompressionRequired = conf.OldLogsCompressionRequired
+	old.LogFileMode = conf.LogFileMode
+	old.CurrentLogSymlink = conf.CurrentLogSymlink
+	old.CircularFileSize = conf.CircularFileSize
+	old.RotateDaily = conf.RotateDaily
+	old.FallbackToStderr = conf.FallbackToStderr
+	old.AsyncBuffer = conf.AsyncBuffer
+	old.AsyncBufferSize = conf.AsyncBufferSize
+	old.AsyncBufferDropOnFull = conf.AsyncBufferDropOnFull
+	old.BackgroundFlushInterval = conf.BackgroundFlushInterval
+	return old
+}
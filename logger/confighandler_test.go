@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/dazzling420/go-logger/config"
+)
+
+// getConfig requests the config served by ConfigHandler for svc.
+func getConfig(t *testing.T, svc Service) (int, map[string]interface{}) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	ConfigHandler(svc).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/logger/config", nil))
+	if rec.Code != http.StatusOK {
+		return rec.Code, nil
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %s, want application/json", ct)
+	}
+	var conf map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &conf); err != nil {
+		t.Fatal(err)
+	}
+	return rec.Code, conf
+}
+
+func TestConfigHandler(t *testing.T) {
+	s, path := newFileService(t, config.Logger{LoggingLevel: "INFO", MaxLogBackupsCount: 3})
+	s.SetLevel("DEBUG")
+
+	_, conf := getConfig(t, s)
+	if conf["logging_level"] != "DEBUG" {
+		t.Errorf("logging_level = %v, want the live level", conf["logging_level"])
+	}
+	if conf["log_file_name"] != path || conf["max_log_backups_count"] != float64(3) || conf["disable_stdout"] != true {
+		t.Errorf("config = %v, want the one of the logger", conf)
+	}
+	// defaults applied
+	if conf["encoding"] != config.DefaultEncoding {
+		t.Errorf("encoding = %v, want the default %s", conf["encoding"], config.DefaultEncoding)
+	}
+}
+
+func TestConfigHandlerAfterReconfigure(t *testing.T) {
+	s, path := newFileService(t, config.Logger{})
+	conf := *s.config()
+	conf.LoggingLevel = "WARN"
+	conf.LogFileName = filepath.Join(filepath.Dir(path), "moved.log")
+	if err := s.Reconfigure(conf); err != nil {
+		t.Fatal(err)
+	}
+
+	_, served := getConfig(t, s)
+	if served["logging_level"] != "WARN" || served["log_file_name"] != conf.LogFileName {
+		t.Errorf("config = %v, want the reconfigured level and file", served)
+	}
+}
+
+func TestConfigHandlerWithoutAConfig(t *testing.T) {
+	svc, _ := NewTestLogger()
+	if code, _ := getConfig(t, svc); code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", code)
+	}
+}
@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying svc, retrieve it with FromContext.
+func NewContext(ctx context.Context, svc Service) context.Context {
+	return context.WithValue(ctx, contextKey{}, svc)
+}
+
+// FromContext returns the Service stored in ctx by NewContext, falling back
+// to the global logger set with SetLogger. It returns nil when there is
+// neither.
+func FromContext(ctx context.Context) Service {
+	if svc, ok := ctx.Value(contextKey{}).(Service); ok {
+		return svc
+	}
+	if l := GetLogger(); l != nil {
+		return l
+	}
+	return nil
+}
+
+// LogContextEnd logs msg to svc when the work ctx governed ends, at a level
+// telling how it ended: INFO when ctx is still live, with the time remaining
+// before its deadline as a remaining field when it has one, WARN when its
+// deadline was exceeded, with the time since as an overrun field, and ERROR
+// when it was canceled, with remaining when it has a deadline. The error of
+// ctx is added as an error field, and its cause as a cause field when
+// context.WithCancelCause gave it a different one.
+func LogContextEnd(ctx context.Context, svc Service, msg string) {
+	// skip this function so the caller is the one of LogContextEnd
+	log := svc.GetZapLogger().WithOptions(zap.AddCallerSkip(1))
+	deadline, hasDeadline := ctx.Deadline()
+
+	err := ctx.Err()
+	var fields []Field
+	level := INFO
+	switch {
+	case err == nil:
+		if hasDeadline {
+			fields = append(fields, zap.Duration("remaining", time.Until(deadline)))
+		}
+	case errors.Is(err, context.DeadlineExceeded):
+		level = WARN
+		fields = append(fields, zap.Duration("overrun", time.Since(deadline)))
+	default:
+		level = ERROR
+		if hasDeadline {
+			fields = append(fields, zap.Duration("remaining", time.Until(deadline)))
+		}
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+		if cause := context.Cause(ctx); cause != nil && cause != err {
+			fields = append(fields, zap.NamedError("cause", cause))
+		}
+	}
+	log.Log(level, msg, fields...)
+}
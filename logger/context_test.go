@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFromContext(t *testing.T) {
+	svc, _ := NewTestLogger()
+	if got := FromContext(NewContext(context.Background(), svc)); got != svc {
+		t.Errorf("FromContext() = %v, want the logger of the context", got)
+	}
+
+	defer SetLogger(GetLogger())
+	SetLogger(nil)
+	if got := FromContext(context.Background()); got != nil {
+		t.Errorf("FromContext() = %v without a logger, want nil", got)
+	}
+	global := svc.(*standardLogger)
+	SetLogger(global)
+	if got := FromContext(context.Background()); got != global {
+		t.Errorf("FromContext() = %v, want the global logger", got)
+	}
+}
+
+func TestLogContextEnd(t *testing.T) {
+	svc, logs := NewTestLogger()
+
+	completed, cancel := context.WithTimeout(context.Background(), time.Hour)
+	LogContextEnd(completed, svc, "completed")
+	cancel()
+	LogContextEnd(completed, svc, "canceled")
+
+	timedOut, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	LogContextEnd(timedOut, svc, "timed out")
+
+	caused, cancelCause := context.WithCancelCause(context.Background())
+	cancelCause(errors.New("client went away"))
+	LogContextEnd(caused, svc, "canceled with a cause")
+
+	LogContextEnd(context.Background(), svc, "no deadline")
+
+	entries := logs.AllUntimed()
+	if len(entries) != 5 {
+		t.Fatalf("logged %d entries, want 5", len(entries))
+	}
+	for i, want := range []struct {
+		level  string
+		fields string
+	}{
+		{"INFO", "remaining"},
+		{"ERROR", "remaining,error"},
+		{"WARN", "overrun,error"},
+		{"ERROR", "error,cause"},
+		{"INFO", ""},
+	} {
+		e := entries[i]
+		var keys []string
+		for _, f := range e.Context {
+			keys = append(keys, f.Key)
+		}
+		if LevelName(e.Level) != want.level || strings.Join(keys, ",") != want.fields {
+			t.Errorf("%s = %s with %v, want %s with %s", e.Message, LevelName(e.Level), keys, want.level, want.fields)
+		}
+		if !strings.HasSuffix(e.Caller.File, "context_test.go") {
+			t.Errorf("%s caller = %s, want this file", e.Message, e.Caller.File)
+		}
+	}
+
+	fields := entries[0].ContextMap()
+	if remaining := fields["remaining"].(time.Duration); remaining <= 59*time.Minute || remaining > time.Hour {
+		t.Errorf("remaining = %v, want about an hour", remaining)
+	}
+	fields = entries[2].ContextMap()
+	if overrun := fields["overrun"].(time.Duration); overrun < time.Second || overrun > time.Minute {
+		t.Errorf("overrun = %v, want about a second", overrun)
+	}
+	if fields["error"] != context.DeadlineExceeded.Error() {
+		t.Errorf("error = %v, want the deadline exceeded", fields["error"])
+	}
+	fields = entries[3].ContextMap()
+	if fields["error"] != context.Canceled.Error() || fields["cause"] != "client went away" {
+		t.Errorf("fields = %v, want the cancellation and its cause", fields)
+	}
+}
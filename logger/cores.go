@@ -0,0 +1,150 @@
+package logger
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// levelFilteredCore drops entries its level enabler rejects on Write too.
+// zapcore's tee only filters levels in Check, so a core wrapping a tee from
+// above (and adding itself to the checked entry) would otherwise see every
+// tee member write every entry.
+type levelFilteredCore struct {
+	zapcore.Core
+}
+
+func (c levelFilteredCore) With(fields []zapcore.Field) zapcore.Core {
+	return levelFilteredCore{c.Core.With(fields)}
+}
+
+func (c levelFilteredCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if !c.Enabled(ent.Level) {
+		return nil
+	}
+	return c.Core.Write(ent, fields)
+}
+
+// newTee tees cores so that each only receives the levels it is enabled for,
+// regardless of how the resulting core is wrapped.
+func newTee(cores ...zapcore.Core) zapcore.Core {
+	if len(cores) == 1 {
+		return cores[0]
+	}
+	filtered := make([]zapcore.Core, len(cores))
+	for i, c := range cores {
+		filtered[i] = levelFilteredCore{c}
+	}
+	return zapcore.NewTee(filtered...)
+}
+
+// allLevels enables every level, for cores whose level is applied by an
+// atomCore above them.
+var allLevels = zap.LevelEnablerFunc(func(zapcore.Level) bool { return true })
+
+// errorLevels enables ERROR and above, AUDIT excepted.
+var errorLevels = zap.LevelEnablerFunc(func(l zapcore.Level) bool { return l >= ERROR && l != AUDIT })
+
+// auditLevel enables AUDIT alone.
+var auditLevel = zap.LevelEnablerFunc(func(l zapcore.Level) bool { return l == AUDIT })
+
+// belowLevel is the field atomCore adds to entries below the level of the
+// logger, which only outputs with a level of their own write.
+type belowLevel struct{}
+
+var belowLevelField = zap.Field{Type: zapcore.SkipType, Interface: belowLevel{}}
+
+func isBelowLevel(fields []zapcore.Field) bool {
+	for _, f := range fields {
+		if f.Type == zapcore.SkipType && f.Interface == (belowLevel{}) {
+			return true
+		}
+	}
+	return false
+}
+
+// atomCore applies the level of a logger. Outputs following that level are
+// wrapped in a gatedCore, the others are described by independent and still
+// receive the entries below the level they are enabled for. Keeping the level
+// out of the outputs lets Clone give a logger sharing them a level of its own.
+// Entries with a field matching one of overrides follow the level of the
+// override instead when it is lower.
+type atomCore struct {
+	zapcore.Core
+	level       zap.AtomicLevel
+	independent zapcore.LevelEnabler
+	overrides   *fieldLevelOverrides
+	context     []zapcore.Field // the string fields bound with With, for overrides
+}
+
+func newAtomCore(core zapcore.Core, level zap.AtomicLevel, independent zapcore.LevelEnabler, overrides *fieldLevelOverrides) *atomCore {
+	if independent == nil {
+		independent = zapcore.InvalidLevel
+	}
+	return &atomCore{Core: core, level: level, independent: independent, overrides: overrides}
+}
+
+// withLevel returns a copy of c applying level instead.
+func (c *atomCore) withLevel(level zap.AtomicLevel) *atomCore {
+	return &atomCore{Core: c.Core, level: level, independent: c.independent, overrides: c.overrides, context: c.context}
+}
+
+func (c *atomCore) Enabled(l zapcore.Level) bool {
+	return c.level.Enabled(l) || c.independent.Enabled(l) || c.overrides.enabled(l)
+}
+
+func (c *atomCore) Level() zapcore.Level {
+	for l := TRACE; l <= FATAL; l++ {
+		if c.Enabled(l) {
+			return l
+		}
+	}
+	return zapcore.InvalidLevel
+}
+
+func (c *atomCore) With(fields []zapcore.Field) zapcore.Core {
+	context := c.context
+	for _, f := range fields {
+		if f.Type == zapcore.StringType {
+			context = append(context[:len(context):len(context)], f)
+		}
+	}
+	return &atomCore{Core: c.Core.With(fields), level: c.level, independent: c.independent, overrides: c.overrides, context: context}
+}
+
+func (c *atomCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *atomCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if !c.level.Enabled(ent.Level) && !c.overrides.match(ent.Level, c.context, fields) {
+		fields = append(fields[:len(fields):len(fields)], belowLevelField)
+	}
+	return c.Core.Write(ent, fields)
+}
+
+// gatedCore drops the entries an atomCore marked as below the level of the
+// logger.
+type gatedCore struct {
+	zapcore.Core
+}
+
+func (c gatedCore) With(fields []zapcore.Field) zapcore.Core {
+	return gatedCore{c.Core.With(fields)}
+}
+
+func (c gatedCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c gatedCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if isBelowLevel(fields) {
+		return nil
+	}
+	return c.Core.Write(ent, fields)
+}
@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"sync/atomic"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// LevelStats are the entries of one level logged so far and their size.
+type LevelStats struct {
+	Entries uint64
+	Bytes   uint64
+}
+
+// Stats are the entries logged so far, per level and in total, see
+// standardLogger.Stats.
+type Stats struct {
+	Levels  map[zapcore.Level]LevelStats // levels without entries left out
+	Entries uint64
+	Bytes   uint64
+}
+
+// logCounts counts entries and encoded bytes per level, TRACE to AUDIT.
+type logCounts struct {
+	entries [samplingLevels]atomic.Uint64
+	bytes   [samplingLevels]atomic.Uint64
+}
+
+// levelIndex returns the index of l in the arrays of logCounts, false for
+// levels out of their range.
+func levelIndex(l zapcore.Level) (int, bool) {
+	i := int(l - TRACE)
+	return i, i >= 0 && i < samplingLevels
+}
+
+func (c *logCounts) stats() Stats {
+	var s Stats
+	for i := range c.entries {
+		ls := LevelStats{Entries: c.entries[i].Load(), Bytes: c.bytes[i].Load()}
+		if ls.Entries == 0 && ls.Bytes == 0 {
+			continue
+		}
+		if s.Levels == nil {
+			s.Levels = make(map[zapcore.Level]LevelStats)
+		}
+		s.Levels[TRACE+zapcore.Level(i)] = ls
+		s.Entries += ls.Entries
+		s.Bytes += ls.Bytes
+	}
+	return s
+}
+
+// countingCore counts the entries written through it.
+type countingCore struct {
+	zapcore.Core
+	counts *logCounts
+}
+
+func (c countingCore) With(fields []zapcore.Field) zapcore.Core {
+	return countingCore{Core: c.Core.With(fields), counts: c.counts}
+}
+
+func (c countingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c countingCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if i, ok := levelIndex(ent.Level); ok {
+		c.counts.entries[i].Add(1)
+	}
+	return c.Core.Write(ent, fields)
+}
+
+// countingEncoder counts the bytes of the entries it encodes, its clones
+// counting along.
+type countingEncoder struct {
+	zapcore.Encoder
+	counts *logCounts
+}
+
+func (e countingEncoder) Clone() zapcore.Encoder {
+	return countingEncoder{Encoder: e.Encoder.Clone(), counts: e.counts}
+}
+
+func (e countingEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	buf, err := e.Encoder.EncodeEntry(ent, fields)
+	if err == nil {
+		if i, ok := levelIndex(ent.Level); ok {
+			e.counts.bytes[i].Add(uint64(buf.Len()))
+		}
+	}
+	return buf, err
+}
@@ -0,0 +1,71 @@
+package logger
+
+import (
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/dazzling420/go-logger/config"
+)
+
+func TestStats(t *testing.T) {
+	s, path := newFileService(t, config.Logger{LoggingLevel: "INFO"})
+	s.Infoz("one")
+	s.Infoz("two")
+	s.Debugz("below the level")
+	s.Errorz("failed")
+	s.Sync()
+
+	stats := s.Stats()
+	if stats.Entries != 3 || len(stats.Levels) != 2 || stats.Levels[INFO].Entries != 2 || stats.Levels[ERROR].Entries != 1 {
+		t.Errorf("Stats() = %+v, want 2 INFO and 1 ERROR entries", stats)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Bytes != uint64(info.Size()) || stats.Levels[INFO].Bytes+stats.Levels[ERROR].Bytes != stats.Bytes {
+		t.Errorf("Stats() = %+v, want the %d bytes of the log file", stats, info.Size())
+	}
+}
+
+func TestStatsCountBytesPerOutput(t *testing.T) {
+	s, _ := newFileService(t, config.Logger{AdditionalLogFiles: []string{tempLog(t, "copy.log")}})
+	s.Infoz("twice")
+
+	stats := s.Stats()
+	if stats.Entries != 1 {
+		t.Errorf("Entries = %d, want 1", stats.Entries)
+	}
+	single := uint64(len(`{"level":"INFO","message":"twice"}`))
+	if stats.Bytes < 2*single {
+		t.Errorf("Bytes = %d, want the entry counted for both files", stats.Bytes)
+	}
+}
+
+func TestStatsConcurrently(t *testing.T) {
+	s, _ := newFileService(t, config.Logger{})
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				s.Warnz("concurrent")
+			}
+		}()
+	}
+	wg.Wait()
+	if got := s.Stats().Levels[WARN].Entries; got != 800 {
+		t.Errorf("WARN entries = %d, want 800", got)
+	}
+}
+
+func TestStatsOfATestLogger(t *testing.T) {
+	svc, _ := NewTestLogger()
+	s := svc.(*standardLogger)
+	s.Infoz("not counted")
+	if stats := s.Stats(); stats.Entries != 0 || stats.Levels != nil {
+		t.Errorf("Stats() = %+v, want none", stats)
+	}
+}
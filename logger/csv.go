@@ -0,0 +1,252 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+var csvPool = buffer.NewPool()
+
+// csvEncoder writes one CSV row per entry holding the configured columns.
+// Columns named after the time, level, caller or message key hold those, any
+// other column holds the field of the same name and is left empty when the
+// entry has none. The header row belongs to the outputs, see headerSink.
+type csvEncoder struct {
+	*zapcore.MapObjectEncoder // context fields
+	cfg                       zapcore.EncoderConfig
+	columns                   []string
+}
+
+func newCSVEncoder(cfg zapcore.EncoderConfig, columns []string) zapcore.Encoder {
+	return &csvEncoder{
+		MapObjectEncoder: zapcore.NewMapObjectEncoder(),
+		cfg:              cfg,
+		columns:          csvColumns(cfg, columns),
+	}
+}
+
+// csvColumns returns columns, or without configured columns the standard keys
+// of cfg that are set.
+func csvColumns(cfg zapcore.EncoderConfig, columns []string) []string {
+	if len(columns) > 0 {
+		return columns
+	}
+	for _, key := range []string{cfg.TimeKey, cfg.LevelKey, cfg.CallerKey, cfg.MessageKey} {
+		if key != "" {
+			columns = append(columns, key)
+		}
+	}
+	return columns
+}
+
+// csvHeader returns the header row of the outputs conf encodes as CSV on top
+// of cfg, nil when conf asks for another encoding.
+func csvHeader(conf *config.Logger, cfg zapcore.EncoderConfig) []byte {
+	if conf.Encoding != EncodingCSV || conf.CompactConsole {
+		return nil
+	}
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.UseCRLF = cfg.LineEnding == "\r\n"
+	w.Write(csvColumns(cfg, conf.CSVColumns))
+	w.Flush()
+	return buf.Bytes()
+}
+
+func (e *csvEncoder) Clone() zapcore.Encoder {
+	fields := zapcore.NewMapObjectEncoder()
+	for k, v := range e.Fields {
+		fields.Fields[k] = v
+	}
+	return &csvEncoder{MapObjectEncoder: fields, cfg: e.cfg, columns: e.columns}
+}
+
+func (e *csvEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	values := e.Clone().(*csvEncoder).MapObjectEncoder
+	for _, f := range fields {
+		f.AddTo(values)
+	}
+
+	buf := csvPool.Get()
+	w := csv.NewWriter(buf)
+	w.UseCRLF = e.cfg.LineEnding == "\r\n"
+
+	row := make([]string, len(e.columns))
+	for i, col := range e.columns {
+		switch {
+		case col == e.cfg.TimeKey && e.cfg.EncodeTime != nil:
+			row[i] = encodeCell(func(enc zapcore.PrimitiveArrayEncoder) { e.cfg.EncodeTime(ent.Time, enc) })
+		case col == e.cfg.LevelKey && e.cfg.EncodeLevel != nil:
+			row[i] = encodeCell(func(enc zapcore.PrimitiveArrayEncoder) { e.cfg.EncodeLevel(ent.Level, enc) })
+		case col == e.cfg.CallerKey && e.cfg.EncodeCaller != nil:
+			if ent.Caller.Defined {
+				row[i] = encodeCell(func(enc zapcore.PrimitiveArrayEncoder) { e.cfg.EncodeCaller(ent.Caller, enc) })
+			}
+		case col == e.cfg.MessageKey:
+			row[i] = ent.Message
+		default:
+			if v, ok := values.Fields[col]; ok {
+				row[i] = cellString(v)
+			}
+		}
+	}
+	w.Write(row)
+	w.Flush()
+	return buf, w.Error()
+}
+
+// cellString renders a field value captured by a MapObjectEncoder.
+func cellString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case time.Duration:
+		return v.String()
+	case fmt.Stringer:
+		return v.String()
+	case []interface{}, map[string]interface{}:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// encodeCell runs one of the EncoderConfig encoders and returns what it
+// appended.
+func encodeCell(encode func(zapcore.PrimitiveArrayEncoder)) string {
+	var c cellEncoder
+	encode(&c)
+	return c.s
+}
+
+// cellEncoder is a PrimitiveArrayEncoder capturing what is appended to it as a
+// string.
+type cellEncoder struct{ s string }
+
+func (c *cellEncoder) append(v interface{}) {
+	if c.s != "" {
+		c.s += " "
+	}
+	c.s += fmt.Sprint(v)
+}
+
+func (c *cellEncoder) AppendBool(v bool)              { c.append(v) }
+func (c *cellEncoder) AppendByteString(v []byte)      { c.append(string(v)) }
+func (c *cellEncoder) AppendComplex128(v complex128)  { c.append(v) }
+func (c *cellEncoder) AppendComplex64(v complex64)    { c.append(v) }
+func (c *cellEncoder) AppendFloat64(v float64)        { c.append(v) }
+func (c *cellEncoder) AppendFloat32(v float32)        { c.append(v) }
+func (c *cellEncoder) AppendInt(v int)                { c.append(v) }
+func (c *cellEncoder) AppendInt64(v int64)            { c.append(v) }
+func (c *cellEncoder) AppendInt32(v int32)            { c.append(v) }
+func (c *cellEncoder) AppendInt16(v int16)            { c.append(v) }
+func (c *cellEncoder) AppendInt8(v int8)              { c.append(v) }
+func (c *cellEncoder) AppendString(v string)          { c.append(v) }
+func (c *cellEncoder) AppendUint(v uint)              { c.append(v) }
+func (c *cellEncoder) AppendUint64(v uint64)          { c.append(v) }
+func (c *cellEncoder) AppendUint32(v uint32)          { c.append(v) }
+func (c *cellEncoder) AppendUint16(v uint16)          { c.append(v) }
+func (c *cellEncoder) AppendUint8(v uint8)            { c.append(v) }
+func (c *cellEncoder) AppendUintptr(v uintptr)        { c.append(v) }
+func (c *cellEncoder) AppendDuration(v time.Duration) { c.append(v) }
+func (c *cellEncoder) AppendTime(v time.Time)         { c.append(v.Format(time.RFC3339Nano)) }
+
+// megabyte is the unit of LogFileSizeCappingInMBs, as lumberjack counts it.
+const megabyte = 1024 * 1024
+
+// headerSink writes header at the start of every file ws writes: a new one,
+// or one just rotated, whether by Rotate, daily or by size, but not a file
+// appended to after a restart. It follows the size of the file to tell when
+// lumberjack is about to rotate it, which it does when a write would exceed
+// max. For the standard streams, path is "" and the header is written once.
+type headerSink struct {
+	zapcore.WriteSyncer
+	header []byte
+	path   string
+	max    int64
+	rotate func() error // rotates the file on demand
+
+	mu      sync.Mutex
+	size    int64       // of the file, -1 when to be read again
+	rotated atomic.Bool // set by rotations ws makes on its own
+}
+
+func newHeaderSink(ws zapcore.WriteSyncer, header []byte, path string, max int64, rotate func() error) *headerSink {
+	h := &headerSink{WriteSyncer: ws, header: header, path: path, max: max, rotate: rotate}
+	if path != "" {
+		h.size = -1
+	}
+	return h
+}
+
+func (h *headerSink) Write(p []byte) (int, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.rotated.Swap(false) {
+		h.size = -1
+	}
+	// lumberjack rotates a file it reopens when the write would fill it
+	reopened := h.size < 0
+	if reopened {
+		h.size = 0
+		if fi, err := os.Stat(h.path); err == nil {
+			h.size = fi.Size()
+		}
+	}
+	rotates := h.max > 0 && (h.size+int64(len(p)) > h.max || reopened && h.size+int64(len(p)) >= h.max)
+
+	if h.size > 0 && !rotates {
+		n, err := h.WriteSyncer.Write(p)
+		h.size += int64(n)
+		if err != nil {
+			h.size = -1
+		}
+		return n, err
+	}
+	// the header goes with the entry so both land in the same file
+	buf := make([]byte, 0, len(h.header)+len(p))
+	buf = append(append(buf, h.header...), p...)
+	n, err := h.WriteSyncer.Write(buf)
+	h.size = int64(n)
+	if err != nil {
+		h.size = -1
+	}
+	n -= len(h.header)
+	if n < 0 {
+		n = 0
+	}
+	return n, err
+}
+
+// Rotate rotates the file, the next write starting the new one with the
+// header.
+func (h *headerSink) Rotate() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	err := h.rotate()
+	h.size = -1
+	return err
+}
+
+// markRotated tells h the file was moved aside by ws itself, see
+// dailyRotator. It does not lock as ws may call it while written to.
+func (h *headerSink) markRotated() {
+	h.rotated.Store(true)
+}
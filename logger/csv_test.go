@@ -0,0 +1,232 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap"
+)
+
+const csvTestHeader = "level,message,user\n"
+
+func csvConfig(path string) config.Logger {
+	return config.Logger{
+		LogFileName:   path,
+		DisableStdout: true,
+		Encoding:      EncodingCSV,
+		CSVColumns:    []string{"level", "message", "user"},
+	}
+}
+
+// readFile returns the content of the file at path.
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// checkOneHeader fails unless the file at path starts with the header and
+// has no other.
+func checkOneHeader(t *testing.T, path string) {
+	t.Helper()
+	got := readFile(t, path)
+	if !strings.HasPrefix(got, csvTestHeader) {
+		t.Errorf("%s does not start with the header:\n%s", filepath.Base(path), got)
+	}
+	if n := strings.Count(got, csvTestHeader); n != 1 {
+		t.Errorf("%s has %d headers:\n%s", filepath.Base(path), n, got)
+	}
+}
+
+func TestCSVHeaderAndEscapedRow(t *testing.T) {
+	path := tempLog(t, "app.csv")
+	svc, err := BuildService(csvConfig(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.Infoz(`say "hi", bye`, zap.String("user", "bob"))
+	svc.Infoz("two\nlines")
+	svc.Close()
+
+	want := csvTestHeader +
+		`INFO,"say ""hi"", bye",bob` + "\n" +
+		"INFO,\"two\nlines\",\n"
+	if got := readFile(t, path); got != want {
+		t.Errorf("file =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCSVHeaderCRLF(t *testing.T) {
+	path := tempLog(t, "app.csv")
+	conf := csvConfig(path)
+	conf.LineEnding = "crlf"
+	svc, err := BuildService(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.Infoz("hi")
+	svc.Close()
+
+	if got, want := readFile(t, path), "level,message,user\r\nINFO,hi,\r\n"; got != want {
+		t.Errorf("file = %q, want %q", got, want)
+	}
+}
+
+func TestCSVHeaderPerOutput(t *testing.T) {
+	dir := t.TempDir()
+	conf := csvConfig(filepath.Join(dir, "app.csv"))
+	conf.ErrorLogFileName = filepath.Join(dir, "error.csv")
+	conf.AdditionalLogFiles = []string{filepath.Join(dir, "copy.csv")}
+	svc, err := BuildService(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.Infoz("first")
+	svc.Errorz("failed")
+	svc.Infoz("last")
+	svc.Close()
+
+	for _, name := range []string{"app.csv", "error.csv", "copy.csv"} {
+		checkOneHeader(t, filepath.Join(dir, name))
+	}
+}
+
+func TestCSVHeaderOnStdout(t *testing.T) {
+	out := captureStdout(t, func() {
+		svc, err := BuildService(config.Logger{Encoding: EncodingCSV, CSVColumns: []string{"level", "message", "user"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		svc.Infoz("one")
+		svc.Infoz("two")
+		svc.Close()
+	})
+
+	if want := csvTestHeader + "INFO,one,\nINFO,two,\n"; out != want {
+		t.Errorf("stdout = %q, want %q", out, want)
+	}
+}
+
+func TestCSVHeaderAfterRotate(t *testing.T) {
+	path := tempLog(t, "app.csv")
+	svc, err := BuildService(csvConfig(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.Infoz("before")
+	if err := svc.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	svc.Infoz("after")
+	svc.Close()
+
+	files, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "*.csv"))
+	if len(files) != 2 {
+		t.Fatalf("files = %q, want the log and one backup", files)
+	}
+	for _, f := range files {
+		checkOneHeader(t, f)
+	}
+	if got, want := readFile(t, path), csvTestHeader+"INFO,after,\n"; got != want {
+		t.Errorf("rotated file = %q, want %q", got, want)
+	}
+}
+
+func TestCSVHeaderAfterSizeRotation(t *testing.T) {
+	path := tempLog(t, "app.csv")
+	conf := csvConfig(path)
+	conf.LogFileSizeCappingInMBs = 1
+	svc, err := BuildService(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	big := strings.Repeat("x", 300*1024)
+	for i := 0; i < 7; i++ {
+		svc.Infoz(big)
+	}
+	svc.Close()
+
+	files, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "*.csv"))
+	if len(files) < 2 {
+		t.Fatalf("files = %q, want the log rotated by size", files)
+	}
+	rows := 0
+	for _, f := range files {
+		checkOneHeader(t, f)
+		rows += strings.Count(readFile(t, f), "INFO,")
+	}
+	if rows != 7 {
+		t.Errorf("%d rows, want 7", rows)
+	}
+}
+
+// stoppedClock never reaches midnight, the tests rotate themselves.
+type stoppedClock struct{}
+
+func (stoppedClock) Now() time.Time                       { return time.Now() }
+func (stoppedClock) After(time.Duration) <-chan time.Time { return nil }
+
+func TestCSVHeaderAfterDailyRotation(t *testing.T) {
+	path := tempLog(t, "app.csv")
+	conf := csvConfig(path).WithDefaults()
+	daily := newDailyRotator(newLumberjackSink(path, &conf), stoppedClock{})
+	defer daily.Close()
+	hs := newHeaderSink(daily, []byte(csvTestHeader), path, megabyte, daily.Logger.Rotate)
+	daily.notify(hs.markRotated)
+
+	hs.Write([]byte("INFO,yesterday,\n"))
+	if err := daily.rotate(time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	hs.Write([]byte("INFO,today,\n"))
+
+	files, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "*.csv"))
+	if len(files) != 2 {
+		t.Fatalf("files = %q, want the log and the day before", files)
+	}
+	for _, f := range files {
+		checkOneHeader(t, f)
+	}
+}
+
+func TestCSVHeaderNotRepeatedOnRestart(t *testing.T) {
+	path := tempLog(t, "app.csv")
+	for _, msg := range []string{"first run", "second run"} {
+		svc, err := BuildService(csvConfig(path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		svc.Infoz(msg)
+		svc.Close()
+	}
+
+	if got, want := readFile(t, path), csvTestHeader+"INFO,first run,\nINFO,second run,\n"; got != want {
+		t.Errorf("file = %q, want %q", got, want)
+	}
+}
+
+func TestCSVHeaderWhenReconfiguredToANewFile(t *testing.T) {
+	dir := t.TempDir()
+	conf := csvConfig(filepath.Join(dir, "a.csv"))
+	svc, err := BuildService(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.Infoz("to a")
+	conf.LogFileName = filepath.Join(dir, "b.csv")
+	if err := svc.Reconfigure(conf); err != nil {
+		t.Fatal(err)
+	}
+	svc.Infoz("to b")
+	svc.Close()
+
+	checkOneHeader(t, filepath.Join(dir, "a.csv"))
+	checkOneHeader(t, filepath.Join(dir, "b.csv"))
+}
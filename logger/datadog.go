@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"context"
+	"encoding/binary"
+	"strconv"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// DatadogSpanExtractor returns the Datadog trace and span ids of the span
+// active in ctx, ok being false when there is none. Register one for the
+// tracer in use with SetDatadogSpanExtractor, e.g. for OpenTelemetry:
+//
+//	logger.SetDatadogSpanExtractor(func(ctx context.Context) (uint64, uint64, bool) {
+//		sc := trace.SpanContextFromContext(ctx)
+//		traceID, spanID := logger.DatadogIDsFromOTel(sc.TraceID(), sc.SpanID())
+//		return traceID, spanID, sc.IsValid()
+//	})
+type DatadogSpanExtractor func(ctx context.Context) (traceID, spanID uint64, ok bool)
+
+var datadogSpanExtractor atomic.Pointer[DatadogSpanExtractor]
+
+// SetDatadogSpanExtractor sets the extractor WithDatadogTrace falls back to
+// when ctx holds no ids set with ContextWithDatadogSpan. nil removes it.
+func SetDatadogSpanExtractor(f DatadogSpanExtractor) {
+	if f == nil {
+		datadogSpanExtractor.Store(nil)
+		return
+	}
+	datadogSpanExtractor.Store(&f)
+}
+
+type datadogSpanKey struct{}
+
+type datadogSpan struct{ traceID, spanID uint64 }
+
+// ContextWithDatadogSpan returns a copy of ctx carrying the given Datadog
+// trace and span ids, for code that already has them at hand.
+func ContextWithDatadogSpan(ctx context.Context, traceID, spanID uint64) context.Context {
+	return context.WithValue(ctx, datadogSpanKey{}, datadogSpan{traceID, spanID})
+}
+
+// DatadogIDsFromOTel converts OpenTelemetry trace and span ids to the 64 bit
+// ids Datadog correlates logs with, the trace id keeping its low 64 bits.
+func DatadogIDsFromOTel(traceID [16]byte, spanID [8]byte) (uint64, uint64) {
+	return binary.BigEndian.Uint64(traceID[8:]), binary.BigEndian.Uint64(spanID[:])
+}
+
+// DatadogTraceFields returns the dd.trace_id and dd.span_id fields Datadog
+// correlates logs with traces by, as decimal strings.
+func DatadogTraceFields(traceID, spanID uint64) []Field {
+	return []Field{
+		zap.String("dd.trace_id", strconv.FormatUint(traceID, 10)),
+		zap.String("dd.span_id", strconv.FormatUint(spanID, 10)),
+	}
+}
+
+// WithDatadogTrace returns a child logger carrying the dd.trace_id and
+// dd.span_id of the span active in ctx, taken from ContextWithDatadogSpan or
+// else the extractor set with SetDatadogSpanExtractor. It returns s when ctx
+// has no span.
+func (s *standardLogger) WithDatadogTrace(ctx context.Context) *standardLogger {
+	if span, ok := ctx.Value(datadogSpanKey{}).(datadogSpan); ok {
+		return s.With(DatadogTraceFields(span.traceID, span.spanID)...)
+	}
+	if f := datadogSpanExtractor.Load(); f != nil {
+		if traceID, spanID, ok := (*f)(ctx); ok {
+			return s.With(DatadogTraceFields(traceID, spanID)...)
+		}
+	}
+	return s
+}
+
+// datadogEncoderConfig adapts cfg to the JSON attributes Datadog reserves:
+// the level becomes a lowercase status and the time a timestamp.
+func datadogEncoderConfig(cfg zapcore.EncoderConfig) zapcore.EncoderConfig {
+	cfg.LevelKey = "status"
+	cfg.EncodeLevel = LowercaseLevelEncoder
+	cfg.MessageKey = "message"
+	cfg.TimeKey = "timestamp"
+	cfg.EncodeTime = zapcore.RFC3339NanoTimeEncoder
+	cfg.NameKey = "logger.name"
+	return cfg
+}
@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dazzling420/go-logger/config"
+)
+
+func TestDatadogEncoding(t *testing.T) {
+	svc, path := newFileService(t, config.Logger{Encoding: EncodingDatadog})
+	svc.WithDatadogTrace(ContextWithDatadogSpan(context.Background(), 12345, 678)).Warnz("traced")
+	svc.Sync()
+
+	e := readEntries(t, path)[0]
+	for key, want := range map[string]interface{}{
+		"status":      "warn",
+		"message":     "traced",
+		"dd.trace_id": "12345",
+		"dd.span_id":  "678",
+	} {
+		if e[key] != want {
+			t.Errorf("%s = %v, want %v", key, e[key], want)
+		}
+	}
+	if _, ok := e["timestamp"].(string); !ok {
+		t.Errorf("entry = %v, want a timestamp", e)
+	}
+}
+
+func TestWithDatadogTraceExtractor(t *testing.T) {
+	svc, logs := NewTestLogger()
+	s := svc.(*standardLogger)
+	if got := s.WithDatadogTrace(context.Background()); got != s {
+		t.Error("WithDatadogTrace returned a child without a span")
+	}
+
+	SetDatadogSpanExtractor(func(ctx context.Context) (uint64, uint64, bool) {
+		return 1, 2, ctx.Value(datadogSpanKey{}) == nil
+	})
+	defer SetDatadogSpanExtractor(nil)
+	s.WithDatadogTrace(context.Background()).Infoz("extracted")
+	// ids set on the context win over the extractor
+	s.WithDatadogTrace(ContextWithDatadogSpan(context.Background(), 3, 4)).Infoz("from the context")
+
+	entries := logs.AllUntimed()
+	if f := entries[0].ContextMap(); f["dd.trace_id"] != "1" || f["dd.span_id"] != "2" {
+		t.Errorf("fields = %v, want the extracted ids", f)
+	}
+	if f := entries[1].ContextMap(); f["dd.trace_id"] != "3" || f["dd.span_id"] != "4" {
+		t.Errorf("fields = %v, want the ids of the context", f)
+	}
+}
+
+func TestDatadogIDsFromOTel(t *testing.T) {
+	traceID := [16]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0, 0, 0, 0x01, 0x00}
+	spanID := [8]byte{0, 0, 0, 0, 0, 0, 0, 42}
+	if gotTrace, gotSpan := DatadogIDsFromOTel(traceID, spanID); gotTrace != 256 || gotSpan != 42 {
+		t.Errorf("DatadogIDsFromOTel() = %d, %d, want 256, 42", gotTrace, gotSpan)
+	}
+}
@@ -0,0 +1,162 @@
+package logger
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithDedup returns an option, for NewService or zap loggers, that drops an
+// entry repeating the level and message of the previous one within window of
+// its first occurrence. Once the window closes, or a different entry arrives,
+// the first entry's message is logged again with a repeated_count field
+// holding how many were dropped. Unlike sampling only consecutive repeats
+// are dropped, and the count is never lost.
+func WithDedup(window time.Duration) zap.Option {
+	return zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		core, _ = newDedupCore(core, window)
+		return core
+	})
+}
+
+type dedupKey struct {
+	level   zapcore.Level
+	message string
+}
+
+// dedup tracks the entry of the current window.
+type dedup struct {
+	window time.Duration
+
+	mu         sync.Mutex
+	open       bool
+	key        dedupKey
+	core       zapcore.Core // core that wrote the first entry
+	entry      zapcore.Entry
+	below      bool // whether the first entry was below the level of the logger
+	repeated   int
+	timer      *time.Timer
+	generation uint64 // tells the timer of a closed window from the current one
+	closed     bool
+}
+
+// newDedupCore wraps core so that consecutive entries with the same level and
+// message are dropped within window, see WithDedup. The returned function
+// closes the open window.
+func newDedupCore(core zapcore.Core, window time.Duration) (zapcore.Core, func() error) {
+	d := &dedup{window: window}
+	return &dedupCore{Core: core, dedup: d}, d.close
+}
+
+// repeat reports whether ent repeats the entry of the open window, opening a
+// new one otherwise. The window it closed, if any, is returned so its summary
+// can be written once the lock is released.
+func (d *dedup) repeat(core zapcore.Core, ent zapcore.Entry, fields []zapcore.Field) (bool, *dedupSummary) {
+	key := dedupKey{level: ent.Level, message: ent.Message}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return false, nil
+	}
+	if d.open && d.key == key {
+		d.repeated++
+		return true, nil
+	}
+	summary := d.closeWindow()
+
+	d.open = true
+	d.key = key
+	d.core = core
+	d.entry = ent
+	d.below = isBelowLevel(fields)
+	d.generation++
+	generation := d.generation
+	d.timer = time.AfterFunc(d.window, func() { d.expire(generation) })
+	return false, summary
+}
+
+// closeWindow closes the open window, returning its summary when entries were
+// dropped. It must be called with d.mu held.
+func (d *dedup) closeWindow() *dedupSummary {
+	if !d.open {
+		return nil
+	}
+	d.open = false
+	d.timer.Stop()
+	if d.repeated == 0 {
+		return nil
+	}
+	s := &dedupSummary{core: d.core, entry: d.entry, below: d.below, repeated: d.repeated}
+	d.repeated = 0
+	return s
+}
+
+func (d *dedup) expire(generation uint64) {
+	d.mu.Lock()
+	var summary *dedupSummary
+	if generation == d.generation {
+		summary = d.closeWindow()
+	}
+	d.mu.Unlock()
+	summary.write()
+}
+
+func (d *dedup) close() error {
+	d.mu.Lock()
+	summary := d.closeWindow()
+	d.closed = true
+	d.mu.Unlock()
+	return summary.write()
+}
+
+type dedupSummary struct {
+	core     zapcore.Core
+	entry    zapcore.Entry
+	below    bool
+	repeated int
+}
+
+func (s *dedupSummary) write() error {
+	if s == nil {
+		return nil
+	}
+	ent := s.entry
+	ent.Time = time.Now()
+	fields := []zapcore.Field{zap.Int("repeated_count", s.repeated)}
+	// the summary reaches the outputs the first entry did, no others
+	if s.below {
+		fields = append(fields, belowLevelField)
+	}
+	return s.core.Write(ent, fields)
+}
+
+type dedupCore struct {
+	zapcore.Core
+	dedup *dedup
+}
+
+func (c *dedupCore) With(fields []zapcore.Field) zapcore.Core {
+	return &dedupCore{Core: c.Core.With(fields), dedup: c.dedup}
+}
+
+func (c *dedupCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *dedupCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if ent.Level == AUDIT {
+		return c.Core.Write(ent, fields)
+	}
+	repeated, summary := c.dedup.repeat(c.Core, ent, fields)
+	if repeated {
+		return nil
+	}
+	summary.write()
+	return c.Core.Write(ent, fields)
+}
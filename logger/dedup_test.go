@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestDedupDropsConsecutiveRepeats(t *testing.T) {
+	obs, logs := observer.New(allLevels)
+	core, closeDedup := newDedupCore(obs, time.Hour)
+	log := zap.New(core)
+
+	log.Info("noisy")
+	log.Info("noisy")
+	log.Info("noisy")
+	log.Info("other")
+	log.Info("noisy")
+	closeDedup()
+
+	var got []string
+	for _, e := range logs.All() {
+		got = append(got, e.Message)
+		if e.Message == "noisy" && e.ContextMap()["repeated_count"] != nil && e.ContextMap()["repeated_count"] != int64(2) {
+			t.Errorf("repeated_count = %v, want 2", e.ContextMap()["repeated_count"])
+		}
+	}
+	want := []string{"noisy", "noisy", "other", "noisy"}
+	if len(got) != len(want) {
+		t.Fatalf("messages = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("messages = %q, want %q", got, want)
+		}
+	}
+	if n := logs.FilterField(zap.Int("repeated_count", 2)).Len(); n != 1 {
+		t.Errorf("%d summaries with repeated_count 2, want 1", n)
+	}
+}
+
+func TestDedupSummaryAfterWindow(t *testing.T) {
+	obs, logs := observer.New(allLevels)
+	core, closeDedup := newDedupCore(obs, 10*time.Millisecond)
+	defer closeDedup()
+	log := zap.New(core)
+
+	log.Warn("noisy")
+	log.Warn("noisy")
+	deadline := time.Now().Add(time.Second)
+	for logs.FilterField(zap.Int("repeated_count", 1)).Len() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("no summary once the window closed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestDedupNeverDropsAudit(t *testing.T) {
+	obs, logs := observer.New(allLevels)
+	core, closeDedup := newDedupCore(obs, time.Hour)
+	defer closeDedup()
+	log := zap.New(core)
+
+	log.Log(AUDIT, "login")
+	log.Log(AUDIT, "login")
+	if logs.Len() != 2 {
+		t.Errorf("%d audit entries written, want 2", logs.Len())
+	}
+}
+
+// The summary of entries below the level of the logger, written because an
+// output with a level of its own was enabled for them, must not reach the
+// outputs following the level.
+func TestDedupSummaryKeepsBelowLevelEntriesOutOfMainOutput(t *testing.T) {
+	mainLog := tempLog(t, "main.log")
+	debugLog := tempLog(t, "debug.log")
+	svc, err := BuildService(config.Logger{
+		LogFileName:   mainLog,
+		LoggingLevel:  "INFO",
+		DisableStdout: true,
+		DedupWindow:   time.Hour,
+		FileSinks:     []config.FileSink{{FileName: debugLog, Level: "DEBUG"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svc.Debugz("noisy")
+	svc.Debugz("noisy")
+	svc.Infoz("done")
+	if err := svc.Close(); err != nil {
+		t.Log(err)
+	}
+
+	for _, e := range readEntries(t, mainLog) {
+		if e["message"] == "noisy" {
+			t.Errorf("DEBUG entry in the INFO main output: %v", e)
+		}
+	}
+	var summaries int
+	for _, e := range readEntries(t, debugLog) {
+		if e["message"] == "noisy" && e["repeated_count"] == float64(1) {
+			summaries++
+		}
+	}
+	if summaries != 1 {
+		t.Errorf("%d summaries in the DEBUG file sink, want 1", summaries)
+	}
+}
@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxDemotionKeys bounds the number of messages a demoter tracks at once.
+// Messages arriving while it is reached, and no window has expired, are
+// never demoted.
+const maxDemotionKeys = 10000
+
+// WithErrorDemotion returns an option, for NewService or zap loggers, that
+// logs an ERROR entry at WARN once its message was logged at ERROR more than
+// threshold times within window of the first of them, with demoted_from and
+// repeated_count fields telling so. When the window closes the message is
+// logged at ERROR again. Unlike dedup every entry is still written, an error
+// loop just stops filling the error outputs and paging.
+func WithErrorDemotion(threshold int, window time.Duration) zap.Option {
+	return zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return newDemoteCore(core, threshold, window)
+	})
+}
+
+func newDemoteCore(core zapcore.Core, threshold int, window time.Duration) zapcore.Core {
+	d := &demoter{threshold: threshold, window: window, now: time.Now, seen: make(map[string]*demotion)}
+	return &demoteCore{Core: core, d: d}
+}
+
+// demotion is the window of a message.
+type demotion struct {
+	start time.Time
+	count int
+}
+
+type demoter struct {
+	threshold int
+	window    time.Duration
+	now       func() time.Time
+
+	mu   sync.Mutex
+	seen map[string]*demotion
+}
+
+// demote counts msg and reports whether it crossed the threshold in its
+// current window, and how often it was seen in it.
+func (d *demoter) demote(msg string) (bool, int) {
+	now := d.now()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	w, ok := d.seen[msg]
+	if !ok {
+		if len(d.seen) >= maxDemotionKeys {
+			d.sweep(now)
+			if len(d.seen) >= maxDemotionKeys {
+				return false, 0
+			}
+		}
+		w = &demotion{}
+		d.seen[msg] = w
+	}
+	if w.count == 0 || now.Sub(w.start) >= d.window {
+		w.start, w.count = now, 0
+	}
+	w.count++
+	return w.count > d.threshold, w.count
+}
+
+// sweep forgets the messages whose window expired. It must be called with
+// d.mu held.
+func (d *demoter) sweep(now time.Time) {
+	for msg, w := range d.seen {
+		if now.Sub(w.start) >= d.window {
+			delete(d.seen, msg)
+		}
+	}
+}
+
+type demoteCore struct {
+	zapcore.Core
+	d *demoter
+}
+
+func (c *demoteCore) With(fields []zapcore.Field) zapcore.Core {
+	return &demoteCore{Core: c.Core.With(fields), d: c.d}
+}
+
+func (c *demoteCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *demoteCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if ent.Level != ERROR {
+		return c.Core.Write(ent, fields)
+	}
+	if demote, count := c.d.demote(ent.Message); demote {
+		ent.Level = WARN
+		fields = append(fields[:len(fields):len(fields)],
+			zap.String("demoted_from", LevelName(ERROR)),
+			zap.Int("repeated_count", count))
+	}
+	return c.Core.Write(ent, fields)
+}
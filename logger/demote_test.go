@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestDemoteCore(t *testing.T) {
+	observed, logs := observer.New(allLevels)
+	core := newDemoteCore(observed, 2, time.Minute).(*demoteCore)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	core.d.now = func() time.Time { return now }
+	log := zap.New(core)
+
+	log.Error("db down")
+	log.Error("db down")
+	log.Error("other")
+	log.Error("db down")
+	log.Error("db down")
+	log.Warn("db down")
+	// the window closes, the message is an ERROR again
+	now = now.Add(time.Minute)
+	log.Error("db down")
+
+	want := []string{
+		"ERROR db down",
+		"ERROR db down",
+		"ERROR other",
+		"WARN db down demoted_from=ERROR repeated_count=3",
+		"WARN db down demoted_from=ERROR repeated_count=4",
+		"WARN db down",
+		"ERROR db down",
+	}
+	entries := logs.AllUntimed()
+	if len(entries) != len(want) {
+		t.Fatalf("logged %d entries, want %d", len(entries), len(want))
+	}
+	for i, e := range entries {
+		got := LevelName(e.Level) + " " + e.Message
+		for _, f := range e.Context {
+			got += fmt.Sprintf(" %s=%v", f.Key, e.ContextMap()[f.Key])
+		}
+		if got != want[i] {
+			t.Errorf("entry %d = %s, want %s", i, got, want[i])
+		}
+	}
+}
+
+func TestDemoterForgetsExpiredMessagesWhenFull(t *testing.T) {
+	now := time.Now()
+	d := &demoter{threshold: 1, window: time.Minute, now: func() time.Time { return now }, seen: make(map[string]*demotion)}
+	for i := 0; i < maxDemotionKeys; i++ {
+		d.demote(fmt.Sprint("message ", i))
+	}
+	d.demote("tracked")
+	if demote, count := d.demote("untracked"); demote || count != 0 {
+		t.Errorf("demote() = %v, %d with every key taken, want false, 0", demote, count)
+	}
+
+	now = now.Add(time.Minute)
+	d.demote("new")
+	if demote, count := d.demote("new"); !demote || count != 2 {
+		t.Errorf("demote() = %v, %d once the expired keys are swept, want true, 2", demote, count)
+	}
+}
+
+func TestErrorDemoteThreshold(t *testing.T) {
+	dir := t.TempDir()
+	errorLog := filepath.Join(dir, "error.log")
+	s, path := newFileService(t, config.Logger{ErrorLogFileName: errorLog, ErrorDemoteThreshold: 1})
+	for i := 0; i < 3; i++ {
+		s.Errorz("failed")
+	}
+	s.Sync()
+
+	var levels []string
+	for _, e := range readEntries(t, path) {
+		levels = append(levels, fmt.Sprint(e["level"]))
+	}
+	if got := strings.Join(levels, ","); got != "ERROR,WARN,WARN" {
+		t.Errorf("levels = %s, want ERROR,WARN,WARN", got)
+	}
+	if n := len(readEntries(t, errorLog)); n != 1 {
+		t.Errorf("error.log has %d entries, want only the one logged at ERROR", n)
+	}
+}
@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap"
+)
+
+func TestDualEncoding(t *testing.T) {
+	path := tempLog(t, "app.log")
+	out := captureStdout(t, func() {
+		svc, err := BuildService(config.Logger{LogFileName: path, DualEncoding: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		svc.Infoz("served", zap.String("user", "bob"))
+		svc.SetLevel("WARN")
+		svc.Infoz("dropped by both")
+		svc.Close()
+	})
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("stdout = %q, want one line", out)
+	}
+	if json.Valid([]byte(lines[0])) || !strings.Contains(lines[0], "\tINFO\t") || !strings.Contains(lines[0], "\tserved\t") || !strings.HasSuffix(lines[0], `{"user": "bob"}`) {
+		t.Errorf("stdout = %q, want the console encoding", lines[0])
+	}
+
+	entries := readEntries(t, path)
+	if len(entries) != 1 {
+		t.Fatalf("log file has %d entries, want 1", len(entries))
+	}
+	if e := entries[0]; e["level"] != "INFO" || e["message"] != "served" || e["user"] != "bob" {
+		t.Errorf("log file entry = %v, want the JSON encoding", e)
+	}
+}
+
+func TestDualEncodingColorsStdoutOnly(t *testing.T) {
+	path := tempLog(t, "app.log")
+	out := captureStdout(t, func() {
+		svc, err := BuildService(config.Logger{LogFileName: path, DualEncoding: true, ColorOutput: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		svc.Warnz("careful")
+		svc.Close()
+	})
+	if !strings.Contains(out, colorYellow+"WARN"+colorReset) {
+		t.Errorf("stdout = %q, want the colored level", out)
+	}
+	if data := readFile(t, path); strings.Contains(data, "\x1b[") {
+		t.Errorf("log file = %q, want no colors", data)
+	}
+}
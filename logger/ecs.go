@@ -0,0 +1,92 @@
+package logger
+
+import (
+	"fmt"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// ecsVersion is the version of the Elastic Common Schema the ecs encoding
+// follows.
+const ecsVersion = "8.11.0"
+
+// ecsEncoder writes entries as Elastic Common Schema JSON: @timestamp,
+// log.level, message, log.origin for the caller and ecs.version, with error
+// fields nested as error.message and error.type. Other fields are kept at the
+// top level as custom fields.
+type ecsEncoder struct {
+	zapcore.Encoder
+	caller bool
+}
+
+func newECSEncoder(cfg zapcore.EncoderConfig) zapcore.Encoder {
+	caller := cfg.CallerKey != ""
+	enc := zapcore.NewJSONEncoder(ecsEncoderConfig(cfg))
+	enc.AddString("ecs.version", ecsVersion)
+	return &ecsEncoder{Encoder: enc, caller: caller}
+}
+
+// ecsEncoderConfig adapts cfg to the ECS field names. The caller is left out,
+// EncodeEntry writes it as the log.origin object.
+func ecsEncoderConfig(cfg zapcore.EncoderConfig) zapcore.EncoderConfig {
+	cfg.TimeKey = "@timestamp"
+	cfg.EncodeTime = zapcore.ISO8601TimeEncoder
+	cfg.LevelKey = "log.level"
+	cfg.EncodeLevel = LowercaseLevelEncoder
+	cfg.MessageKey = "message"
+	cfg.NameKey = "log.logger"
+	cfg.StacktraceKey = ""
+	cfg.CallerKey = ""
+	return cfg
+}
+
+func (e *ecsEncoder) Clone() zapcore.Encoder {
+	return &ecsEncoder{Encoder: e.Encoder.Clone(), caller: e.caller}
+}
+
+func (e *ecsEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	ecsFields := make([]zapcore.Field, 0, len(fields)+1)
+	if e.caller && ent.Caller.Defined {
+		ecsFields = append(ecsFields, zapcore.Field{Key: "log", Type: zapcore.ObjectMarshalerType, Interface: ecsLog{ent.Caller}})
+	}
+	for _, f := range fields {
+		if f.Type == zapcore.ErrorType {
+			if err, ok := f.Interface.(error); ok {
+				f = zapcore.Field{Key: f.Key, Type: zapcore.ObjectMarshalerType, Interface: ecsError{err}}
+			}
+		}
+		ecsFields = append(ecsFields, f)
+	}
+	return e.Encoder.EncodeEntry(ent, ecsFields)
+}
+
+// ecsLog holds the log.origin of an entry.
+type ecsLog struct {
+	caller zapcore.EntryCaller
+}
+
+func (l ecsLog) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return enc.AddObject("origin", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+		enc.AddObject("file", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+			enc.AddString("name", l.caller.File)
+			enc.AddInt("line", l.caller.Line)
+			return nil
+		}))
+		if l.caller.Function != "" {
+			enc.AddString("function", l.caller.Function)
+		}
+		return nil
+	}))
+}
+
+// ecsError is an error as the ECS error object.
+type ecsError struct {
+	err error
+}
+
+func (e ecsError) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("message", e.err.Error())
+	enc.AddString("type", fmt.Sprintf("%T", e.err))
+	return nil
+}
@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap"
+)
+
+func TestECSEncoding(t *testing.T) {
+	svc, path := newFileService(t, config.Logger{Encoding: EncodingECS})
+	svc.Errorz("failed", zap.Error(fs.ErrNotExist), zap.String("user", "bob"))
+	svc.Sync()
+
+	entries := readEntries(t, path)
+	if len(entries) != 1 {
+		t.Fatalf("wrote %d entries, want 1", len(entries))
+	}
+	e := entries[0]
+	if e["ecs.version"] != ecsVersion || e["log.level"] != "error" || e["message"] != "failed" || e["user"] != "bob" {
+		t.Errorf("entry = %v", e)
+	}
+	if _, ok := e["@timestamp"].(string); !ok {
+		t.Errorf("entry = %v, want an @timestamp", e)
+	}
+	for _, key := range []string{"level", "time", "caller"} {
+		if _, ok := e[key]; ok {
+			t.Errorf("entry has the non ECS key %s", key)
+		}
+	}
+
+	origin, _ := e["log"].(map[string]interface{})["origin"].(map[string]interface{})
+	file, _ := origin["file"].(map[string]interface{})
+	if name, _ := file["name"].(string); !strings.HasSuffix(name, "ecs_test.go") || file["line"] == nil {
+		t.Errorf("log.origin = %v, want this file", origin)
+	}
+	if origin["function"] == nil {
+		t.Errorf("log.origin = %v, want the function", origin)
+	}
+
+	errObj, _ := e["error"].(map[string]interface{})
+	if errObj["message"] != fs.ErrNotExist.Error() || errObj["type"] != "*errors.errorString" {
+		t.Errorf("error = %v, want the ECS error object", e["error"])
+	}
+}
+
+func TestECSEncodingWithoutCaller(t *testing.T) {
+	svc, path := newFileService(t, config.Logger{Encoding: EncodingECS, DisableCaller: true})
+	svc.Infoz("no caller", zap.NamedError("cause", errors.New("boom")))
+	svc.Sync()
+
+	e := readEntries(t, path)[0]
+	if _, ok := e["log"]; ok {
+		t.Errorf("entry = %v, want no log.origin", e)
+	}
+	if cause, _ := e["cause"].(map[string]interface{}); cause["message"] != "boom" {
+		t.Errorf("cause = %v, want it as an ECS error object", e["cause"])
+	}
+}
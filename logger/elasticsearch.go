@@ -0,0 +1,119 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// elasticsearchBufferSize is the number of entries an ElasticsearchCore queues
+// before it starts dropping them.
+const elasticsearchBufferSize = 10000
+
+// ElasticsearchCore ships entries to Elasticsearch or OpenSearch through the
+// _bulk API. Use Close to send whatever is still buffered.
+type ElasticsearchCore struct {
+	*batchCore
+	failures atomic.Uint64
+}
+
+// NewElasticsearchCore returns a core indexing entries at or above minLevel
+// into index on the cluster at url. When index contains the reference year
+// 2006 it is used as a time layout against each entry's time, so
+// "logs-2006.01.02" writes to daily indices. Documents hold @timestamp, level,
+// message, caller and every field as a property. Entries are sent once 100 of
+// them are buffered or every second, once the buffer is full further entries
+// are dropped and counted, see Dropped.
+func NewElasticsearchCore(url, index string, minLevel zapcore.Level) *ElasticsearchCore {
+	url = strings.TrimSuffix(url, "/") + "/_bulk"
+	client := &http.Client{Timeout: 10 * time.Second}
+	c := &ElasticsearchCore{}
+
+	flush := func(batch []batchEntry) error {
+		var body bytes.Buffer
+		for _, e := range batch {
+			action, _ := json.Marshal(map[string]map[string]string{
+				"index": {"_index": esIndexName(index, e.Entry.Time)},
+			})
+			body.Write(action)
+			body.WriteByte('\n')
+			body.Write(e.Line)
+			body.WriteByte('\n')
+		}
+
+		failed, err := esBulk(client, url, &body)
+		if err != nil {
+			failed = len(batch)
+		}
+		c.failures.Add(uint64(failed))
+		return err
+	}
+
+	encoderConfig := newEncoderConfig()
+	encoderConfig.TimeKey = "@timestamp"
+	encoderConfig.EncodeTime = zapcore.RFC3339NanoTimeEncoder
+	b := newBatcher(defaultBatchSize, defaultBatchInterval, flush)
+	b.limit = elasticsearchBufferSize
+	c.batchCore = newBatchCore(zapcore.NewJSONEncoder(encoderConfig), minLevel, b)
+	return c
+}
+
+// Failures returns the number of entries Elasticsearch failed to index or
+// that could not be sent at all.
+func (c *ElasticsearchCore) Failures() uint64 {
+	return c.failures.Load()
+}
+
+// Dropped returns the number of entries dropped because the buffer was full.
+func (c *ElasticsearchCore) Dropped() uint64 {
+	return c.b.dropped.Load()
+}
+
+func esIndexName(index string, t time.Time) string {
+	if !strings.Contains(index, "2006") {
+		return index
+	}
+	return t.UTC().Format(index)
+}
+
+// esBulk sends body to the _bulk endpoint and returns the number of items
+// that failed to index.
+func esBulk(client *http.Client, url string, body *bytes.Buffer) (int, error) {
+	resp, err := client.Post(url, "application/x-ndjson", body)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return 0, fmt.Errorf("elasticsearch bulk: unexpected status %s", resp.Status)
+	}
+
+	var result struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Status int `json:"status"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("elasticsearch bulk: decoding response: %w", err)
+	}
+	if !result.Errors {
+		return 0, nil
+	}
+
+	failed := 0
+	for _, item := range result.Items {
+		for _, r := range item {
+			if r.Status >= 300 {
+				failed++
+			}
+		}
+	}
+	return failed, nil
+}
@@ -0,0 +1,152 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// fakeBulk is an Elasticsearch _bulk endpoint recording the request bodies it
+// receives and answering with respond.
+type fakeBulk struct {
+	t       *testing.T
+	respond func(w http.ResponseWriter)
+
+	mu     sync.Mutex
+	bodies []string
+}
+
+func (f *fakeBulk) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.URL.Path != "/_bulk" || r.Header.Get("Content-Type") != "application/x-ndjson" {
+		f.t.Errorf("request = %s %s %s, want POST /_bulk as NDJSON", r.Method, r.URL.Path, r.Header.Get("Content-Type"))
+	}
+	body, _ := io.ReadAll(r.Body)
+	f.mu.Lock()
+	f.bodies = append(f.bodies, string(body))
+	f.mu.Unlock()
+	f.respond(w)
+}
+
+func (f *fakeBulk) body() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return strings.Join(f.bodies, "")
+}
+
+func TestElasticsearchCoreSendsNDJSON(t *testing.T) {
+	bulk := &fakeBulk{t: t, respond: func(w http.ResponseWriter) {
+		io.WriteString(w, `{"errors":false,"items":[]}`)
+	}}
+	srv := httptest.NewServer(bulk)
+	defer srv.Close()
+
+	core := NewElasticsearchCore(srv.URL+"/", "logs-2006.01.02", INFO)
+	log := zap.New(core).With(zap.String("service", "api"))
+	at := time.Date(2024, 3, 1, 23, 30, 0, 0, time.UTC)
+	if ce := log.Check(WARN, "careful"); ce != nil {
+		ce.Time = at
+		ce.Write(zap.Int("attempt", 2))
+	}
+	log.Debug("not shipped")
+	if err := core.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// an action line naming the index of the day, then the document
+	scanner := bufio.NewScanner(strings.NewReader(bulk.body()))
+	var lines []map[string]interface{}
+	for scanner.Scan() {
+		var line map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("%v: %q", err, scanner.Text())
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("body = %q, want an action and a document", bulk.body())
+	}
+	if index, _ := lines[0]["index"].(map[string]interface{}); index["_index"] != "logs-2024.03.01" {
+		t.Errorf("action = %v, want index logs-2024.03.01", lines[0])
+	}
+	doc := lines[1]
+	if doc["@timestamp"] != "2024-03-01T23:30:00Z" || doc["level"] != "WARN" || doc["message"] != "careful" ||
+		doc["service"] != "api" || doc["attempt"] != float64(2) {
+		t.Errorf("document = %v", doc)
+	}
+	if core.Failures() != 0 {
+		t.Errorf("Failures() = %d, want 0", core.Failures())
+	}
+}
+
+func TestElasticsearchCoreCountsFailures(t *testing.T) {
+	bulk := &fakeBulk{t: t, respond: func(w http.ResponseWriter) {
+		io.WriteString(w, `{"errors":true,"items":[{"index":{"status":201}},{"index":{"status":400}},{"index":{"status":429}}]}`)
+	}}
+	srv := httptest.NewServer(bulk)
+	defer srv.Close()
+
+	core := NewElasticsearchCore(srv.URL, "logs", INFO)
+	for i := 0; i < 3; i++ {
+		core.Write(zapcore.Entry{Level: INFO, Message: "entry"}, nil)
+	}
+	core.Close()
+	if got := core.Failures(); got != 2 {
+		t.Errorf("Failures() = %d, want the 2 items rejected", got)
+	}
+}
+
+func TestElasticsearchCoreCountsUnsentEntries(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	core := NewElasticsearchCore(srv.URL, "logs", INFO)
+	core.Write(zapcore.Entry{Level: INFO, Message: "one"}, nil)
+	core.Write(zapcore.Entry{Level: INFO, Message: "two"}, nil)
+	if err := core.Close(); err == nil {
+		t.Error("Close() = nil, want the status error")
+	}
+	if got := core.Failures(); got != 2 {
+		t.Errorf("Failures() = %d, want 2", got)
+	}
+}
+
+func TestElasticsearchCoreDropsOnceTheBufferIsFull(t *testing.T) {
+	received := make(chan struct{}, 1)
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case received <- struct{}{}:
+		default:
+		}
+		<-release
+		io.WriteString(w, `{"errors":false,"items":[]}`)
+	}))
+	defer srv.Close()
+
+	core := NewElasticsearchCore(srv.URL, "logs", INFO)
+	log := zap.New(core)
+	// a full batch is sent at once, and Elasticsearch holds on to it
+	for i := 0; i < defaultBatchSize; i++ {
+		log.Info("batch")
+	}
+	<-received
+	for i := 0; i < elasticsearchBufferSize+5; i++ {
+		log.Info("queued")
+	}
+	if got := core.Dropped(); got != 5 {
+		t.Errorf("Dropped() = %d, want 5", got)
+	}
+	close(release)
+	core.Close()
+}
@@ -0,0 +1,223 @@
+package logger
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	EncodingJSON    = "json"
+	EncodingConsole = "console"
+	EncodingCSV     = "csv"
+	EncodingGCP     = "gcp"
+	EncodingECS     = "ecs"
+	EncodingLogfmt  = "logfmt"
+	EncodingDatadog = "datadog"
+)
+
+// Values of LineEnding.
+const (
+	LineEndingLF   = "lf"
+	LineEndingCRLF = "crlf"
+)
+
+// lineEnding returns the characters ending each entry for the LineEnding
+// setting name.
+func lineEnding(name string) string {
+	if name == LineEndingCRLF {
+		return "\r\n"
+	}
+	return zapcore.DefaultLineEnding
+}
+
+// Values of LevelEncoding. The color ones only color console output, other
+// encodings get the plain level.
+const (
+	LevelEncodingCapital        = "capital"
+	LevelEncodingLowercase      = "lowercase"
+	LevelEncodingCapitalColor   = "capitalColor"
+	LevelEncodingLowercaseColor = "lowercaseColor"
+)
+
+// ANSI foreground colors used for levels in console output.
+const (
+	colorRed     = "\x1b[31m"
+	colorYellow  = "\x1b[33m"
+	colorBlue    = "\x1b[34m"
+	colorMagenta = "\x1b[35m"
+	colorCyan    = "\x1b[36m"
+	colorReset   = "\x1b[0m"
+)
+
+var levelColors = map[zapcore.Level]string{
+	TRACE:  colorCyan,
+	DEBUG:  colorMagenta,
+	INFO:   colorBlue,
+	WARN:   colorYellow,
+	ERROR:  colorRed,
+	DPANIC: colorRed,
+	PANIC:  colorRed,
+	FATAL:  colorRed,
+}
+
+var shortLevelNames = map[zapcore.Level]string{
+	TRACE:  "T",
+	DEBUG:  "D",
+	INFO:   "I",
+	WARN:   "W",
+	ERROR:  "E",
+	DPANIC: "P",
+	PANIC:  "P",
+	FATAL:  "F",
+	AUDIT:  "A",
+}
+
+// LevelName returns the capitalized name of l, including TRACE and AUDIT
+// which zap itself would render as "LEVEL(-2)" and "LEVEL(7)".
+func LevelName(l zapcore.Level) string {
+	switch l {
+	case TRACE:
+		return "TRACE"
+	case AUDIT:
+		return "AUDIT"
+	}
+	return l.CapitalString()
+}
+
+// CapitalLevelEncoder serializes a level to an all-caps string, e.g. "INFO"
+// or "TRACE".
+func CapitalLevelEncoder(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	enc.AppendString(LevelName(l))
+}
+
+// LowercaseLevelEncoder serializes a level to a lowercase string, e.g.
+// "info" or "trace".
+func LowercaseLevelEncoder(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	enc.AppendString(lowercaseLevelName(l))
+}
+
+func lowercaseLevelName(l zapcore.Level) string {
+	return strings.ToLower(LevelName(l))
+}
+
+// CapitalColorLevelEncoder serializes a level to an all-caps string wrapped
+// in the ANSI color of the level, e.g. red for ERROR.
+func CapitalColorLevelEncoder(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	enc.AppendString(colorize(levelColors, l, LevelName(l)))
+}
+
+// CompactColorLevelEncoder serializes a level as a single colored letter,
+// e.g. "I" for INFO and "E" for ERROR.
+func CompactColorLevelEncoder(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	enc.AppendString(colorize(levelColors, l, shortLevelName(l)))
+}
+
+func shortLevelName(l zapcore.Level) string {
+	if name, ok := shortLevelNames[l]; ok {
+		return name
+	}
+	return LevelName(l)
+}
+
+// colorize wraps s in the color colors holds for l.
+func colorize(colors map[zapcore.Level]string, l zapcore.Level, s string) string {
+	color, ok := colors[l]
+	if !ok {
+		return s
+	}
+	return color + s + colorReset
+}
+
+// newColorLevelEncoder returns a level encoder writing name(l) in the color
+// colors holds for l.
+func newColorLevelEncoder(colors map[zapcore.Level]string, name func(zapcore.Level) string) zapcore.LevelEncoder {
+	return func(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+		enc.AppendString(colorize(colors, l, name(l)))
+	}
+}
+
+// newTrimmedCallerEncoder returns a caller encoder writing the path of the
+// caller with prefix trimmed, e.g. "pkg/file.go:42" for the prefix
+// "/home/ci/src/github.com/org/repo/", and falling back to the short
+// "package/file.go:42" form for paths outside of prefix.
+func newTrimmedCallerEncoder(prefix string) zapcore.CallerEncoder {
+	return func(caller zapcore.EntryCaller, enc zapcore.PrimitiveArrayEncoder) {
+		if !caller.Defined {
+			enc.AppendString("undefined")
+			return
+		}
+		if path, ok := strings.CutPrefix(caller.File, prefix); ok {
+			enc.AppendString(path + ":" + strconv.Itoa(caller.Line))
+			return
+		}
+		zapcore.ShortCallerEncoder(caller, enc)
+	}
+}
+
+// levelColorsFor returns the default level colors with the valid entries of
+// conf.LevelColors applied on top. Invalid entries are left to Validate.
+func levelColorsFor(conf *config.Logger) map[zapcore.Level]string {
+	if len(conf.LevelColors) == 0 {
+		return levelColors
+	}
+	colors := make(map[zapcore.Level]string, len(levelColors))
+	for l, c := range levelColors {
+		colors[l] = c
+	}
+	for name, code := range conf.LevelColors {
+		if config.ValidColor(code) {
+			colors[GetLevel(name)] = "\x1b[" + code + "m"
+		}
+	}
+	return colors
+}
+
+// newEncoder builds the encoder selected by conf on top of encoderConfig.
+func newEncoder(conf *config.Logger, encoderConfig zapcore.EncoderConfig) zapcore.Encoder {
+	// compact lines lead with the level letter, so the timestamp is dropped
+	if conf.CompactConsole {
+		encoderConfig.TimeKey = ""
+		if conf.ColorOutput || conf.LevelEncoding == LevelEncodingCapitalColor || conf.LevelEncoding == LevelEncodingLowercaseColor {
+			encoderConfig.EncodeLevel = newColorLevelEncoder(levelColorsFor(conf), shortLevelName)
+		} else {
+			encoderConfig.EncodeLevel = func(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+				enc.AppendString(shortLevelName(l))
+			}
+		}
+		return zapcore.NewConsoleEncoder(encoderConfig)
+	}
+
+	levelName := LevelName
+	if conf.LevelEncoding == LevelEncodingLowercase || conf.LevelEncoding == LevelEncodingLowercaseColor {
+		levelName = lowercaseLevelName
+		encoderConfig.EncodeLevel = LowercaseLevelEncoder
+	}
+
+	switch conf.Encoding {
+	case EncodingConsole:
+		// colors would corrupt structured output, so only console gets them
+		if conf.ColorOutput || conf.LevelEncoding == LevelEncodingCapitalColor || conf.LevelEncoding == LevelEncodingLowercaseColor {
+			encoderConfig.EncodeLevel = newColorLevelEncoder(levelColorsFor(conf), levelName)
+		}
+		return zapcore.NewConsoleEncoder(encoderConfig)
+	case EncodingCSV:
+		return newCSVEncoder(encoderConfig, conf.CSVColumns)
+	case EncodingGCP:
+		return newMessageKeyEncoder(gcpEncoderConfig(encoderConfig), zapcore.NewJSONEncoder)
+	case EncodingECS:
+		return newECSEncoder(encoderConfig)
+	case EncodingDatadog:
+		return newMessageKeyEncoder(datadogEncoderConfig(encoderConfig), zapcore.NewJSONEncoder)
+	case EncodingLogfmt:
+		return newLogfmtEncoder(encoderConfig)
+	default:
+		encoder := newMessageKeyEncoder(encoderConfig, zapcore.NewJSONEncoder)
+		if conf.PrettyPrint {
+			return prettyEncoder{encoder, encoderConfig.LineEnding}
+		}
+		return encoder
+	}
+}
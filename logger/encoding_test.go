@@ -0,0 +1,187 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dazzling420/go-logger/config"
+)
+
+func TestLevelColorsWrapTheLevel(t *testing.T) {
+	out := captureStdout(t, func() {
+		svc, err := BuildService(config.Logger{
+			Encoding:    EncodingConsole,
+			ColorOutput: true,
+			LevelColors: map[string]string{"WARN": "38;5;208"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		svc.Warnz("orange")
+		svc.Errorz("red")
+		svc.Close()
+	})
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("stdout = %q, want 2 lines", out)
+	}
+	if want := "\x1b[38;5;208mWARN\x1b[0m"; !strings.Contains(lines[0], want) {
+		t.Errorf("WARN line = %q, want it to contain %q", lines[0], want)
+	}
+	if want := colorRed + "ERROR" + colorReset; !strings.Contains(lines[1], want) {
+		t.Errorf("ERROR line = %q, want the default color %q", lines[1], want)
+	}
+}
+
+func TestLevelColorsForSkipsInvalidColors(t *testing.T) {
+	colors := levelColorsFor(&config.Logger{LevelColors: map[string]string{"WARN": "orange", "INFO": "32"}})
+	if colors[WARN] != colorYellow {
+		t.Errorf("WARN color = %q, want the default for an invalid code", colors[WARN])
+	}
+	if colors[INFO] != "\x1b[32m" {
+		t.Errorf("INFO color = %q, want \\x1b[32m", colors[INFO])
+	}
+}
+
+func TestCompactConsole(t *testing.T) {
+	out := captureStdout(t, func() {
+		svc, err := BuildService(config.Logger{Encoding: EncodingConsole, CompactConsole: true, ColorOutput: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		svc.Infoz("started", String("port", "80"))
+		svc.Errorz("failed")
+		svc.Audit("audited")
+		svc.Close()
+	})
+
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("stdout = %q, want 3 lines", out)
+	}
+	for i, want := range []struct{ prefix, suffix string }{
+		{colorBlue + "I" + colorReset + "\t", "\tstarted\t{\"port\": \"80\"}"},
+		{colorRed + "E" + colorReset + "\t", "\tfailed"},
+		{"A\t", "\taudited"},
+	} {
+		if !strings.HasPrefix(lines[i], want.prefix) || !strings.HasSuffix(lines[i], want.suffix) {
+			t.Errorf("line %d = %q, want %q...%q without a timestamp", i, lines[i], want.prefix, want.suffix)
+		}
+	}
+}
+
+func TestCompactConsoleWithoutColors(t *testing.T) {
+	out := captureStdout(t, func() {
+		svc, err := BuildService(config.Logger{Encoding: EncodingConsole, CompactConsole: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		svc.Errorz("failed")
+		svc.Close()
+	})
+	if !strings.HasPrefix(out, "E\t") || strings.Contains(out, "\x1b[") {
+		t.Errorf("stdout = %q, want an uncolored level letter", out)
+	}
+}
+
+func TestConsoleEncoding(t *testing.T) {
+	out := captureStdout(t, func() {
+		svc, err := BuildService(config.Logger{Encoding: EncodingConsole})
+		if err != nil {
+			t.Fatal(err)
+		}
+		svc.Infoz("started", String("port", "80"))
+		svc.Close()
+	})
+
+	fields := strings.Split(strings.TrimSuffix(out, "\n"), "\t")
+	if len(fields) != 5 || fields[1] != "INFO" || fields[3] != "started" || fields[4] != `{"port": "80"}` {
+		t.Errorf("stdout = %q, want time, level, caller, message and fields tab separated", out)
+	}
+}
+
+func TestColoredLevels(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		conf config.Logger
+		want string
+	}{
+		{"color_output", config.Logger{Encoding: EncodingConsole, ColorOutput: true}, colorYellow + "WARN" + colorReset},
+		{"capitalColor", config.Logger{Encoding: EncodingConsole, LevelEncoding: LevelEncodingCapitalColor}, colorYellow + "WARN" + colorReset},
+		{"lowercaseColor", config.Logger{Encoding: EncodingConsole, LevelEncoding: LevelEncodingLowercaseColor}, colorYellow + "warn" + colorReset},
+		{"plain console", config.Logger{Encoding: EncodingConsole}, "\tWARN\t"},
+		{"json", config.Logger{ColorOutput: true}, `"level":"WARN"`},
+	} {
+		out := captureStdout(t, func() {
+			svc, err := BuildService(tt.conf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			svc.Warnz("careful")
+			svc.Close()
+		})
+		if !strings.Contains(out, tt.want) {
+			t.Errorf("%s: stdout = %q, want it to contain %q", tt.name, out, tt.want)
+		}
+		if tt.name == "plain console" || tt.name == "json" {
+			if strings.Contains(out, "\x1b[") {
+				t.Errorf("%s: stdout = %q, want no colors", tt.name, out)
+			}
+		}
+	}
+}
+
+func TestLevelEncoding(t *testing.T) {
+	for encoding, want := range map[string]string{
+		"":                          "TRACE,INFO,AUDIT",
+		LevelEncodingCapital:        "TRACE,INFO,AUDIT",
+		LevelEncodingLowercase:      "trace,info,audit",
+		LevelEncodingLowercaseColor: "trace,info,audit", // json stays plain
+	} {
+		svc, path := newFileService(t, config.Logger{LevelEncoding: encoding, LoggingLevel: "TRACE"})
+		svc.Tracez("trace")
+		svc.Infoz("info")
+		svc.Audit("audit")
+		svc.Sync()
+
+		var levels []string
+		for _, e := range readEntries(t, path) {
+			levels = append(levels, e["level"].(string))
+		}
+		if got := strings.Join(levels, ","); got != want {
+			t.Errorf("level_encoding %q wrote %s, want %s", encoding, got, want)
+		}
+	}
+}
+
+func TestLineEnding(t *testing.T) {
+	for name, conf := range map[string]config.Logger{
+		EncodingJSON:    {Encoding: EncodingJSON},
+		EncodingConsole: {Encoding: EncodingConsole},
+		EncodingLogfmt:  {Encoding: EncodingLogfmt},
+		EncodingGCP:     {Encoding: EncodingGCP},
+		"pretty_print":  {PrettyPrint: true},
+	} {
+		for ending, want := range map[string]string{"": "\n", LineEndingLF: "\n", LineEndingCRLF: "\r\n"} {
+			t.Run(name+"/"+ending, func(t *testing.T) {
+				conf.LineEnding = ending
+				svc, path := newFileService(t, conf)
+				svc.Infoz("first")
+				svc.Infoz("second")
+				svc.Sync()
+
+				got := readFile(t, path)
+				if !strings.HasSuffix(got, want) {
+					t.Fatalf("file = %q, want it to end with %q", got, want)
+				}
+				if n := strings.Count(got, want); n != 2 && name != "pretty_print" {
+					t.Errorf("file = %q, want 2 entries ended with %q", got, want)
+				}
+				if want == "\n" && strings.Contains(got, "\r") {
+					t.Errorf("file = %q, want no carriage returns", got)
+				}
+			})
+		}
+	}
+}
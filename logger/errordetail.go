@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"errors"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// FieldsError is implemented by errors carrying fields of their own, which
+// ErrorDetail adds to the detail of every error wrapping them.
+type FieldsError interface {
+	error
+	LogFields() []Field
+}
+
+// ErrorDetail returns an error_detail field describing err layer by layer,
+// following errors.Unwrap: a chain array holding the message each layer adds,
+// outermost first, e.g. ["query users", "dial tcp", "connection refused"] for
+// the errors wrapped with fmt.Errorf("query users: %w", ...), and the fields of
+// every layer implementing FieldsError. Only the first error of the ones
+// joined with errors.Join is followed. A nil err adds no field.
+func ErrorDetail(err error) Field {
+	if isNilError(err) {
+		return zap.Skip()
+	}
+	return zap.Object("error_detail", errorDetail{err})
+}
+
+type errorDetail struct {
+	err error
+}
+
+func (d errorDetail) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	layers := errorChain(d.err)
+	err := enc.AddArray("chain", zapcore.ArrayMarshalerFunc(func(arr zapcore.ArrayEncoder) error {
+		for i, e := range layers {
+			msg := e.Error()
+			// the message of a layer usually ends with the one of the next
+			if i+1 < len(layers) {
+				if inner := layers[i+1].Error(); inner != msg {
+					msg = strings.TrimSuffix(strings.TrimSuffix(msg, inner), ": ")
+				}
+			}
+			arr.AppendString(msg)
+		}
+		return nil
+	}))
+	for _, e := range layers {
+		if fe, ok := e.(FieldsError); ok {
+			for _, f := range fe.LogFields() {
+				f.AddTo(enc)
+			}
+		}
+	}
+	return err
+}
+
+// errorChain returns err followed by the errors it wraps.
+func errorChain(err error) []error {
+	var layers []error
+	for err != nil && len(layers) < maxErrorChain {
+		layers = append(layers, err)
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			if errs := joined.Unwrap(); len(errs) > 0 {
+				err = errs[0]
+				continue
+			}
+		}
+		err = errors.Unwrap(err)
+	}
+	return layers
+}
+
+// maxErrorChain bounds the layers ErrorDetail follows, in case an error
+// wraps itself.
+const maxErrorChain = 32
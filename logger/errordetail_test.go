@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// queryError is a FieldsError.
+type queryError struct {
+	table string
+	err   error
+}
+
+func (e *queryError) Error() string      { return "query " + e.table + ": " + e.err.Error() }
+func (e *queryError) Unwrap() error      { return e.err }
+func (e *queryError) LogFields() []Field { return []Field{zap.String("table", e.table)} }
+
+// loopError wraps itself.
+type loopError struct{}
+
+func (e *loopError) Error() string { return "loop" }
+func (e *loopError) Unwrap() error { return e }
+
+// errorDetailOf returns the error_detail field of err encoded to a map.
+func errorDetailOf(t *testing.T, err error) map[string]interface{} {
+	t.Helper()
+	enc := zapcore.NewMapObjectEncoder()
+	ErrorDetail(err).AddTo(enc)
+	detail, _ := enc.Fields["error_detail"].(map[string]interface{})
+	return detail
+}
+
+func TestErrorDetail(t *testing.T) {
+	refused := errors.New("connection refused")
+	err := fmt.Errorf("list users: %w", &queryError{table: "users", err: fmt.Errorf("dial tcp: %w", refused)})
+
+	detail := errorDetailOf(t, err)
+	want := []interface{}{"list users", "query users", "dial tcp", "connection refused"}
+	if !reflect.DeepEqual(detail["chain"], want) {
+		t.Errorf("chain = %v, want %v", detail["chain"], want)
+	}
+	if detail["table"] != "users" {
+		t.Errorf("detail = %v, want the table of the query error", detail)
+	}
+}
+
+func TestErrorDetailLayersNotEndingWithTheNext(t *testing.T) {
+	err := fmt.Errorf("%w, giving up", errors.New("timeout"))
+	want := []interface{}{"timeout, giving up", "timeout"}
+	if got := errorDetailOf(t, err)["chain"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("chain = %v, want %v", got, want)
+	}
+}
+
+func TestErrorDetailFollowsTheFirstJoinedError(t *testing.T) {
+	err := fmt.Errorf("close: %w", errors.Join(errors.New("flush failed"), errors.New("sync failed")))
+	chain, _ := errorDetailOf(t, err)["chain"].([]interface{})
+	if len(chain) != 3 || chain[0] != "close" || chain[2] != "flush failed" {
+		t.Errorf("chain = %q, want close, the joined errors and flush failed", chain)
+	}
+}
+
+func TestErrorDetailBoundsTheChain(t *testing.T) {
+	chain, _ := errorDetailOf(t, &loopError{})["chain"].([]interface{})
+	if len(chain) != maxErrorChain {
+		t.Errorf("chain of %d layers, want %d", len(chain), maxErrorChain)
+	}
+}
+
+func TestErrorDetailOfNil(t *testing.T) {
+	var nilPointer *queryError
+	for _, err := range []error{nil, nilPointer} {
+		if f := ErrorDetail(err); f.Type != zapcore.SkipType {
+			t.Errorf("ErrorDetail(%#v) = %v, want no field", err, f)
+		}
+	}
+}
+
+func TestErrorWithResponseDescribesTheChain(t *testing.T) {
+	svc, logs := NewTestLogger()
+	svc.(*standardLogger).ErrorWithResponse("request failed:", fmt.Errorf("dial tcp: %w", errors.New("connection refused")))
+
+	fields := logs.All()[0].ContextMap()
+	if fields["response_message"] != "dial tcp: connection refused" {
+		t.Errorf("response_message = %v", fields["response_message"])
+	}
+	detail, _ := fields["error_detail"].(map[string]interface{})
+	if want := []interface{}{"dial tcp", "connection refused"}; !reflect.DeepEqual(detail["chain"], want) {
+		t.Errorf("error_detail = %v, want the chain %v", fields["error_detail"], want)
+	}
+}
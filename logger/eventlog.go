@@ -0,0 +1,82 @@
+//go:build windows
+
+package logger
+
+import (
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/sys/windows/svc/eventlog"
+)
+
+// eventLogID is the id of every event written, the message is in the event
+// data.
+const eventLogID = 1
+
+// eventWriter is the part of *eventlog.Log eventLogCore uses.
+type eventWriter interface {
+	Info(eid uint32, msg string) error
+	Warning(eid uint32, msg string) error
+	Error(eid uint32, msg string) error
+}
+
+// eventLogCore writes encoded entries to the Windows event log, mapping
+// levels to the Information, Warning and Error event types.
+type eventLogCore struct {
+	zapcore.LevelEnabler
+	enc zapcore.Encoder
+	log eventWriter
+}
+
+// newEventLogCore opens the event log for source, registering the source
+// first when it isn't yet, which needs administrator rights. The returned
+// function closes the event log.
+func newEventLogCore(source string, enc zapcore.Encoder, enab zapcore.LevelEnabler) (zapcore.Core, func() error, error) {
+	err := eventlog.InstallAsEventCreate(source, eventlog.Error|eventlog.Warning|eventlog.Info)
+	// eventlog has no error value for a source registered already
+	if err != nil && !strings.HasSuffix(err.Error(), "registry key already exists") {
+		return nil, nil, err
+	}
+	log, err := eventlog.Open(source)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &eventLogCore{LevelEnabler: enab, enc: enc, log: log}, log.Close, nil
+}
+
+func (c *eventLogCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return &eventLogCore{LevelEnabler: c.LevelEnabler, enc: enc, log: c.log}
+}
+
+func (c *eventLogCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *eventLogCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	msg := strings.TrimRight(buf.String(), "\r\n")
+	buf.Free()
+
+	switch {
+	case ent.Level == AUDIT || ent.Level < WARN:
+		return c.log.Info(eventLogID, msg)
+	case ent.Level == WARN:
+		return c.log.Warning(eventLogID, msg)
+	default:
+		return c.log.Error(eventLogID, msg)
+	}
+}
+
+func (c *eventLogCore) Sync() error {
+	return nil
+}
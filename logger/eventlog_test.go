@@ -0,0 +1,50 @@
+//go:build windows
+
+package logger
+
+import (
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// fakeEventLog records the events written, by type.
+type fakeEventLog struct {
+	events []string
+}
+
+func (l *fakeEventLog) record(typ, msg string) error {
+	l.events = append(l.events, typ+" "+msg)
+	return nil
+}
+
+func (l *fakeEventLog) Info(eid uint32, msg string) error    { return l.record("Information", msg) }
+func (l *fakeEventLog) Warning(eid uint32, msg string) error { return l.record("Warning", msg) }
+func (l *fakeEventLog) Error(eid uint32, msg string) error   { return l.record("Error", msg) }
+
+func TestEventLogCoreEventTypes(t *testing.T) {
+	events := &fakeEventLog{}
+	encoderConfig := newEncoderConfig()
+	encoderConfig.TimeKey = ""
+	core := &eventLogCore{LevelEnabler: allLevels, enc: zapcore.NewJSONEncoder(encoderConfig), log: events}
+	log := zap.New(core).With(zap.String("service", "api"))
+
+	log.Debug("debug")
+	log.Info("info")
+	log.Warn("warn")
+	log.Error("error")
+	log.Check(AUDIT, "audit").Write()
+
+	want := []string{
+		`Information {"level":"DEBUG","message":"debug","service":"api"}`,
+		`Information {"level":"INFO","message":"info","service":"api"}`,
+		`Warning {"level":"WARN","message":"warn","service":"api"}`,
+		`Error {"level":"ERROR","message":"error","service":"api"}`,
+		`Information {"level":"AUDIT","message":"audit","service":"api"}`,
+	}
+	if got := strings.Join(events.events, "\n"); got != strings.Join(want, "\n") {
+		t.Errorf("events =\n%s\nwant\n%s", got, strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package logger
+
+import (
+	"errors"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func newEventLogCore(source string, enc zapcore.Encoder, enab zapcore.LevelEnabler) (zapcore.Core, func() error, error) {
+	return nil, nil, errors.New("the Windows event log is not supported on this platform")
+}
@@ -0,0 +1,20 @@
+//go:build !windows
+
+package logger
+
+import (
+	"testing"
+
+	"github.com/dazzling420/go-logger/config"
+)
+
+func TestEventLogSourceUnsupported(t *testing.T) {
+	s, path := newFileService(t, config.Logger{EventLogSource: "MyService"})
+	s.Infoz("still logged")
+	s.Sync()
+
+	got := messages(readEntries(t, path))
+	if len(got) != 2 || got[0] != "Was unable to open the Windows event log, running without it!" || got[1] != "still logged" {
+		t.Errorf("logged %q, want the event log error and the entry", got)
+	}
+}
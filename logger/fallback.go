@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// fallbackRetryInterval is how long a fallbackSink keeps writing to its
+// fallback before trying its primary again.
+const fallbackRetryInterval = 10 * time.Second
+
+// fallbackSink writes to primary and, when a write to it fails, writes the
+// same bytes to fallback instead, e.g. stderr when the disk of a log file is
+// full. Writes then go to fallback for retryInterval, after which primary is
+// tried again.
+type fallbackSink struct {
+	primary       zapcore.WriteSyncer
+	fallback      zapcore.WriteSyncer
+	retryInterval time.Duration
+	now           func() time.Time
+
+	mu       sync.Mutex
+	retryAt  time.Time // primary is skipped until then, zero while it works
+	failures atomic.Uint64
+}
+
+func newFallbackSink(primary, fallback zapcore.WriteSyncer, retryInterval time.Duration) *fallbackSink {
+	return &fallbackSink{
+		primary:       primary,
+		fallback:      fallback,
+		retryInterval: retryInterval,
+		now:           time.Now,
+	}
+}
+
+func (s *fallbackSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.retryAt.IsZero() || !s.now().Before(s.retryAt) {
+		n, err := s.primary.Write(p)
+		if err == nil {
+			s.retryAt = time.Time{}
+			return n, nil
+		}
+		s.failures.Add(1)
+		s.retryAt = s.now().Add(s.retryInterval)
+	}
+	return s.fallback.Write(p)
+}
+
+func (s *fallbackSink) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.retryAt.IsZero() {
+		return s.fallback.Sync()
+	}
+	return s.primary.Sync()
+}
+
+// Failures returns the number of writes to the primary output that failed.
+func (s *fallbackSink) Failures() uint64 {
+	return s.failures.Load()
+}
+
+// FallbackFailures returns the number of writes to the log files that failed
+// and went to stderr instead, see config.Logger.FallbackToStderr. It is zero
+// for loggers not built by NewService.
+func (s *standardLogger) FallbackFailures() uint64 {
+	var n uint64
+	if s.sinks != nil {
+		n += s.sinks.fallbackFailures()
+	}
+	if s.logFile != nil {
+		n += s.logFile.fallbackFailures()
+	}
+	return n
+}
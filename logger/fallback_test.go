@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap/zapcore"
+)
+
+// failingSink fails its writes while failing is set.
+type failingSink struct {
+	bytes.Buffer
+	failing bool
+}
+
+func (s *failingSink) Write(p []byte) (int, error) {
+	if s.failing {
+		return 0, errors.New("disk full")
+	}
+	return s.Buffer.Write(p)
+}
+
+func (s *failingSink) Sync() error { return nil }
+
+func TestFallbackSink(t *testing.T) {
+	primary := &failingSink{failing: true}
+	var fallback bytes.Buffer
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	s := newFallbackSink(primary, zapcore.AddSync(&fallback), time.Minute)
+	s.now = func() time.Time { return now }
+
+	if n, err := s.Write([]byte("lost\n")); n != 5 || err != nil {
+		t.Errorf("Write() = %d, %v, want the fallback's result", n, err)
+	}
+	primary.failing = false
+	s.Write([]byte("still falling back\n"))
+	now = now.Add(time.Minute)
+	s.Write([]byte("back\n"))
+
+	if got := fallback.String(); got != "lost\nstill falling back\n" {
+		t.Errorf("fallback = %q", got)
+	}
+	if got := primary.String(); got != "back\n" {
+		t.Errorf("primary = %q, want the write after the retry interval", got)
+	}
+	if got := s.Failures(); got != 1 {
+		t.Errorf("Failures() = %d, want 1", got)
+	}
+}
+
+func TestFallbackToStderr(t *testing.T) {
+	// a regular file where the directory of the log file should be
+	notADir := tempLog(t, "file")
+	if err := os.WriteFile(notADir, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stderr := captureStderr(t, func() {
+		svc, err := BuildService(config.Logger{
+			LogFileName:      filepath.Join(notADir, "app.log"),
+			DisableStdout:    true,
+			FallbackToStderr: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		svc.Infoz("rescued")
+		// the first failed write sends the rest to stderr until the retry
+		if got := svc.FallbackFailures(); got != 1 {
+			t.Errorf("FallbackFailures() = %d, want 1", got)
+		}
+		svc.Close()
+	})
+	if !strings.Contains(stderr, `"message":"rescued"`) {
+		t.Errorf("stderr = %q, want the entry", stderr)
+	}
+}
+
+func TestFallbackFailuresWithoutFailures(t *testing.T) {
+	svc, _ := newFileService(t, config.Logger{FallbackToStderr: true})
+	svc.Infoz("written")
+	if got := svc.FallbackFailures(); got != 0 {
+		t.Errorf("FallbackFailures() = %d, want 0", got)
+	}
+}
@@ -0,0 +1,25 @@
+package logger
+
+import (
+	"os"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// OnFatal returns an option, for NewService or zap loggers, running f once a
+// Fatal entry has been written and before the process exits, e.g. to release
+// resources or stop accepting work. To not exit at all, pass
+// zap.WithFatalHook with zapcore.WriteThenPanic or a custom
+// zapcore.CheckWriteHook instead; the Fatal methods then return once it has
+// run, which lets tests exercise fatal paths.
+func OnFatal(f func()) zap.Option {
+	return zap.WithFatalHook(fatalHook(f))
+}
+
+type fatalHook func()
+
+func (f fatalHook) OnWrite(*zapcore.CheckedEntry, []zapcore.Field) {
+	f()
+	os.Exit(1)
+}
@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// recordingHook records the entries it is run for instead of exiting.
+type recordingHook struct {
+	entries []string
+}
+
+func (h *recordingHook) OnWrite(ce *zapcore.CheckedEntry, _ []zapcore.Field) {
+	h.entries = append(h.entries, ce.Message)
+}
+
+func TestOnFatal(t *testing.T) {
+	if os.Getenv("LOGGER_TEST_ON_FATAL") == "1" {
+		svc, err := BuildService(config.Logger{}, OnFatal(func() { os.Stdout.WriteString("\nreleased\n") }))
+		if err != nil {
+			t.Fatal(err)
+		}
+		svc.Fatalz("giving up")
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestOnFatal$")
+	cmd.Env = append(os.Environ(), "LOGGER_TEST_ON_FATAL=1")
+	out, err := cmd.Output()
+	var exit *exec.ExitError
+	if !errors.As(err, &exit) || exit.ExitCode() != 1 {
+		t.Fatalf("the process ended with %v, want exit status 1", err)
+	}
+	logged := strings.Index(string(out), "giving up")
+	released := strings.Index(string(out), "released")
+	if logged < 0 || released < logged {
+		t.Errorf("output = %q, want the entry then the hook", out)
+	}
+}
+
+func TestReplacedFatalHookReturns(t *testing.T) {
+	hook := &recordingHook{}
+	svc, path := newFileService(t, config.Logger{}, zap.WithFatalHook(hook))
+	useGlobal(t, svc)
+
+	svc.Fatalz("method")
+	Fatalf("package %s", "function")
+	svc.Sync()
+
+	if got := strings.Join(hook.entries, ","); got != "method,package function" {
+		t.Errorf("hook ran for %s, want both entries", got)
+	}
+	if got := messages(readEntries(t, path)); len(got) != 2 {
+		t.Errorf("wrote %q, want both entries", got)
+	}
+}
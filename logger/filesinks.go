@@ -0,0 +1,60 @@
+package logger
+
+import (
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap/zapcore"
+)
+
+// newFileSinkCores builds a core per conf.FileSinks entry, each with its own
+// level and encoder config, and returns them together with the lowest level
+// any of them wants stacktraces for (zapcore.InvalidLevel for none).
+func newFileSinkCores(conf *config.Logger, encoderConfig zapcore.EncoderConfig, sinks *sinkSet) ([]zapcore.Core, zapcore.Level) {
+	var cores []zapcore.Core
+	stackLevel := zapcore.InvalidLevel
+	for _, fs := range conf.FileSinks {
+		cfg := encoderConfig
+		if !fs.IncludeCaller {
+			cfg.CallerKey = ""
+		} else if cfg.CallerKey == "" {
+			cfg.CallerKey = "caller"
+		}
+
+		sinkStackLevel := zapcore.InvalidLevel
+		if fs.StacktraceLevel != "" {
+			sinkStackLevel = GetLevel(fs.StacktraceLevel)
+			cfg.StacktraceKey = "stacktrace"
+			if sinkStackLevel < stackLevel {
+				stackLevel = sinkStackLevel
+			}
+		}
+
+		core := zapcore.NewCore(newEncoder(conf, cfg), sinks.openWithHeader(fs.FileName, csvHeader(conf, cfg)), GetLevel(fs.Level))
+		cores = append(cores, &stackLevelCore{Core: core, level: sinkStackLevel})
+	}
+	return cores, stackLevel
+}
+
+// stackLevelCore drops the stacktrace of entries below level, as the logger
+// captures stacks for the lowest level any output wants them for.
+type stackLevelCore struct {
+	zapcore.Core
+	level zapcore.Level
+}
+
+func (c *stackLevelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &stackLevelCore{Core: c.Core.With(fields), level: c.level}
+}
+
+func (c *stackLevelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *stackLevelCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if ent.Level < c.level {
+		ent.Stack = ""
+	}
+	return c.Core.Write(ent, fields)
+}
@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dazzling420/go-logger/config"
+)
+
+func TestFileSinks(t *testing.T) {
+	dir := t.TempDir()
+	debug, errs := filepath.Join(dir, "debug.log"), filepath.Join(dir, "errors.log")
+	svc, _ := newFileService(t, config.Logger{
+		LoggingLevel: "DEBUG",
+		FileSinks: []config.FileSink{
+			{FileName: debug, Level: "DEBUG", IncludeCaller: true, StacktraceLevel: "ERROR"},
+			{FileName: errs, Level: "WARN"},
+		},
+	})
+	svc.Debugz("debug")
+	svc.Warnz("warn")
+	svc.Errorz("error")
+	svc.Sync()
+
+	entries := readEntries(t, debug)
+	if got := strings.Join(messages(entries), ","); got != "debug,warn,error" {
+		t.Fatalf("debug.log = %s, want debug,warn,error", got)
+	}
+	for _, e := range entries {
+		if e["caller"] == nil {
+			t.Errorf("%v has no caller in debug.log", e["message"])
+		}
+		if _, ok := e["stacktrace"]; ok != (e["message"] == "error") {
+			t.Errorf("%v has a stacktrace: %v, want one for error alone", e["message"], ok)
+		}
+	}
+
+	entries = readEntries(t, errs)
+	if got := strings.Join(messages(entries), ","); got != "warn,error" {
+		t.Fatalf("errors.log = %s, want warn,error", got)
+	}
+	for _, e := range entries {
+		if e["caller"] != nil || e["stacktrace"] != nil {
+			t.Errorf("%v has a caller or stacktrace in errors.log", e["message"])
+		}
+	}
+}
+
+func TestFileSinksKeepTheMainOutputAsIs(t *testing.T) {
+	svc, path := newFileService(t, config.Logger{
+		FileSinks: []config.FileSink{{FileName: tempLog(t, "debug.log"), Level: "DEBUG", StacktraceLevel: "WARN"}},
+	})
+	svc.Warnz("warn")
+	svc.Sync()
+
+	entries := readEntries(t, path)
+	if len(entries) != 1 {
+		t.Fatalf("app.log = %v, want the warning", entries)
+	}
+	if _, ok := entries[0]["stacktrace"]; ok {
+		t.Error("the main output got the stacktrace of a file sink")
+	}
+}
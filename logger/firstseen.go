@@ -0,0 +1,11 @@
+package logger
+
+import "go.uber.org/zap"
+
+// FirstSeen logs msg at INFO with a first_seen field that is true the first
+// time key is passed to FirstSeen on this logger, or any logger derived from
+// it, and false afterwards.
+func (s *standardLogger) FirstSeen(key, msg string, fields ...Field) {
+	_, loaded := s.seen.LoadOrStore(key, struct{}{})
+	s.log.Info(msg, append(fields[:len(fields):len(fields)], zap.Bool("first_seen", !loaded))...)
+}
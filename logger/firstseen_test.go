@@ -0,0 +1,39 @@
+package logger
+
+import "testing"
+
+func TestFirstSeen(t *testing.T) {
+	svc, logs := NewTestLogger()
+	log := svc.(*standardLogger)
+	child := log.With(String("component", "cache"))
+
+	log.FirstSeen("user:1", "user logged in", String("user", "1"))
+	log.FirstSeen("user:1", "user logged in", String("user", "1"))
+	child.FirstSeen("user:1", "user logged in")
+	child.FirstSeen("user:2", "user logged in")
+
+	entries := logs.AllUntimed()
+	want := []bool{true, false, false, true}
+	if len(entries) != len(want) {
+		t.Fatalf("logged %d entries, want %d", len(entries), len(want))
+	}
+	for i, e := range entries {
+		if e.Level != INFO || e.ContextMap()["first_seen"] != want[i] {
+			t.Errorf("entry %d = %s %v, want INFO first_seen=%v", i, LevelName(e.Level), e.ContextMap(), want[i])
+		}
+	}
+	if entries[0].ContextMap()["user"] != "1" {
+		t.Errorf("fields = %v, want the fields passed", entries[0].ContextMap())
+	}
+}
+
+func TestFirstSeenLeavesTheFieldsPassedAlone(t *testing.T) {
+	svc, _ := NewTestLogger()
+	fields := make([]Field, 1, 2)
+	fields[0] = String("user", "1")
+	svc.(*standardLogger).FirstSeen("user:1", "user logged in", fields...)
+
+	if spare := fields[:2][1]; spare.Key != "" {
+		t.Errorf("FirstSeen wrote %s past the fields passed", spare.Key)
+	}
+}
@@ -0,0 +1,37 @@
+package logger
+
+import "go.uber.org/zap/zapcore"
+
+// gcpSeverities maps levels to the severities of Google Cloud Logging.
+var gcpSeverities = map[zapcore.Level]string{
+	TRACE:  "DEBUG",
+	DEBUG:  "DEBUG",
+	INFO:   "INFO",
+	WARN:   "WARNING",
+	ERROR:  "ERROR",
+	DPANIC: "CRITICAL",
+	PANIC:  "CRITICAL",
+	FATAL:  "CRITICAL",
+	AUDIT:  "NOTICE",
+}
+
+// GCPSeverityEncoder encodes a level as a Google Cloud Logging severity.
+func GCPSeverityEncoder(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	if s, ok := gcpSeverities[l]; ok {
+		enc.AppendString(s)
+		return
+	}
+	enc.AppendString("DEFAULT")
+}
+
+// gcpEncoderConfig adapts cfg to the JSON Google Cloud Logging parses from
+// container output: the level becomes severity and the time an RFC 3339
+// timestamp.
+func gcpEncoderConfig(cfg zapcore.EncoderConfig) zapcore.EncoderConfig {
+	cfg.LevelKey = "severity"
+	cfg.EncodeLevel = GCPSeverityEncoder
+	cfg.MessageKey = "message"
+	cfg.TimeKey = "timestamp"
+	cfg.EncodeTime = zapcore.RFC3339NanoTimeEncoder
+	return cfg
+}
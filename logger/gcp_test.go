@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestGCPEncoding(t *testing.T) {
+	svc, path := newFileService(t, config.Logger{Encoding: EncodingGCP, LoggingLevel: "TRACE"})
+	svc.Tracez("trace")
+	svc.Infoz("info")
+	svc.Warnz("warn")
+	svc.Errorz("error")
+	svc.Audit("audit")
+	svc.Sync()
+
+	entries := readEntries(t, path)
+	want := []string{"DEBUG", "INFO", "WARNING", "ERROR", "NOTICE"}
+	if len(entries) != len(want) {
+		t.Fatalf("wrote %d entries, want %d", len(entries), len(want))
+	}
+	for i, e := range entries {
+		if e["severity"] != want[i] {
+			t.Errorf("%v has severity %v, want %s", e["message"], e["severity"], want[i])
+		}
+		if _, ok := e["level"]; ok {
+			t.Errorf("%v has a level key", e["message"])
+		}
+		ts, _ := e["timestamp"].(string)
+		if _, err := time.Parse(time.RFC3339Nano, ts); err != nil {
+			t.Errorf("timestamp %q is not RFC 3339: %v", ts, err)
+		}
+	}
+}
+
+func TestGCPSeverityEncoderDefault(t *testing.T) {
+	enc := zapcore.NewMapObjectEncoder()
+	enc.AddArray("severity", zapcore.ArrayMarshalerFunc(func(ae zapcore.ArrayEncoder) error {
+		GCPSeverityEncoder(AUDIT+1, ae)
+		return nil
+	}))
+	if got, _ := enc.Fields["severity"].([]interface{}); len(got) != 1 || got[0] != "DEFAULT" {
+		t.Errorf("encoded %v, want DEFAULT for an unknown level", enc.Fields["severity"])
+	}
+}
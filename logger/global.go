@@ -0,0 +1,255 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+)
+
+// global holds the logger set with SetLogger.
+type global struct {
+	logger *standardLogger
+	// skipped is logger skipping one more frame, the one of the
+	// package-level function it is called from
+	skipped *standardLogger
+}
+
+var loggerPointer atomic.Pointer[global]
+
+// SetLogger sets the logger returned by GetLogger and used by the
+// package-level logging functions. It is safe to call concurrently with them.
+func SetLogger(l *standardLogger) {
+	if l == nil {
+		loggerPointer.Store(nil)
+		return
+	}
+	loggerPointer.Store(&global{
+		logger:  l,
+		skipped: l.withLogger(l.log.WithOptions(zap.AddCallerSkip(1))),
+	})
+}
+
+// GetLogger returns the logger set with SetLogger, nil when there is none.
+func GetLogger() *standardLogger {
+	if g := loggerPointer.Load(); g != nil {
+		return g.logger
+	}
+	return nil
+}
+
+// globalLogger returns the logger the package-level functions log through.
+func globalLogger() *standardLogger {
+	if g := loggerPointer.Load(); g != nil {
+		return g.skipped
+	}
+	return nil
+}
+
+// SetLevel changes the level of the logger set with SetLogger, see
+// standardLogger.SetLevel. It does nothing when there is none.
+func SetLevel(level string) {
+	if l := globalLogger(); l != nil {
+		l.SetLevel(level)
+	}
+}
+
+// The functions below log through the logger set with SetLogger and do
+// nothing when there is none, except for the Fatal and Panic ones which still
+// exit and panic.
+
+func Errorf(format string, args ...interface{}) {
+	if l := globalLogger(); l != nil {
+		l.Errorf(format, args...)
+	}
+}
+
+func Error(args ...interface{}) {
+	if l := globalLogger(); l != nil {
+		l.Error(args...)
+	}
+}
+
+func Errorz(msg string, fields ...Field) {
+	if l := globalLogger(); l != nil {
+		l.Errorz(msg, fields...)
+	}
+}
+
+func Fatalf(format string, args ...interface{}) {
+	if l := globalLogger(); l != nil {
+		l.Fatalf(format, args...)
+		return
+	}
+	os.Exit(1)
+}
+
+func Fatal(args ...interface{}) {
+	if l := globalLogger(); l != nil {
+		l.Fatal(args...)
+		return
+	}
+	os.Exit(1)
+}
+
+func Fatalz(msg string, fields ...Field) {
+	if l := globalLogger(); l != nil {
+		l.Fatalz(msg, fields...)
+		return
+	}
+	os.Exit(1)
+}
+
+func Panicf(format string, args ...interface{}) {
+	if l := globalLogger(); l != nil {
+		l.Panicf(format, args...)
+	}
+	panic(fmt.Sprintf(format, args...))
+}
+
+func Panic(args ...interface{}) {
+	if l := globalLogger(); l != nil {
+		l.Panic(args...)
+	}
+	panic(fmt.Sprint(args...))
+}
+
+func Panicz(msg string, fields ...Field) {
+	if l := globalLogger(); l != nil {
+		l.Panicz(msg, fields...)
+	}
+	panic(msg)
+}
+
+func DPanicf(format string, args ...interface{}) {
+	if l := globalLogger(); l != nil {
+		l.DPanicf(format, args...)
+	}
+}
+
+func DPanic(args ...interface{}) {
+	if l := globalLogger(); l != nil {
+		l.DPanic(args...)
+	}
+}
+
+func DPanicz(msg string, fields ...Field) {
+	if l := globalLogger(); l != nil {
+		l.DPanicz(msg, fields...)
+	}
+}
+
+func Infof(format string, args ...interface{}) {
+	if l := globalLogger(); l != nil {
+		l.Infof(format, args...)
+	}
+}
+
+func Info(args ...interface{}) {
+	if l := globalLogger(); l != nil {
+		l.Info(args...)
+	}
+}
+
+func Infoz(msg string, fields ...Field) {
+	if l := globalLogger(); l != nil {
+		l.Infoz(msg, fields...)
+	}
+}
+
+func Warnf(format string, args ...interface{}) {
+	if l := globalLogger(); l != nil {
+		l.Warnf(format, args...)
+	}
+}
+
+func Warn(args ...interface{}) {
+	if l := globalLogger(); l != nil {
+		l.Warn(args...)
+	}
+}
+
+func Warnz(msg string, fields ...Field) {
+	if l := globalLogger(); l != nil {
+		l.Warnz(msg, fields...)
+	}
+}
+
+func Debugf(format string, args ...interface{}) {
+	if l := globalLogger(); l != nil {
+		l.Debugf(format, args...)
+	}
+}
+
+func Debug(args ...interface{}) {
+	if l := globalLogger(); l != nil {
+		l.Debug(args...)
+	}
+}
+
+func Debugz(msg string, fields ...Field) {
+	if l := globalLogger(); l != nil {
+		l.Debugz(msg, fields...)
+	}
+}
+
+func Tracef(format string, args ...interface{}) {
+	if l := globalLogger(); l != nil {
+		l.Tracef(format, args...)
+	}
+}
+
+func Trace(args ...interface{}) {
+	if l := globalLogger(); l != nil {
+		l.Trace(args...)
+	}
+}
+
+func Tracez(msg string, fields ...Field) {
+	if l := globalLogger(); l != nil {
+		l.Tracez(msg, fields...)
+	}
+}
+
+func Audit(msg string, fields ...Field) {
+	if l := globalLogger(); l != nil {
+		l.Audit(msg, fields...)
+	}
+}
+
+func TracezIf(cond bool, msg string, fields ...Field) {
+	if l := globalLogger(); l != nil {
+		l.TracezIf(cond, msg, fields...)
+	}
+}
+
+func DebugzIf(cond bool, msg string, fields ...Field) {
+	if l := globalLogger(); l != nil {
+		l.DebugzIf(cond, msg, fields...)
+	}
+}
+
+func InfozIf(cond bool, msg string, fields ...Field) {
+	if l := globalLogger(); l != nil {
+		l.InfozIf(cond, msg, fields...)
+	}
+}
+
+func WarnzIf(cond bool, msg string, fields ...Field) {
+	if l := globalLogger(); l != nil {
+		l.WarnzIf(cond, msg, fields...)
+	}
+}
+
+func ErrorzIf(cond bool, msg string, fields ...Field) {
+	if l := globalLogger(); l != nil {
+		l.ErrorzIf(cond, msg, fields...)
+	}
+}
+
+func ErrorOn(err error, msg string, fields ...Field) {
+	if l := globalLogger(); l != nil {
+		l.ErrorOn(err, msg, fields...)
+	}
+}
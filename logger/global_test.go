@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+// useGlobal sets l as the global logger until the test ends.
+func useGlobal(t *testing.T, l *standardLogger) {
+	t.Helper()
+	prev := GetLogger()
+	SetLogger(l)
+	t.Cleanup(func() { SetLogger(prev) })
+}
+
+func TestPackageLevelFunctions(t *testing.T) {
+	svc, logs := NewTestLogger()
+	useGlobal(t, svc.(*standardLogger))
+	SetLevel("TRACE")
+
+	Tracez("tracez")
+	Debugf("debug%s", "f")
+	Info("info")
+	Warnz("warnz")
+	Errorf("error%s", "f")
+	Audit("audit")
+	InfozIf(false, "skipped")
+	ErrorOn(nil, "no error")
+
+	want := []string{"TRACE tracez", "DEBUG debugf", "INFO info", "WARN warnz", "ERROR errorf", "AUDIT audit"}
+	entries := logs.AllUntimed()
+	if len(entries) != len(want) {
+		t.Fatalf("logged %d entries, want %d", len(entries), len(want))
+	}
+	for i, e := range entries {
+		if got := LevelName(e.Level) + " " + e.Message; got != want[i] {
+			t.Errorf("entry %d = %s, want %s", i, got, want[i])
+		}
+		if !strings.HasSuffix(e.Caller.File, "global_test.go") {
+			t.Errorf("entry %d caller = %s, want this file", i, e.Caller.File)
+		}
+	}
+}
+
+func TestSetLevel(t *testing.T) {
+	svc, logs := NewTestLogger()
+	useGlobal(t, svc.(*standardLogger))
+	SetLevel("WARN")
+	Infoz("dropped")
+	Warnz("kept")
+
+	if entries := logs.AllUntimed(); len(entries) != 1 || entries[0].Message != "kept" {
+		t.Errorf("entries = %v, want only the warning", entries)
+	}
+}
+
+func TestPackageLevelFunctionsWithoutALogger(t *testing.T) {
+	useGlobal(t, nil)
+	SetLevel("DEBUG")
+	Infoz("nowhere")
+	Errorf("nowhere")
+	if GetLogger() != nil {
+		t.Error("GetLogger() != nil after SetLogger(nil)")
+	}
+}
+
+func TestSetLoggerConcurrently(t *testing.T) {
+	a, _ := NewTestLogger()
+	b, _ := NewTestLogger()
+	useGlobal(t, a.(*standardLogger))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				SetLogger(b.(*standardLogger))
+				SetLogger(a.(*standardLogger))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				Infoz("racing")
+				if l := GetLogger(); l != a && l != b {
+					t.Errorf("GetLogger() = %v, want one of the loggers set", l)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
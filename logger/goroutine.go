@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithGoroutineID returns an option, for NewService or zap loggers, that adds
+// a goroutine field holding the id of the goroutine logging each entry, to
+// tell concurrent work apart when chasing deadlocks and races. Go has no
+// cheap way to get that id, it is parsed from the first line of
+// runtime.Stack, which costs a few microseconds and an allocation per entry:
+// leave it to debugging.
+func WithGoroutineID() zap.Option {
+	return zap.WrapCore(newGoroutineCore)
+}
+
+func newGoroutineCore(core zapcore.Core) zapcore.Core {
+	return &goroutineCore{core}
+}
+
+type goroutineCore struct {
+	zapcore.Core
+}
+
+func (c *goroutineCore) With(fields []zapcore.Field) zapcore.Core {
+	return &goroutineCore{c.Core.With(fields)}
+}
+
+func (c *goroutineCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write runs on the goroutine that logged the entry, zap writing entries
+// synchronously.
+func (c *goroutineCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if id, ok := goroutineID(); ok {
+		fields = append(fields[:len(fields):len(fields)], zap.Uint64("goroutine", id))
+	}
+	return c.Core.Write(ent, fields)
+}
+
+// goroutineID parses the id of the calling goroutine out of the first line of
+// its stack, "goroutine 42 [running]:".
+func goroutineID() (uint64, bool) {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+	id, err := strconv.ParseUint(string(b), 10, 64)
+	return id, err == nil
+}
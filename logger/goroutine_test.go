@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/dazzling420/go-logger/config"
+)
+
+func TestIncludeGoroutineID(t *testing.T) {
+	s, path := newFileService(t, config.Logger{IncludeGoroutineID: true})
+	s.Infoz("from the test")
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.Infoz("from another goroutine")
+	}()
+	wg.Wait()
+	s.Sync()
+
+	entries := readEntries(t, path)
+	if len(entries) != 2 {
+		t.Fatalf("logged %d entries, want 2", len(entries))
+	}
+	first, ok1 := entries[0]["goroutine"].(float64)
+	second, ok2 := entries[1]["goroutine"].(float64)
+	if !ok1 || !ok2 || first == 0 || second == 0 {
+		t.Fatalf("goroutine = %v and %v, want ids", entries[0]["goroutine"], entries[1]["goroutine"])
+	}
+	if first == second {
+		t.Errorf("both entries have goroutine %v", first)
+	}
+	if id, _ := goroutineID(); float64(id) != first {
+		t.Errorf("goroutine = %v, want the id of the test, %d", first, id)
+	}
+}
+
+func TestGoroutineIDLeftOutByDefault(t *testing.T) {
+	s, path := newFileService(t, config.Logger{})
+	s.Infoz("hi")
+	s.Sync()
+	if entries := readEntries(t, path); entries[0]["goroutine"] != nil {
+		t.Errorf("goroutine = %v without IncludeGoroutineID", entries[0]["goroutine"])
+	}
+}
+
+func TestWithGoroutineID(t *testing.T) {
+	svc, logs := NewTestLogger(WithGoroutineID())
+	svc.Infoz("hi")
+	id, _ := goroutineID()
+	if got := logs.All()[0].ContextMap()["goroutine"]; got != id {
+		t.Errorf("goroutine = %v, want %d", got, id)
+	}
+}
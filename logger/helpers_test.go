@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap"
+)
+
+// readEntries decodes the JSON entries of the log file at path, one per line.
+// A missing file has no entries.
+func readEntries(t *testing.T, path string) []map[string]interface{} {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []map[string]interface{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var entry map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("%s: %v: %s", path, err, scanner.Bytes())
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// messages returns the messages of entries, in order.
+func messages(entries []map[string]interface{}) []string {
+	var msgs []string
+	for _, e := range entries {
+		msg, _ := e["message"].(string)
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
+
+// tempLog returns the path of a log file named name in a directory removed
+// once the test ends.
+func tempLog(t *testing.T, name string) string {
+	t.Helper()
+	return filepath.Join(t.TempDir(), name)
+}
+
+// newFileService returns a logger configured with conf writing to a log file
+// only, with opts applied, and the path of that file. The logger is closed
+// once the test ends.
+func newFileService(t *testing.T, conf config.Logger, opts ...zap.Option) (*standardLogger, string) {
+	t.Helper()
+	path := tempLog(t, "app.log")
+	conf.LogFileName = path
+	conf.DisableStdout = true
+	svc, err := BuildService(conf, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { svc.Close() })
+	return svc, path
+}
+
+// captureStdout returns what f, and the loggers it builds, write to stdout.
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+	return capture(t, &os.Stdout, f)
+}
+
+// captureStderr returns what f, and the loggers it builds, write to stderr.
+func captureStderr(t *testing.T, f func()) string {
+	t.Helper()
+	return capture(t, &os.Stderr, f)
+}
+
+// capture returns what f writes to the standard stream *file.
+func capture(t *testing.T, file **os.File, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := *file
+	*file = w
+	defer func() { *file = saved }()
+
+	out := make(chan []byte)
+	go func() {
+		b, _ := io.ReadAll(r)
+		out <- b
+	}()
+	f()
+	w.Close()
+	return string(<-out)
+}
@@ -0,0 +1,107 @@
+package logger
+
+import (
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// HTTPMiddlewareOption configures HTTPMiddleware.
+type HTTPMiddlewareOption func(*httpMiddleware)
+
+// WithStatusLevel sets the function choosing the level a request is logged at
+// from its response status code.
+func WithStatusLevel(f func(status int) zapcore.Level) HTTPMiddlewareOption {
+	return func(m *httpMiddleware) {
+		m.level = f
+	}
+}
+
+// DefaultStatusLevel logs 5xx responses at ERROR, 4xx at WARN and everything
+// else at INFO.
+func DefaultStatusLevel(status int) zapcore.Level {
+	switch {
+	case status >= 500:
+		return ERROR
+	case status >= 400:
+		return WARN
+	default:
+		return INFO
+	}
+}
+
+type httpMiddleware struct {
+	level func(status int) zapcore.Level
+}
+
+// HTTPMiddleware returns middleware logging one line per request with its
+// method, path, request id, status, bytes written, duration and remote
+// address. The request id is that of the RequestIDHeader of the request, or a
+// generated one, and is echoed in the same header of the response. Handlers
+// can get a child of svc carrying the method, path and request id with
+// FromContext, and the id with RequestIDFromContext.
+func HTTPMiddleware(svc Service, opts ...HTTPMiddlewareOption) func(http.Handler) http.Handler {
+	m := &httpMiddleware{level: DefaultStatusLevel}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ctx := NewContext(r.Context(), svc.GetLogger().withLogger(svc.GetLogger().log.With(
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+			)))
+			ctx = WithIncomingRequestID(ctx, r.Header.Get(RequestIDHeader))
+			child := FromContext(ctx)
+			w.Header().Set(RequestIDHeader, RequestIDFromContext(ctx))
+			rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+
+			next.ServeHTTP(rw, r.WithContext(ctx))
+
+			child.GetZapLogger().Log(m.level(rw.status), "http request",
+				zap.Int("status", rw.status),
+				zap.Int64("bytes", rw.bytes),
+				zap.Duration("duration", time.Since(start)),
+				zap.String("remote_addr", r.RemoteAddr),
+			)
+		})
+	}
+}
+
+// responseWriter records the status code and body size of a response.
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += int64(n)
+	return n, err
+}
+
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying ResponseWriter.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
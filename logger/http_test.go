@@ -0,0 +1,116 @@
+package logger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	svc, logs := NewTestLogger()
+	handler := HTTPMiddleware(svc)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		FromContext(r.Context()).Infoz("handling")
+		switch r.URL.Path {
+		case "/missing":
+			http.NotFound(w, r)
+		case "/failing":
+			w.WriteHeader(http.StatusInternalServerError)
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.Write([]byte("hello"))
+		}
+	}))
+
+	for _, tt := range []struct {
+		path   string
+		status int
+		level  zapcore.Level
+	}{
+		{"/ok", http.StatusOK, INFO},
+		{"/missing", http.StatusNotFound, WARN},
+		{"/failing", http.StatusInternalServerError, ERROR},
+	} {
+		logs.TakeAll()
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		entries := logs.AllUntimed()
+		if len(entries) != 2 {
+			t.Fatalf("%s logged %d entries, want 2", tt.path, len(entries))
+		}
+		if f := entries[0].ContextMap(); f["method"] != "GET" || f["path"] != tt.path {
+			t.Errorf("%s: the logger of the handler has fields %v, want the method and path", tt.path, f)
+		}
+		e := entries[1]
+		f := e.ContextMap()
+		if e.Message != "http request" || e.Level != tt.level || f["status"] != int64(tt.status) {
+			t.Errorf("%s logged %s %q status %v, want %s status %d", tt.path, LevelName(e.Level), e.Message, f["status"], LevelName(tt.level), tt.status)
+		}
+		if f["method"] != "GET" || f["path"] != tt.path || f["remote_addr"] != req.RemoteAddr {
+			t.Errorf("%s: fields = %v", tt.path, f)
+		}
+		if _, ok := f["duration"]; !ok {
+			t.Errorf("%s: no duration in %v", tt.path, f)
+		}
+		if tt.path == "/ok" && f["bytes"] != int64(len("hello")) {
+			t.Errorf("bytes = %v, want 5", f["bytes"])
+		}
+	}
+}
+
+func TestHTTPMiddlewareWithStatusLevel(t *testing.T) {
+	svc, logs := NewTestLogger()
+	handler := HTTPMiddleware(svc, WithStatusLevel(func(int) zapcore.Level { return DEBUG }))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil))
+
+	if entries := logs.AllUntimed(); len(entries) != 1 || entries[0].Level != DEBUG {
+		t.Errorf("entries = %v, want one at DEBUG", entries)
+	}
+}
+
+func TestHTTPMiddlewareKeepsTheFlusher(t *testing.T) {
+	svc, _ := NewTestLogger()
+	rec := httptest.NewRecorder()
+	HTTPMiddleware(svc)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := http.NewResponseController(w).Flush(); err != nil {
+			t.Errorf("Flush() = %v", err)
+		}
+	})).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if !rec.Flushed {
+		t.Error("the response was not flushed")
+	}
+}
+
+func TestHTTPMiddlewareRequestID(t *testing.T) {
+	svc, logs := NewTestLogger()
+	var seen []string
+	handler := HTTPMiddleware(svc)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, RequestIDFromContext(r.Context()))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, "upstream-1")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if seen[0] != "upstream-1" || rec.Header().Get(RequestIDHeader) != "upstream-1" {
+		t.Errorf("id = %q, echoed %q, want the upstream one", seen[0], rec.Header().Get(RequestIDHeader))
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if seen[1] == "" || rec.Header().Get(RequestIDHeader) != seen[1] {
+		t.Errorf("id = %q, echoed %q, want a generated one", seen[1], rec.Header().Get(RequestIDHeader))
+	}
+
+	for i, e := range logs.FilterMessage("http request").All() {
+		if e.ContextMap()["request_id"] != seen[i] {
+			t.Errorf("request %d logged request_id %v, want %s", i, e.ContextMap()["request_id"], seen[i])
+		}
+	}
+}
@@ -0,0 +1,154 @@
+//go:build linux
+
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// journalSocket is where journald listens for its native protocol.
+const journalSocket = "/run/systemd/journal/socket"
+
+// journalPriorities maps levels to the syslog priorities journald uses.
+var journalPriorities = map[zapcore.Level]int{
+	TRACE:  7,
+	DEBUG:  7,
+	INFO:   6,
+	AUDIT:  5,
+	WARN:   4,
+	ERROR:  3,
+	DPANIC: 2,
+	PANIC:  1,
+	FATAL:  0,
+}
+
+// journaldCore sends entries to journald over its native protocol: the
+// message as MESSAGE, the level as PRIORITY, the caller as CODE_FILE,
+// CODE_LINE and CODE_FUNC and every field as a journal field named after its
+// key in uppercase, e.g. user_id as USER_ID. Entries too large for a single
+// datagram are lost.
+type journaldCore struct {
+	zapcore.LevelEnabler
+	conn       *net.UnixConn
+	socket     *net.UnixAddr
+	identifier string
+	context    []zapcore.Field
+}
+
+// newJournaldCore connects to the journald socket at path, journalSocket
+// when empty, tagging entries with identifier, the name of the executable
+// when empty. It fails when journald isn't running. The returned function
+// closes the connection.
+func newJournaldCore(path, identifier string, enab zapcore.LevelEnabler) (zapcore.Core, func() error, error) {
+	if path == "" {
+		path = journalSocket
+	}
+	if identifier == "" {
+		identifier = filepath.Base(os.Args[0])
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, nil, err
+	}
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Net: "unixgram"})
+	if err != nil {
+		return nil, nil, err
+	}
+	socket := &net.UnixAddr{Name: path, Net: "unixgram"}
+	return &journaldCore{LevelEnabler: enab, conn: conn, socket: socket, identifier: identifier}, conn.Close, nil
+}
+
+func (c *journaldCore) With(fields []zapcore.Field) zapcore.Core {
+	context := make([]zapcore.Field, 0, len(c.context)+len(fields))
+	context = append(append(context, c.context...), fields...)
+	clone := *c
+	clone.context = context
+	return &clone
+}
+
+func (c *journaldCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *journaldCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	var b bytes.Buffer
+	appendJournalField(&b, "MESSAGE", ent.Message)
+	priority, ok := journalPriorities[ent.Level]
+	if !ok {
+		priority = journalPriorities[INFO]
+	}
+	appendJournalField(&b, "PRIORITY", strconv.Itoa(priority))
+	appendJournalField(&b, "SYSLOG_IDENTIFIER", c.identifier)
+	if ent.LoggerName != "" {
+		appendJournalField(&b, "LOGGER", ent.LoggerName)
+	}
+	if ent.Caller.Defined {
+		appendJournalField(&b, "CODE_FILE", ent.Caller.File)
+		appendJournalField(&b, "CODE_LINE", strconv.Itoa(ent.Caller.Line))
+		if ent.Caller.Function != "" {
+			appendJournalField(&b, "CODE_FUNC", ent.Caller.Function)
+		}
+	}
+	if ent.Stack != "" {
+		appendJournalField(&b, "STACKTRACE", ent.Stack)
+	}
+
+	values := zapcore.NewMapObjectEncoder()
+	for _, f := range c.context {
+		f.AddTo(values)
+	}
+	for _, f := range fields {
+		f.AddTo(values)
+	}
+	for key, v := range values.Fields {
+		if name := journalFieldName(key); name != "" {
+			appendJournalField(&b, name, cellString(v))
+		}
+	}
+
+	_, _, err := c.conn.WriteMsgUnix(b.Bytes(), nil, c.socket)
+	return err
+}
+
+func (c *journaldCore) Sync() error {
+	return nil
+}
+
+// appendJournalField appends a field in the native protocol format, values
+// holding a newline being length prefixed.
+func appendJournalField(b *bytes.Buffer, name, value string) {
+	b.WriteString(name)
+	if !strings.Contains(value, "\n") {
+		b.WriteByte('=')
+		b.WriteString(value)
+		b.WriteByte('\n')
+		return
+	}
+	b.WriteByte('\n')
+	binary.Write(b, binary.LittleEndian, uint64(len(value)))
+	b.WriteString(value)
+	b.WriteByte('\n')
+}
+
+// journalFieldName turns key into a valid journal field name, uppercase
+// letters, digits and underscores not starting with a digit or an underscore,
+// which journald reserves. It returns "" when nothing is left.
+func journalFieldName(key string) string {
+	name := []byte(strings.ToUpper(key))
+	for i, ch := range name {
+		if (ch < 'A' || ch > 'Z') && (ch < '0' || ch > '9') {
+			name[i] = '_'
+		}
+	}
+	return strings.TrimLeft(string(name), "_0123456789")
+}
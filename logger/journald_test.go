@@ -0,0 +1,138 @@
+//go:build linux
+
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// fakeJournal listens on a unix datagram socket in a temporary directory,
+// returning its path and the socket.
+func fakeJournal(t *testing.T) (string, *net.UnixConn) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "journal.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return path, conn
+}
+
+// readJournalEntry reads a datagram of the native protocol from conn.
+func readJournalEntry(t *testing.T, conn *net.UnixConn) map[string]string {
+	t.Helper()
+	buf := make([]byte, 64*1024)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := map[string]string{}
+	data := buf[:n]
+	for len(data) > 0 {
+		line := data
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			line = data[:i]
+		}
+		if i := bytes.IndexByte(line, '='); i >= 0 {
+			fields[string(line[:i])] = string(line[i+1:])
+			data = data[len(line)+1:]
+			continue
+		}
+		// a length prefixed value
+		data = data[len(line)+1:]
+		size := binary.LittleEndian.Uint64(data[:8])
+		fields[string(line)] = string(data[8 : 8+size])
+		data = data[8+size+1:]
+	}
+	return fields
+}
+
+func TestJournaldCore(t *testing.T) {
+	path, journal := fakeJournal(t)
+	core, stop, err := newJournaldCore(path, "myapp", allLevels)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+	log := zap.New(core, zap.AddCaller()).With(zap.String("user_id", "bob"))
+
+	log.Warn("two\nlines", zap.Int("attempt", 3), zap.String("http.method", "GET"), zap.String("_private", "x"))
+	got := readJournalEntry(t, journal)
+	for name, want := range map[string]string{
+		"MESSAGE":           "two\nlines",
+		"PRIORITY":          "4",
+		"SYSLOG_IDENTIFIER": "myapp",
+		"USER_ID":           "bob",
+		"ATTEMPT":           "3",
+		"HTTP_METHOD":       "GET",
+		"PRIVATE":           "x",
+	} {
+		if got[name] != want {
+			t.Errorf("%s = %q, want %q", name, got[name], want)
+		}
+	}
+	if !strings.HasSuffix(got["CODE_FILE"], "journald_test.go") || got["CODE_LINE"] == "" || !strings.HasSuffix(got["CODE_FUNC"], "TestJournaldCore") {
+		t.Errorf("caller = %s:%s %s, want this test", got["CODE_FILE"], got["CODE_LINE"], got["CODE_FUNC"])
+	}
+}
+
+func TestJournaldPriorities(t *testing.T) {
+	path, journal := fakeJournal(t)
+	core, stop, err := newJournaldCore(path, "myapp", allLevels)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+	log := zap.New(core)
+
+	for _, tt := range []struct {
+		level    zapcore.Level
+		priority string
+	}{{TRACE, "7"}, {DEBUG, "7"}, {INFO, "6"}, {AUDIT, "5"}, {WARN, "4"}, {ERROR, "3"}} {
+		log.Check(tt.level, "entry").Write()
+		if got := readJournalEntry(t, journal)["PRIORITY"]; got != tt.priority {
+			t.Errorf("PRIORITY of %s = %s, want %s", LevelName(tt.level), got, tt.priority)
+		}
+	}
+}
+
+func TestJournaldFallsBackWithoutJournald(t *testing.T) {
+	if _, err := os.Stat(journalSocket); err == nil {
+		t.Skip("journald is running")
+	}
+	s, path := newFileService(t, config.Logger{Journald: true})
+	s.Infoz("still logged")
+	s.Sync()
+
+	got := messages(readEntries(t, path))
+	if len(got) != 2 || got[0] != "Was unable to connect to journald, running without it!" || got[1] != "still logged" {
+		t.Errorf("logged %q, want the journald error and the entry", got)
+	}
+}
+
+func TestJournalFieldName(t *testing.T) {
+	for key, want := range map[string]string{
+		"user_id":  "USER_ID",
+		"http.url": "HTTP_URL",
+		"_secret":  "SECRET",
+		"2fa":      "FA",
+		"__":       "",
+	} {
+		if got := journalFieldName(key); got != want {
+			t.Errorf("journalFieldName(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
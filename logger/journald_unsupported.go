@@ -0,0 +1,13 @@
+//go:build !linux
+
+package logger
+
+import (
+	"errors"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func newJournaldCore(path, identifier string, enab zapcore.LevelEnabler) (zapcore.Core, func() error, error) {
+	return nil, nil, errors.New("journald is not supported on this platform")
+}
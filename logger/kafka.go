@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// kafkaBufferSize is the number of entries a KafkaCore queues before it
+// starts dropping them.
+const kafkaBufferSize = 10000
+
+// KafkaMessage is a log entry ready to be produced to Kafka.
+type KafkaMessage struct {
+	Topic string
+	Key   []byte // nil unless the core was given a key field
+	Value []byte // the JSON encoded entry
+	Time  time.Time
+}
+
+// KafkaProducer sends messages to Kafka. It is the only part of a Kafka client
+// a KafkaCore depends on, the logkafka module provides one backed by
+// github.com/segmentio/kafka-go.
+type KafkaProducer interface {
+	Produce(ctx context.Context, msgs []KafkaMessage) error
+	Close() error
+}
+
+// KafkaCore produces entries to a Kafka topic without ever blocking the
+// logging goroutine. Use Close to produce whatever is still buffered and close
+// the producer.
+type KafkaCore struct {
+	*batchCore
+	producer KafkaProducer
+}
+
+// NewKafkaProducerCore returns a core producing entries at or above minLevel
+// to topic through p, one JSON message per entry keyed by the value of the
+// keyField field when keyField is set and the entry carries it. Entries are
+// buffered and produced in the background, once the buffer is full further
+// entries are dropped and counted, see Dropped.
+func NewKafkaProducerCore(p KafkaProducer, topic string, minLevel zapcore.Level, keyField string) *KafkaCore {
+	flush := func(batch []batchEntry) error {
+		msgs := make([]KafkaMessage, len(batch))
+		for i, e := range batch {
+			msgs[i] = KafkaMessage{
+				Topic: topic,
+				Key:   kafkaKey(keyField, e.Fields),
+				Value: e.Line,
+				Time:  e.Entry.Time,
+			}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return p.Produce(ctx, msgs)
+	}
+
+	b := newBatcher(defaultBatchSize, defaultBatchInterval, flush)
+	b.limit = kafkaBufferSize
+	return &KafkaCore{
+		batchCore: newBatchCore(zapcore.NewJSONEncoder(newEncoderConfig()), minLevel, b),
+		producer:  p,
+	}
+}
+
+// Dropped returns the number of entries dropped because the buffer was full.
+func (c *KafkaCore) Dropped() uint64 {
+	return c.b.dropped.Load()
+}
+
+// Close produces the buffered entries and closes the producer.
+func (c *KafkaCore) Close() error {
+	err := c.batchCore.Close()
+	if cerr := c.producer.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func kafkaKey(keyField string, fields []zapcore.Field) []byte {
+	if keyField == "" {
+		return nil
+	}
+	// the last field wins, like it would in the encoded entry
+	for i := len(fields) - 1; i >= 0; i-- {
+		f := fields[i]
+		if f.Key != keyField {
+			continue
+		}
+		switch f.Type {
+		case zapcore.StringType:
+			return []byte(f.String)
+		case zapcore.Int64Type, zapcore.Int32Type, zapcore.Int16Type, zapcore.Int8Type,
+			zapcore.Uint64Type, zapcore.Uint32Type, zapcore.Uint16Type, zapcore.Uint8Type:
+			return []byte(fmt.Sprint(f.Integer))
+		default:
+			if f.Interface != nil {
+				return []byte(fmt.Sprint(f.Interface))
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// fakeProducer records the messages produced, blocking until release is
+// closed when it is set.
+type fakeProducer struct {
+	release  chan struct{}
+	produced chan struct{}
+
+	mu     sync.Mutex
+	msgs   []KafkaMessage
+	closed bool
+}
+
+func (p *fakeProducer) Produce(ctx context.Context, msgs []KafkaMessage) error {
+	if p.produced != nil {
+		select {
+		case p.produced <- struct{}{}:
+		default:
+		}
+	}
+	if p.release != nil {
+		<-p.release
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.msgs = append(p.msgs, msgs...)
+	return nil
+}
+
+func (p *fakeProducer) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	return nil
+}
+
+func TestKafkaCoreProducesEntries(t *testing.T) {
+	p := &fakeProducer{}
+	core := NewKafkaProducerCore(p, "logs", INFO, "user_id")
+	log := zap.New(core)
+	log.Info("keyed", zap.Int("user_id", 42))
+	log.With(zap.String("user_id", "bob")).Warn("keyed by context")
+	log.Info("no key")
+	log.Debug("not produced")
+	if err := core.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !p.closed {
+		t.Error("Close did not close the producer")
+	}
+	if len(p.msgs) != 3 {
+		t.Fatalf("produced %d messages, want 3", len(p.msgs))
+	}
+	for i, want := range []struct {
+		key, message string
+	}{{"42", "keyed"}, {"bob", "keyed by context"}, {"", "no key"}} {
+		m := p.msgs[i]
+		var entry map[string]interface{}
+		if err := json.Unmarshal(m.Value, &entry); err != nil {
+			t.Fatal(err)
+		}
+		if m.Topic != "logs" || string(m.Key) != want.key || entry["message"] != want.message || m.Time.IsZero() {
+			t.Errorf("message %d = %s key %q %v, want key %q for %q", i, m.Topic, m.Key, entry, want.key, want.message)
+		}
+	}
+	if p.msgs[2].Key != nil {
+		t.Errorf("key = %q, want nil without the key field", p.msgs[2].Key)
+	}
+}
+
+func TestKafkaCoreDropsOnceTheBufferIsFull(t *testing.T) {
+	p := &fakeProducer{release: make(chan struct{}), produced: make(chan struct{}, 1)}
+	core := NewKafkaProducerCore(p, "logs", INFO, "")
+	log := zap.New(core)
+	for i := 0; i < defaultBatchSize; i++ {
+		log.Info("batch")
+	}
+	<-p.produced
+	for i := 0; i < kafkaBufferSize+3; i++ {
+		log.Info("queued")
+	}
+	if got := core.Dropped(); got != 3 {
+		t.Errorf("Dropped() = %d, want 3", got)
+	}
+	close(p.release)
+	core.Close()
+}
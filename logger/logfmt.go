@@ -0,0 +1,245 @@
+package logger
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+var logfmtPool = buffer.NewPool()
+
+// logfmtEncoder writes one line of space separated key=value pairs per entry:
+// time, level, logger, caller and message under their configured keys, then
+// the context and entry fields in the order they were added. Values holding
+// spaces, quotes, '=' or control characters are quoted, arrays, objects and
+// reflected values are written as quoted JSON and the keys of namespaced
+// fields are prefixed with the namespace, e.g. http.status=200.
+type logfmtEncoder struct {
+	cfg       zapcore.EncoderConfig
+	buf       *buffer.Buffer // context fields, already encoded
+	namespace string
+}
+
+func newLogfmtEncoder(cfg zapcore.EncoderConfig) zapcore.Encoder {
+	return &logfmtEncoder{cfg: cfg, buf: logfmtPool.Get()}
+}
+
+func (e *logfmtEncoder) Clone() zapcore.Encoder {
+	buf := logfmtPool.Get()
+	buf.Write(e.buf.Bytes())
+	return &logfmtEncoder{cfg: e.cfg, buf: buf, namespace: e.namespace}
+}
+
+func (e *logfmtEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	line := &logfmtEncoder{cfg: e.cfg, buf: logfmtPool.Get()}
+
+	if e.cfg.TimeKey != "" && e.cfg.EncodeTime != nil {
+		line.addKey(e.cfg.TimeKey)
+		line.appendValue(encodeCell(func(enc zapcore.PrimitiveArrayEncoder) { e.cfg.EncodeTime(ent.Time, enc) }))
+	}
+	if e.cfg.LevelKey != "" && e.cfg.EncodeLevel != nil {
+		line.addKey(e.cfg.LevelKey)
+		line.appendValue(encodeCell(func(enc zapcore.PrimitiveArrayEncoder) { e.cfg.EncodeLevel(ent.Level, enc) }))
+	}
+	if e.cfg.NameKey != "" && ent.LoggerName != "" {
+		line.AddString(e.cfg.NameKey, ent.LoggerName)
+	}
+	if e.cfg.CallerKey != "" && e.cfg.EncodeCaller != nil && ent.Caller.Defined {
+		line.addKey(e.cfg.CallerKey)
+		line.appendValue(encodeCell(func(enc zapcore.PrimitiveArrayEncoder) { e.cfg.EncodeCaller(ent.Caller, enc) }))
+	}
+	if e.cfg.FunctionKey != "" && ent.Caller.Defined && ent.Caller.Function != "" {
+		line.AddString(e.cfg.FunctionKey, ent.Caller.Function)
+	}
+	if e.cfg.MessageKey != "" {
+		line.AddString(e.cfg.MessageKey, ent.Message)
+	}
+
+	if e.buf.Len() > 0 {
+		if line.buf.Len() > 0 {
+			line.buf.AppendByte(' ')
+		}
+		line.buf.Write(e.buf.Bytes())
+	}
+	line.namespace = e.namespace
+	for _, f := range fields {
+		f.AddTo(line)
+	}
+	line.namespace = ""
+
+	if e.cfg.StacktraceKey != "" && ent.Stack != "" {
+		line.AddString(e.cfg.StacktraceKey, ent.Stack)
+	}
+	if e.cfg.LineEnding != "" {
+		line.buf.AppendString(e.cfg.LineEnding)
+	} else {
+		line.buf.AppendString(zapcore.DefaultLineEnding)
+	}
+	return line.buf, nil
+}
+
+func (e *logfmtEncoder) addKey(key string) {
+	if e.buf.Len() > 0 {
+		e.buf.AppendByte(' ')
+	}
+	e.buf.AppendString(e.namespace)
+	e.buf.AppendString(key)
+	e.buf.AppendByte('=')
+}
+
+// appendValue appends s, quoted when it would otherwise not read back as a
+// single value.
+func (e *logfmtEncoder) appendValue(s string) {
+	if logfmtNeedsQuoting(s) {
+		e.buf.AppendString(strconv.Quote(s))
+		return
+	}
+	e.buf.AppendString(s)
+}
+
+func logfmtNeedsQuoting(s string) bool {
+	if s == "" {
+		return true
+	}
+	for _, r := range s {
+		if r <= ' ' || r == '=' || r == '"' || r == 0x7f || r == utf8.RuneError {
+			return true
+		}
+	}
+	return false
+}
+
+// appendJSON appends v marshaled to JSON as a quoted value.
+func (e *logfmtEncoder) appendJSON(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	e.appendValue(string(b))
+	return nil
+}
+
+func (e *logfmtEncoder) AddArray(key string, arr zapcore.ArrayMarshaler) error {
+	values := zapcore.NewMapObjectEncoder()
+	if err := values.AddArray(key, arr); err != nil {
+		return err
+	}
+	e.addKey(key)
+	return e.appendJSON(values.Fields[key])
+}
+
+func (e *logfmtEncoder) AddObject(key string, obj zapcore.ObjectMarshaler) error {
+	values := zapcore.NewMapObjectEncoder()
+	if err := values.AddObject(key, obj); err != nil {
+		return err
+	}
+	e.addKey(key)
+	return e.appendJSON(values.Fields[key])
+}
+
+func (e *logfmtEncoder) AddReflected(key string, v interface{}) error {
+	e.addKey(key)
+	return e.appendJSON(v)
+}
+
+func (e *logfmtEncoder) OpenNamespace(key string) {
+	e.namespace += key + "."
+}
+
+func (e *logfmtEncoder) AddBinary(key string, v []byte) {
+	e.addKey(key)
+	e.appendValue(base64.StdEncoding.EncodeToString(v))
+}
+
+func (e *logfmtEncoder) AddByteString(key string, v []byte) {
+	e.addKey(key)
+	e.appendValue(string(v))
+}
+
+func (e *logfmtEncoder) AddString(key, v string) {
+	e.addKey(key)
+	e.appendValue(v)
+}
+
+func (e *logfmtEncoder) AddBool(key string, v bool) {
+	e.addKey(key)
+	e.buf.AppendBool(v)
+}
+
+func (e *logfmtEncoder) AddComplex128(key string, v complex128) {
+	e.addKey(key)
+	e.appendValue(strconv.FormatComplex(v, 'g', -1, 128))
+}
+
+func (e *logfmtEncoder) AddComplex64(key string, v complex64) {
+	e.addKey(key)
+	e.appendValue(strconv.FormatComplex(complex128(v), 'g', -1, 64))
+}
+
+func (e *logfmtEncoder) AddDuration(key string, v time.Duration) {
+	e.addKey(key)
+	if e.cfg.EncodeDuration == nil {
+		e.appendValue(v.String())
+		return
+	}
+	e.appendValue(encodeCell(func(enc zapcore.PrimitiveArrayEncoder) { e.cfg.EncodeDuration(v, enc) }))
+}
+
+func (e *logfmtEncoder) AddFloat64(key string, v float64) {
+	e.addKey(key)
+	e.appendFloat(v, 64)
+}
+
+func (e *logfmtEncoder) AddFloat32(key string, v float32) {
+	e.addKey(key)
+	e.appendFloat(float64(v), 32)
+}
+
+func (e *logfmtEncoder) appendFloat(v float64, bitSize int) {
+	switch {
+	case math.IsNaN(v):
+		e.buf.AppendString("NaN")
+	case math.IsInf(v, 1):
+		e.buf.AppendString("+Inf")
+	case math.IsInf(v, -1):
+		e.buf.AppendString("-Inf")
+	default:
+		e.buf.AppendFloat(v, bitSize)
+	}
+}
+
+func (e *logfmtEncoder) AddInt(key string, v int)     { e.AddInt64(key, int64(v)) }
+func (e *logfmtEncoder) AddInt32(key string, v int32) { e.AddInt64(key, int64(v)) }
+func (e *logfmtEncoder) AddInt16(key string, v int16) { e.AddInt64(key, int64(v)) }
+func (e *logfmtEncoder) AddInt8(key string, v int8)   { e.AddInt64(key, int64(v)) }
+
+func (e *logfmtEncoder) AddInt64(key string, v int64) {
+	e.addKey(key)
+	e.buf.AppendInt(v)
+}
+
+func (e *logfmtEncoder) AddTime(key string, v time.Time) {
+	e.addKey(key)
+	if e.cfg.EncodeTime == nil {
+		e.appendValue(v.Format(time.RFC3339Nano))
+		return
+	}
+	e.appendValue(encodeCell(func(enc zapcore.PrimitiveArrayEncoder) { e.cfg.EncodeTime(v, enc) }))
+}
+
+func (e *logfmtEncoder) AddUint(key string, v uint)       { e.AddUint64(key, uint64(v)) }
+func (e *logfmtEncoder) AddUint32(key string, v uint32)   { e.AddUint64(key, uint64(v)) }
+func (e *logfmtEncoder) AddUint16(key string, v uint16)   { e.AddUint64(key, uint64(v)) }
+func (e *logfmtEncoder) AddUint8(key string, v uint8)     { e.AddUint64(key, uint64(v)) }
+func (e *logfmtEncoder) AddUintptr(key string, v uintptr) { e.AddUint64(key, uint64(v)) }
+
+func (e *logfmtEncoder) AddUint64(key string, v uint64) {
+	e.addKey(key)
+	e.buf.AppendUint(v)
+}
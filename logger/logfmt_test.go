@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func logfmtTestConfig() zapcore.EncoderConfig {
+	return zapcore.EncoderConfig{
+		LevelKey:       "level",
+		MessageKey:     "msg",
+		EncodeLevel:    zapcore.CapitalLevelEncoder,
+		EncodeDuration: zapcore.StringDurationEncoder,
+	}
+}
+
+func TestLogfmtEncoder(t *testing.T) {
+	enc := newLogfmtEncoder(logfmtTestConfig())
+	zap.String("service", "api").AddTo(enc)
+	buf, err := enc.EncodeEntry(zapcore.Entry{Level: zapcore.WarnLevel, Message: "slow request"}, []zapcore.Field{
+		zap.String("path", "/orders list"),
+		zap.String("quote", `say "hi"`),
+		zap.String("empty", ""),
+		zap.String("eq", "a=b"),
+		zap.Int("status", 200),
+		zap.Bool("ok", false),
+		zap.Float64("ratio", 0.5),
+		zap.Float64("nan", math.NaN()),
+		zap.Duration("took", 1500*time.Millisecond),
+		zap.Strings("tags", []string{"a", "b"}),
+		zap.Namespace("http"),
+		zap.Int("code", 503),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `level=WARN msg="slow request" service=api path="/orders list" quote="say \"hi\"" empty="" eq="a=b"` +
+		` status=200 ok=false ratio=0.5 nan=NaN took=1.5s tags="[\"a\",\"b\"]" http.code=503` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("line =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestLogfmtEncoderCloneKeepsTheContext(t *testing.T) {
+	enc := newLogfmtEncoder(logfmtTestConfig())
+	enc.AddString("a", "1")
+	clone := enc.Clone()
+	clone.AddString("b", "2")
+	enc.AddString("c", "3")
+
+	for e, want := range map[zapcore.Encoder]string{enc: "msg=m a=1 c=3\n", clone: "msg=m a=1 b=2\n"} {
+		buf, _ := e.EncodeEntry(zapcore.Entry{Message: "m"}, nil)
+		if got := strings.TrimPrefix(buf.String(), "level=INFO "); got != want {
+			t.Errorf("line = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestLogfmtEncoding(t *testing.T) {
+	svc, path := newFileService(t, config.Logger{Encoding: EncodingLogfmt, LineEnding: LineEndingCRLF})
+	svc.Infoz("started", zap.Int("port", 8080))
+	svc.Sync()
+
+	got := readFile(t, path)
+	if !strings.HasSuffix(got, " message=started port=8080\r\n") || !strings.Contains(got, "level=INFO ") {
+		t.Errorf("file = %q, want a logfmt line ending in CRLF", got)
+	}
+}
@@ -0,0 +1,121 @@
+// Package loggorm provides a GORM logger writing through a logger.Service. It
+// is a module of its own so that only programs using it pull in the gorm
+// dependency.
+package loggorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dazzling420/go-logger/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	gormlogger "gorm.io/gorm/logger"
+	"gorm.io/gorm/utils"
+)
+
+// DefaultSlowThreshold is the duration above which New logs queries as slow.
+const DefaultSlowThreshold = 200 * time.Millisecond
+
+// Logger implements gorm's logger.Interface on top of a logger.Service. GORM
+// messages are logged at the matching level, queries at DEBUG, failed queries
+// at ERROR and queries slower than SlowThreshold at WARN, each with its sql,
+// rows and duration. The caller is the line of the program that ran the
+// query, as a source field, rather than GORM's own.
+type Logger struct {
+	svc   logger.Service
+	level gormlogger.LogLevel
+
+	// SlowThreshold is the duration above which queries are logged at WARN,
+	// 0 disables it.
+	SlowThreshold time.Duration
+	// IgnoreRecordNotFound logs queries failing with ErrRecordNotFound as
+	// successful ones.
+	IgnoreRecordNotFound bool
+}
+
+var _ gormlogger.Interface = (*Logger)(nil)
+
+// New returns a Logger for svc at gorm's Warn level, logging failed and slow
+// queries, with a SlowThreshold of DefaultSlowThreshold. Use it as the Logger
+// of gorm.Config, LogMode(gormlogger.Info) logging every query.
+func New(svc logger.Service) *Logger {
+	return &Logger{svc: svc, level: gormlogger.Warn, SlowThreshold: DefaultSlowThreshold}
+}
+
+// LogMode returns a copy of l logging at level.
+func (l *Logger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	c := *l
+	c.level = level
+	return &c
+}
+
+func (l *Logger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Info {
+		l.log(logger.INFO, fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *Logger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		l.log(logger.WARN, fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *Logger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Error {
+		l.log(logger.ERROR, fmt.Sprintf(msg, args...))
+	}
+}
+
+// Trace logs the query run since begin, fc returning its sql and the rows it
+// affected, -1 when unknown.
+func (l *Logger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+	elapsed := time.Since(begin)
+	if err != nil && l.IgnoreRecordNotFound && errors.Is(err, gormlogger.ErrRecordNotFound) {
+		err = nil
+	}
+
+	var level zapcore.Level
+	var msg string
+	var extra []zap.Field
+	switch {
+	case err != nil && l.level >= gormlogger.Error:
+		level, msg = logger.ERROR, "SQL query failed!"
+		extra = append(extra, zap.Error(err))
+	case l.SlowThreshold > 0 && elapsed > l.SlowThreshold && l.level >= gormlogger.Warn:
+		level, msg = logger.WARN, "Slow SQL query!"
+		extra = append(extra, zap.Duration("slow_threshold", l.SlowThreshold))
+	case l.level >= gormlogger.Info:
+		level, msg = logger.DEBUG, "SQL query"
+	default:
+		return
+	}
+
+	zl := l.svc.GetZapLogger()
+	if !zl.Core().Enabled(level) {
+		return
+	}
+	sql, rows := fc()
+	fields := []zap.Field{zap.String("sql", sql), zap.Duration("duration", elapsed)}
+	if rows >= 0 {
+		fields = append(fields, zap.Int64("rows", rows))
+	}
+	fields = append(fields, extra...)
+	l.write(zl, level, msg, fields...)
+}
+
+func (l *Logger) log(level zapcore.Level, msg string) {
+	l.write(l.svc.GetZapLogger(), level, msg)
+}
+
+// write logs with the caller replaced by the first frame outside GORM.
+func (l *Logger) write(zl *zap.Logger, level zapcore.Level, msg string, fields ...zap.Field) {
+	fields = append(fields, zap.String("source", utils.FileWithLineNum()))
+	zl.WithOptions(zap.WithCaller(false)).Log(level, msg, fields...)
+}
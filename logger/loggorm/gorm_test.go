@@ -0,0 +1,151 @@
+package loggorm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dazzling420/go-logger/logger"
+	"go.uber.org/zap/zapcore"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+const query = "SELECT * FROM users WHERE id = 1"
+
+func sql(rows int64) func() (string, int64) {
+	return func() (string, int64) { return query, rows }
+}
+
+func TestTrace(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		mode    gormlogger.LogLevel
+		elapsed time.Duration
+		err     error
+		level   zapcore.Level
+		message string // empty when nothing is logged
+	}{
+		{"fast query at Info", gormlogger.Info, time.Millisecond, nil, logger.DEBUG, "SQL query"},
+		{"fast query at Warn", gormlogger.Warn, time.Millisecond, nil, 0, ""},
+		{"slow query", gormlogger.Warn, time.Second, nil, logger.WARN, "Slow SQL query!"},
+		{"slow query at Error", gormlogger.Error, time.Second, nil, 0, ""},
+		{"failed query", gormlogger.Error, time.Millisecond, errors.New("broken"), logger.ERROR, "SQL query failed!"},
+		{"failed slow query", gormlogger.Warn, time.Second, errors.New("broken"), logger.ERROR, "SQL query failed!"},
+		{"silent", gormlogger.Silent, time.Second, errors.New("broken"), 0, ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, logs := logger.NewTestLogger()
+			l := New(svc).LogMode(tt.mode)
+
+			l.Trace(context.Background(), time.Now().Add(-tt.elapsed), sql(3), tt.err)
+
+			if tt.message == "" {
+				if logs.Len() != 0 {
+					t.Fatalf("logged %v, want nothing", logs.All())
+				}
+				return
+			}
+			if logs.Len() != 1 {
+				t.Fatalf("%d entries, want 1", logs.Len())
+			}
+			e := logs.All()[0]
+			if e.Level != tt.level || e.Message != tt.message {
+				t.Errorf("logged %v %q, want %v %q", e.Level, e.Message, tt.level, tt.message)
+			}
+			fields := e.ContextMap()
+			if fields["sql"] != query || fields["rows"] != int64(3) {
+				t.Errorf("fields = %v, want sql and rows", fields)
+			}
+			if d, ok := fields["duration"].(time.Duration); !ok || d < tt.elapsed {
+				t.Errorf("duration = %v, want at least %v", fields["duration"], tt.elapsed)
+			}
+			if _, ok := fields["source"]; !ok {
+				t.Error("no source field")
+			}
+			if tt.level == logger.WARN && fields["slow_threshold"] != DefaultSlowThreshold {
+				t.Errorf("slow_threshold = %v, want %v", fields["slow_threshold"], DefaultSlowThreshold)
+			}
+			if _, ok := fields["error"]; ok != (tt.err != nil) {
+				t.Errorf("error field present = %v, want %v", ok, tt.err != nil)
+			}
+		})
+	}
+}
+
+func TestTraceUnknownRows(t *testing.T) {
+	svc, logs := logger.NewTestLogger()
+	New(svc).LogMode(gormlogger.Info).Trace(context.Background(), time.Now(), sql(-1), nil)
+
+	if _, ok := logs.All()[0].ContextMap()["rows"]; ok {
+		t.Error("rows logged although unknown")
+	}
+}
+
+func TestTraceIgnoreRecordNotFound(t *testing.T) {
+	svc, logs := logger.NewTestLogger()
+	l := New(svc)
+	l.IgnoreRecordNotFound = true
+
+	l.Trace(context.Background(), time.Now(), sql(0), gormlogger.ErrRecordNotFound)
+	if logs.Len() != 0 {
+		t.Errorf("logged %v, want nothing for a record not found at Warn", logs.All())
+	}
+}
+
+func TestSlowThreshold(t *testing.T) {
+	svc, logs := logger.NewTestLogger()
+	l := New(svc)
+	l.SlowThreshold = 0
+
+	l.Trace(context.Background(), time.Now().Add(-time.Hour), sql(1), nil)
+	if logs.Len() != 0 {
+		t.Errorf("logged %v, want nothing with slow query detection disabled", logs.All())
+	}
+}
+
+func TestMessages(t *testing.T) {
+	svc, logs := logger.NewTestLogger()
+	l := New(svc).LogMode(gormlogger.Info)
+	ctx := context.Background()
+
+	l.Info(ctx, "migrated %d tables", 3)
+	l.Warn(ctx, "deprecated %s", "option")
+	l.Error(ctx, "failed: %v", "boom")
+
+	want := []struct {
+		level zapcore.Level
+		msg   string
+	}{
+		{logger.INFO, "migrated 3 tables"},
+		{logger.WARN, "deprecated option"},
+		{logger.ERROR, "failed: boom"},
+	}
+	entries := logs.All()
+	if len(entries) != len(want) {
+		t.Fatalf("%d entries, want %d", len(entries), len(want))
+	}
+	for i, w := range want {
+		if entries[i].Level != w.level || entries[i].Message != w.msg {
+			t.Errorf("entry %d = %v %q, want %v %q", i, entries[i].Level, entries[i].Message, w.level, w.msg)
+		}
+	}
+
+	// the default Warn mode leaves Info out
+	svc, logs = logger.NewTestLogger()
+	New(svc).Info(ctx, "migrated")
+	if logs.Len() != 0 {
+		t.Errorf("Info logged at the Warn mode: %v", logs.All())
+	}
+}
+
+func TestLogModeReturnsACopy(t *testing.T) {
+	svc, logs := logger.NewTestLogger()
+	l := New(svc)
+	l.LogMode(gormlogger.Info)
+
+	l.Info(context.Background(), "hidden")
+	if logs.Len() != 0 {
+		t.Error("LogMode changed the level of the original logger")
+	}
+}
@@ -0,0 +1,127 @@
+// Package loggrpc provides gRPC server interceptors logging through a
+// logger.Service. It is a module of its own so that only programs using it
+// pull in the grpc dependency.
+package loggrpc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/dazzling420/go-logger/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Option configures the interceptors.
+type Option func(*interceptor)
+
+// WithCodeLevel sets the function choosing the level a call is logged at from
+// its status code.
+func WithCodeLevel(f func(code codes.Code) zapcore.Level) Option {
+	return func(i *interceptor) {
+		i.level = f
+	}
+}
+
+// DefaultCodeLevel logs OK at INFO, codes caused by the caller at WARN and
+// server side failures at ERROR.
+func DefaultCodeLevel(code codes.Code) zapcore.Level {
+	switch code {
+	case codes.OK:
+		return logger.INFO
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists,
+		codes.PermissionDenied, codes.Unauthenticated, codes.ResourceExhausted,
+		codes.FailedPrecondition, codes.Aborted, codes.OutOfRange:
+		return logger.WARN
+	default:
+		return logger.ERROR
+	}
+}
+
+type interceptor struct {
+	svc   logger.Service
+	level func(code codes.Code) zapcore.Level
+}
+
+func newInterceptor(svc logger.Service, opts []Option) *interceptor {
+	i := &interceptor{svc: svc, level: DefaultCodeLevel}
+	for _, opt := range opts {
+		opt(i)
+	}
+	return i
+}
+
+// begin returns a child of the service carrying method and the request id
+// and a context holding both, along with a function logging the outcome of
+// the call.
+func (i *interceptor) begin(ctx context.Context, method string) (context.Context, func(err error)) {
+	start := time.Now()
+	id := incomingRequestID(ctx)
+	ctx = logger.NewContext(ctx, i.svc.GetLogger().With(zap.String("grpc_method", method)))
+	ctx = logger.WithIncomingRequestID(ctx, id)
+	child := logger.FromContext(ctx)
+
+	return ctx, func(err error) {
+		code := status.Code(err)
+		fields := []zap.Field{
+			zap.String("grpc_code", code.String()),
+			zap.Duration("duration", time.Since(start)),
+		}
+		if err != nil {
+			fields = append(fields, zap.Error(err))
+		}
+		child.GetZapLogger().Log(i.level(code), "grpc call", fields...)
+	}
+}
+
+// incomingRequestID returns the request id in the metadata of the call, ""
+// when there is none.
+func incomingRequestID(ctx context.Context) string {
+	ids := metadata.ValueFromIncomingContext(ctx, strings.ToLower(logger.RequestIDHeader))
+	if len(ids) == 0 {
+		return ""
+	}
+	return ids[0]
+}
+
+// UnaryServerInterceptor returns an interceptor logging one line per unary
+// call with its method, request id, status code and duration. The request id
+// is that of the x-request-id metadata of the call, or a generated one.
+// Handlers can get a child of svc carrying the method and request id with
+// logger.FromContext, and the id with logger.RequestIDFromContext.
+func UnaryServerInterceptor(svc logger.Service, opts ...Option) grpc.UnaryServerInterceptor {
+	i := newInterceptor(svc, opts)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, end := i.begin(ctx, info.FullMethod)
+		resp, err := handler(ctx, req)
+		end(err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor is the streaming equivalent of
+// UnaryServerInterceptor, logging once the stream handler returns.
+func StreamServerInterceptor(svc logger.Service, opts ...Option) grpc.StreamServerInterceptor {
+	i := newInterceptor(svc, opts)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, end := i.begin(ss.Context(), info.FullMethod)
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		end(err)
+		return err
+	}
+}
+
+// serverStream overrides the context of a grpc.ServerStream.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
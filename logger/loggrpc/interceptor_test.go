@@ -0,0 +1,141 @@
+package loggrpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dazzling420/go-logger/logger"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const method = "/greeter.Greeter/SayHello"
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		err   error
+		level zapcore.Level
+		code  string
+	}{
+		{"ok", nil, logger.INFO, "OK"},
+		{"caller error", status.Error(codes.NotFound, "no such greeting"), logger.WARN, "NotFound"},
+		{"server error", status.Error(codes.Internal, "broken"), logger.ERROR, "Internal"},
+		{"plain error", errors.New("broken"), logger.ERROR, "Unknown"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, logs := logger.NewTestLogger()
+			intercept := UnaryServerInterceptor(svc)
+
+			_, err := intercept(context.Background(), "req", &grpc.UnaryServerInfo{FullMethod: method},
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					logger.FromContext(ctx).Infoz("handling")
+					return "resp", tt.err
+				})
+			if err != tt.err {
+				t.Errorf("interceptor returned %v, want %v", err, tt.err)
+			}
+
+			entries := logs.FilterMessage("grpc call").All()
+			if len(entries) != 1 {
+				t.Fatalf("%d grpc call entries, want 1", len(entries))
+			}
+			e := entries[0]
+			if e.Level != tt.level {
+				t.Errorf("level = %v, want %v", e.Level, tt.level)
+			}
+			fields := e.ContextMap()
+			if fields["grpc_method"] != method || fields["grpc_code"] != tt.code {
+				t.Errorf("fields = %v, want grpc_method %s and grpc_code %s", fields, method, tt.code)
+			}
+			if _, ok := fields["duration"]; !ok {
+				t.Error("no duration field")
+			}
+			if _, ok := fields["error"]; ok != (tt.err != nil) {
+				t.Errorf("error field present = %v, want %v", ok, tt.err != nil)
+			}
+
+			handling := logs.FilterMessage("handling").All()
+			if len(handling) != 1 || handling[0].ContextMap()["grpc_method"] != method {
+				t.Errorf("handler entries = %v, want one carrying the method", handling)
+			}
+		})
+	}
+}
+
+func TestWithCodeLevel(t *testing.T) {
+	svc, logs := logger.NewTestLogger()
+	intercept := UnaryServerInterceptor(svc, WithCodeLevel(func(codes.Code) zapcore.Level { return logger.DEBUG }))
+
+	intercept(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, status.Error(codes.Internal, "broken")
+		})
+	if entries := logs.FilterMessage("grpc call").All(); len(entries) != 1 || entries[0].Level != logger.DEBUG {
+		t.Errorf("entries = %v, want one at DEBUG", entries)
+	}
+}
+
+func TestRequestIDPropagation(t *testing.T) {
+	svc, logs := logger.NewTestLogger()
+	intercept := UnaryServerInterceptor(svc)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "upstream-1"))
+
+	var seen string
+	intercept(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			seen = logger.RequestIDFromContext(ctx)
+			return nil, nil
+		})
+	if seen != "upstream-1" {
+		t.Errorf("handler saw request id %q, want upstream-1", seen)
+	}
+	if id := logs.FilterMessage("grpc call").All()[0].ContextMap()["request_id"]; id != "upstream-1" {
+		t.Errorf("request_id = %v, want upstream-1", id)
+	}
+
+	// without metadata an id is generated
+	intercept(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			seen = logger.RequestIDFromContext(ctx)
+			return nil, nil
+		})
+	if seen == "" || seen == "upstream-1" {
+		t.Errorf("handler saw request id %q, want a generated one", seen)
+	}
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	svc, logs := logger.NewTestLogger()
+	intercept := StreamServerInterceptor(svc)
+
+	err := intercept(nil, fakeServerStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: method},
+		func(srv interface{}, ss grpc.ServerStream) error {
+			logger.FromContext(ss.Context()).Infoz("streaming")
+			return status.Error(codes.Canceled, "gone")
+		})
+	if status.Code(err) != codes.Canceled {
+		t.Errorf("interceptor returned %v, want the handler's error", err)
+	}
+
+	entries := logs.FilterMessage("grpc call").All()
+	if len(entries) != 1 || entries[0].Level != logger.WARN || entries[0].ContextMap()["grpc_code"] != "Canceled" {
+		t.Errorf("entries = %v, want one Canceled call at WARN", entries)
+	}
+	if streaming := logs.FilterMessage("streaming").All(); len(streaming) != 1 || streaming[0].ContextMap()["grpc_method"] != method {
+		t.Errorf("handler entries = %v, want one carrying the method", streaming)
+	}
+}
@@ -0,0 +1,21 @@
+module github.com/dazzling420/go-logger/logger/logkafka
+
+go 1.22.5
+
+require (
+	github.com/dazzling420/go-logger v0.0.0-00010101000000-000000000000
+	github.com/segmentio/kafka-go v0.4.48
+	go.uber.org/zap v1.27.0
+)
+
+require (
+	github.com/fsnotify/fsnotify v1.8.0 // indirect
+	github.com/klauspost/compress v1.18.0 // indirect
+	github.com/pierrec/lz4/v4 v4.1.15 // indirect
+	go.uber.org/multierr v1.10.0 // indirect
+	golang.org/x/sys v0.24.0 // indirect
+	gopkg.in/natefinch/lumberjack.v2 v2.2.1 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/dazzling420/go-logger => ../..
@@ -0,0 +1,45 @@
+// Package logkafka produces log entries to Kafka with
+// github.com/segmentio/kafka-go. It is a module of its own so that only
+// programs shipping logs to Kafka pull in the kafka-go dependency.
+package logkafka
+
+import (
+	"context"
+
+	"github.com/dazzling420/go-logger/logger"
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap/zapcore"
+)
+
+// Producer adapts a kafka-go Writer to logger.KafkaProducer.
+type Producer struct {
+	w *kafka.Writer
+}
+
+// NewProducer returns a Producer writing to w.
+func NewProducer(w *kafka.Writer) Producer {
+	return Producer{w}
+}
+
+func (p Producer) Produce(ctx context.Context, msgs []logger.KafkaMessage) error {
+	kmsgs := make([]kafka.Message, len(msgs))
+	for i, m := range msgs {
+		kmsgs[i] = kafka.Message{Topic: m.Topic, Key: m.Key, Value: m.Value, Time: m.Time}
+	}
+	return p.w.WriteMessages(ctx, kmsgs...)
+}
+
+func (p Producer) Close() error {
+	return p.w.Close()
+}
+
+// NewCore returns a core producing entries at or above minLevel to topic on
+// the given brokers, keyed by the value of the keyField field when keyField is
+// set, see logger.NewKafkaProducerCore.
+func NewCore(brokers []string, topic string, minLevel zapcore.Level, keyField string) *logger.KafkaCore {
+	w := &kafka.Writer{
+		Addr:     kafka.TCP(brokers...),
+		Balancer: &kafka.Hash{},
+	}
+	return logger.NewKafkaProducerCore(NewProducer(w), topic, minLevel, keyField)
+}
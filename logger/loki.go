@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const lokiPushPath = "/loki/api/v1/push"
+
+// lokiBufferSize is the number of entries a LokiCore queues before it starts
+// dropping them.
+const lokiBufferSize = 10000
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+type lokiPush struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+// LokiCore pushes entries to Grafana Loki without ever blocking the logging
+// goroutine. Use Close to push whatever is still buffered.
+type LokiCore struct {
+	*batchCore
+}
+
+// NewLokiCore returns a core pushing entries at or above minLevel to the Loki
+// server at url. Entries are JSON encoded and sent in batches, one stream per
+// level labelled with labels plus a "level" label. A batch is pushed once it
+// holds 100 entries or every second, whichever comes first. A batch Loki
+// fails to accept is dropped, not pushed again. Entries queue up while a push
+// is in flight, once the buffer is full further entries are dropped and
+// counted, see Dropped.
+func NewLokiCore(url string, labels map[string]string, minLevel zapcore.Level) *LokiCore {
+	if !strings.HasSuffix(url, lokiPushPath) {
+		url = strings.TrimSuffix(url, "/") + lokiPushPath
+	}
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	flush := func(batch []batchEntry) error {
+		body, err := json.Marshal(lokiPayload(labels, batch))
+		if err != nil {
+			return err
+		}
+		resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("loki push: unexpected status %s", resp.Status)
+		}
+		return nil
+	}
+
+	b := newBatcher(defaultBatchSize, defaultBatchInterval, flush)
+	b.limit = lokiBufferSize
+	return &LokiCore{batchCore: newBatchCore(zapcore.NewJSONEncoder(newEncoderConfig()), minLevel, b)}
+}
+
+// Dropped returns the number of entries dropped because the buffer was full.
+func (c *LokiCore) Dropped() uint64 {
+	return c.b.dropped.Load()
+}
+
+// lokiPayload groups batch into one stream per level.
+func lokiPayload(labels map[string]string, batch []batchEntry) lokiPush {
+	var push lokiPush
+	streams := map[zapcore.Level]int{}
+	for _, e := range batch {
+		i, ok := streams[e.Entry.Level]
+		if !ok {
+			stream := make(map[string]string, len(labels)+1)
+			stream["level"] = strings.ToLower(LevelName(e.Entry.Level))
+			for k, v := range labels {
+				stream[k] = v
+			}
+			i = len(push.Streams)
+			streams[e.Entry.Level] = i
+			push.Streams = append(push.Streams, lokiStream{Stream: stream})
+		}
+		ts := strconv.FormatInt(e.Entry.Time.UnixNano(), 10)
+		push.Streams[i].Values = append(push.Streams[i].Values, [2]string{ts, string(e.Line)})
+	}
+	return push
+}
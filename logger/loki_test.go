@@ -0,0 +1,101 @@
+package logger
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestLokiCorePushesOneStreamPerLevel(t *testing.T) {
+	var mu sync.Mutex
+	var pushes []lokiPush
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != lokiPushPath || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("push to %s as %s", r.URL.Path, r.Header.Get("Content-Type"))
+		}
+		var push lokiPush
+		if err := json.NewDecoder(r.Body).Decode(&push); err != nil {
+			t.Error(err)
+		}
+		mu.Lock()
+		pushes = append(pushes, push)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	core := NewLokiCore(srv.URL, map[string]string{"app": "api"}, INFO)
+	log := zap.New(core)
+	log.Info("started")
+	log.Debug("not shipped")
+	log.Error("failed")
+	log.Info("stopped")
+	if err := core.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	got := map[string][]string{}
+	for _, push := range pushes {
+		for _, s := range push.Streams {
+			if s.Stream["app"] != "api" {
+				t.Errorf("stream labels = %v, want app=api", s.Stream)
+			}
+			for _, v := range s.Values {
+				var entry map[string]interface{}
+				if err := json.Unmarshal([]byte(v[1]), &entry); err != nil {
+					t.Fatal(err)
+				}
+				got[s.Stream["level"]] = append(got[s.Stream["level"]], entry["message"].(string))
+			}
+		}
+	}
+	levels := make([]string, 0, len(got))
+	for l := range got {
+		levels = append(levels, l)
+	}
+	sort.Strings(levels)
+	if strings.Join(levels, ",") != "error,info" {
+		t.Fatalf("streams = %v, want error and info", got)
+	}
+	if strings.Join(got["info"], ",") != "started,stopped" || strings.Join(got["error"], ",") != "failed" {
+		t.Errorf("streams = %v", got)
+	}
+}
+
+func TestLokiCoreDropsOnceTheBufferIsFull(t *testing.T) {
+	received := make(chan struct{}, 1)
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case received <- struct{}{}:
+		default:
+		}
+		<-release
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	core := NewLokiCore(srv.URL, nil, INFO)
+	log := zap.New(core)
+	// a full batch is pushed at once, and Loki holds on to it
+	for i := 0; i < defaultBatchSize; i++ {
+		log.Info("batch")
+	}
+	<-received
+	for i := 0; i < lokiBufferSize+5; i++ {
+		log.Info("queued")
+	}
+	if got := core.Dropped(); got != 5 {
+		t.Errorf("Dropped() = %d, want 5", got)
+	}
+	close(release)
+	core.Close()
+}
@@ -0,0 +1,107 @@
+package logger
+
+import (
+	"regexp"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// maskRule is a compiled config.MaskRule.
+type maskRule struct {
+	re          *regexp.Regexp
+	replacement string
+}
+
+// compileMaskRules compiles rules, leaving out the invalid ones Validate
+// reports, as well as those matching the empty string.
+func compileMaskRules(rules []config.MaskRule) []maskRule {
+	compiled := make([]maskRule, 0, len(rules))
+	for _, rule := range rules {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil || re.MatchString("") {
+			continue
+		}
+		compiled = append(compiled, maskRule{re: re, replacement: rule.Replacement})
+	}
+	return compiled
+}
+
+// WithMasking returns an option, for NewService or zap loggers, that applies
+// rules to the message and string fields of every entry, see
+// config.MaskRule. Go regular expressions run in linear time, so a pattern
+// can't make logging hang, but each rule costs a scan of every string.
+func WithMasking(rules ...config.MaskRule) zap.Option {
+	compiled := compileMaskRules(rules)
+	return zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return newMaskCore(core, compiled)
+	})
+}
+
+func newMaskCore(core zapcore.Core, rules []maskRule) zapcore.Core {
+	return &maskCore{Core: core, rules: rules}
+}
+
+type maskCore struct {
+	zapcore.Core
+	rules []maskRule
+}
+
+func (c *maskCore) With(fields []zapcore.Field) zapcore.Core {
+	return &maskCore{Core: c.Core.With(c.maskFields(fields)), rules: c.rules}
+}
+
+func (c *maskCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *maskCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ent.Message = c.mask(ent.Message)
+	return c.Core.Write(ent, c.maskFields(fields))
+}
+
+// maskFields returns fields with the rules applied to the string fields,
+// copying fields only when one changes.
+func (c *maskCore) maskFields(fields []zapcore.Field) []zapcore.Field {
+	var out []zapcore.Field
+	for i, f := range fields {
+		var s string
+		switch f.Type {
+		case zapcore.StringType:
+			s = f.String
+		case zapcore.ByteStringType:
+			s = string(f.Interface.([]byte))
+		default:
+			if out != nil {
+				out = append(out, f)
+			}
+			continue
+		}
+		masked := c.mask(s)
+		if masked == s {
+			if out != nil {
+				out = append(out, f)
+			}
+			continue
+		}
+		if out == nil {
+			out = append(make([]zapcore.Field, 0, len(fields)), fields[:i]...)
+		}
+		out = append(out, zap.String(f.Key, masked))
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+func (c *maskCore) mask(s string) string {
+	for _, rule := range c.rules {
+		s = rule.re.ReplaceAllString(s, rule.replacement)
+	}
+	return s
+}
@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap"
+)
+
+var testMaskRules = []config.MaskRule{
+	{Pattern: `\b(?:\d[ -]?){12}(\d{4})\b`, Replacement: "****-$1"},
+	{Pattern: `[\w.+-]+@[\w-]+\.[\w.]+`, Replacement: "[email]"},
+}
+
+func TestMaskRules(t *testing.T) {
+	s, path := newFileService(t, config.Logger{MaskRules: testMaskRules})
+	s.With(zap.String("contact", "bob@example.com")).Infoz("paid with 4111 1111 1111 1234",
+		zap.String("card", "4111-1111-1111-1234"),
+		zap.ByteString("raw", []byte("to alice@example.org")),
+		zap.Int("amount", 42),
+		zap.String("note", "nothing to mask"))
+	s.Sync()
+
+	entries := readEntries(t, path)
+	if len(entries) != 1 {
+		t.Fatalf("logged %d entries, want 1", len(entries))
+	}
+	e := entries[0]
+	for key, want := range map[string]interface{}{
+		"message": "paid with ****-1234",
+		"card":    "****-1234",
+		"raw":     "to [email]",
+		"contact": "[email]",
+		"amount":  float64(42),
+		"note":    "nothing to mask",
+	} {
+		if e[key] != want {
+			t.Errorf("%s = %v, want %v", key, e[key], want)
+		}
+	}
+}
+
+func TestWithMasking(t *testing.T) {
+	svc, logs := NewTestLogger(WithMasking(testMaskRules...))
+	svc.Infoz("mail bob@example.com", zap.String("card", "4111111111111234"))
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("logged %d entries, want 1", len(entries))
+	}
+	if entries[0].Message != "mail [email]" {
+		t.Errorf("message = %q, want it masked", entries[0].Message)
+	}
+	if got := entries[0].ContextMap()["card"]; got != "****-1234" {
+		t.Errorf("card = %v, want ****-1234", got)
+	}
+}
+
+func TestCompileMaskRulesLeavesOutInvalidRules(t *testing.T) {
+	rules := compileMaskRules([]config.MaskRule{
+		{Pattern: "(card"},
+		{Pattern: "x*"},
+		{Pattern: "secret", Replacement: "***"},
+	})
+	if len(rules) != 1 || rules[0].re.String() != "secret" {
+		t.Errorf("compiled %v, want only the valid rule", rules)
+	}
+}
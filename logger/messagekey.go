@@ -0,0 +1,192 @@
+package logger
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// messageKey is carried by the field LogWithMessageKey adds to its entry.
+type messageKey string
+
+// LogWithMessageKey logs msg at level under key instead of the configured
+// message key, for this entry only. Only the json encoding of the outputs
+// built by NewService honors the override; console and csv, whose columns are
+// fixed, as well as cores added through options write the entry as usual.
+// Overriding the key costs a new encoder per call, so it is meant for the odd
+// entry.
+func (s *standardLogger) LogWithMessageKey(key, level, msg string, fields ...Field) {
+	override := zap.Field{Type: zapcore.SkipType, Interface: messageKey(key)}
+	s.log.Log(GetLevel(level), msg, append(fields[:len(fields):len(fields)], override)...)
+}
+
+// messageKeyEncoder encodes entries carrying a LogWithMessageKey field with a
+// fresh encoder built by build using that message key. It records the fields
+// added to it by With so that encoder can be given them as well.
+type messageKeyEncoder struct {
+	zapcore.Encoder
+	cfg     zapcore.EncoderConfig
+	build   func(zapcore.EncoderConfig) zapcore.Encoder
+	context []zapcore.Field
+}
+
+func newMessageKeyEncoder(cfg zapcore.EncoderConfig, build func(zapcore.EncoderConfig) zapcore.Encoder) zapcore.Encoder {
+	return &messageKeyEncoder{Encoder: build(cfg), cfg: cfg, build: build}
+}
+
+func (e *messageKeyEncoder) Clone() zapcore.Encoder {
+	return &messageKeyEncoder{
+		Encoder: e.Encoder.Clone(),
+		cfg:     e.cfg,
+		build:   e.build,
+		context: e.context[:len(e.context):len(e.context)],
+	}
+}
+
+func (e *messageKeyEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	for _, f := range fields {
+		key, ok := f.Interface.(messageKey)
+		if !ok || f.Type != zapcore.SkipType {
+			continue
+		}
+		cfg := e.cfg
+		cfg.MessageKey = string(key)
+		enc := e.build(cfg)
+		for _, c := range e.context {
+			c.AddTo(enc)
+		}
+		return enc.EncodeEntry(ent, fields)
+	}
+	return e.Encoder.EncodeEntry(ent, fields)
+}
+
+func (e *messageKeyEncoder) record(f zapcore.Field) {
+	e.context = append(e.context, f)
+}
+
+func (e *messageKeyEncoder) AddArray(key string, v zapcore.ArrayMarshaler) error {
+	e.record(zap.Array(key, v))
+	return e.Encoder.AddArray(key, v)
+}
+
+func (e *messageKeyEncoder) AddObject(key string, v zapcore.ObjectMarshaler) error {
+	e.record(zap.Object(key, v))
+	return e.Encoder.AddObject(key, v)
+}
+
+func (e *messageKeyEncoder) AddBinary(key string, v []byte) {
+	e.record(zap.Binary(key, v))
+	e.Encoder.AddBinary(key, v)
+}
+
+func (e *messageKeyEncoder) AddByteString(key string, v []byte) {
+	e.record(zap.ByteString(key, v))
+	e.Encoder.AddByteString(key, v)
+}
+
+func (e *messageKeyEncoder) AddBool(key string, v bool) {
+	e.record(zap.Bool(key, v))
+	e.Encoder.AddBool(key, v)
+}
+
+func (e *messageKeyEncoder) AddComplex128(key string, v complex128) {
+	e.record(zap.Complex128(key, v))
+	e.Encoder.AddComplex128(key, v)
+}
+
+func (e *messageKeyEncoder) AddComplex64(key string, v complex64) {
+	e.record(zap.Complex64(key, v))
+	e.Encoder.AddComplex64(key, v)
+}
+
+func (e *messageKeyEncoder) AddDuration(key string, v time.Duration) {
+	e.record(zap.Duration(key, v))
+	e.Encoder.AddDuration(key, v)
+}
+
+func (e *messageKeyEncoder) AddFloat64(key string, v float64) {
+	e.record(zap.Float64(key, v))
+	e.Encoder.AddFloat64(key, v)
+}
+
+func (e *messageKeyEncoder) AddFloat32(key string, v float32) {
+	e.record(zap.Float32(key, v))
+	e.Encoder.AddFloat32(key, v)
+}
+
+func (e *messageKeyEncoder) AddInt(key string, v int) {
+	e.record(zap.Int(key, v))
+	e.Encoder.AddInt(key, v)
+}
+
+func (e *messageKeyEncoder) AddInt64(key string, v int64) {
+	e.record(zap.Int64(key, v))
+	e.Encoder.AddInt64(key, v)
+}
+
+func (e *messageKeyEncoder) AddInt32(key string, v int32) {
+	e.record(zap.Int32(key, v))
+	e.Encoder.AddInt32(key, v)
+}
+
+func (e *messageKeyEncoder) AddInt16(key string, v int16) {
+	e.record(zap.Int16(key, v))
+	e.Encoder.AddInt16(key, v)
+}
+
+func (e *messageKeyEncoder) AddInt8(key string, v int8) {
+	e.record(zap.Int8(key, v))
+	e.Encoder.AddInt8(key, v)
+}
+
+func (e *messageKeyEncoder) AddString(key, v string) {
+	e.record(zap.String(key, v))
+	e.Encoder.AddString(key, v)
+}
+
+func (e *messageKeyEncoder) AddTime(key string, v time.Time) {
+	e.record(zap.Time(key, v))
+	e.Encoder.AddTime(key, v)
+}
+
+func (e *messageKeyEncoder) AddUint(key string, v uint) {
+	e.record(zap.Uint(key, v))
+	e.Encoder.AddUint(key, v)
+}
+
+func (e *messageKeyEncoder) AddUint64(key string, v uint64) {
+	e.record(zap.Uint64(key, v))
+	e.Encoder.AddUint64(key, v)
+}
+
+func (e *messageKeyEncoder) AddUint32(key string, v uint32) {
+	e.record(zap.Uint32(key, v))
+	e.Encoder.AddUint32(key, v)
+}
+
+func (e *messageKeyEncoder) AddUint16(key string, v uint16) {
+	e.record(zap.Uint16(key, v))
+	e.Encoder.AddUint16(key, v)
+}
+
+func (e *messageKeyEncoder) AddUint8(key string, v uint8) {
+	e.record(zap.Uint8(key, v))
+	e.Encoder.AddUint8(key, v)
+}
+
+func (e *messageKeyEncoder) AddUintptr(key string, v uintptr) {
+	e.record(zap.Uintptr(key, v))
+	e.Encoder.AddUintptr(key, v)
+}
+
+func (e *messageKeyEncoder) AddReflected(key string, v interface{}) error {
+	e.record(zap.Reflect(key, v))
+	return e.Encoder.AddReflected(key, v)
+}
+
+func (e *messageKeyEncoder) OpenNamespace(key string) {
+	e.record(zap.Namespace(key))
+	e.Encoder.OpenNamespace(key)
+}
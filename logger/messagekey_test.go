@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLogWithMessageKey(t *testing.T) {
+	svc, path := newFileService(t, config.Logger{})
+	child := svc.With(zap.String("service", "api"))
+	child.LogWithMessageKey("event", "WARN", "user signed up", zap.String("user", "bob"))
+	child.Infoz("as usual")
+	svc.Sync()
+
+	entries := readEntries(t, path)
+	if len(entries) != 2 {
+		t.Fatalf("wrote %d entries, want 2", len(entries))
+	}
+	overridden := entries[0]
+	if overridden["event"] != "user signed up" || overridden["message"] != nil {
+		t.Errorf("entry = %v, want the message under event", overridden)
+	}
+	if overridden["level"] != "WARN" || overridden["user"] != "bob" || overridden["service"] != "api" {
+		t.Errorf("entry = %v, want WARN with its fields and those of the logger", overridden)
+	}
+	if usual := entries[1]; usual["message"] != "as usual" || usual["event"] != nil || usual["service"] != "api" {
+		t.Errorf("next entry = %v, want the configured message key", usual)
+	}
+}
+
+func TestLogWithMessageKeyInANamespace(t *testing.T) {
+	svc, path := newFileService(t, config.Logger{})
+	svc.With(zap.Namespace("request"), zap.String("id", "42")).LogWithMessageKey("event", "INFO", "done")
+	svc.Sync()
+
+	entries := readEntries(t, path)
+	if len(entries) != 1 {
+		t.Fatalf("wrote %d entries, want 1", len(entries))
+	}
+	request, _ := entries[0]["request"].(map[string]interface{})
+	if entries[0]["event"] != "done" || request["id"] != "42" {
+		t.Errorf("entry = %v, want event=done and request.id=42", entries[0])
+	}
+}
+
+func TestLogWithMessageKeyLeavesTheFieldsPassedAlone(t *testing.T) {
+	svc, _ := NewTestLogger()
+	fields := make([]Field, 1, 2)
+	fields[0] = zap.String("user", "bob")
+	svc.(*standardLogger).LogWithMessageKey("event", "INFO", "user signed up", fields...)
+
+	if spare := fields[:2][1]; spare.Type != zapcore.UnknownType {
+		t.Errorf("LogWithMessageKey wrote %+v past the fields passed", spare)
+	}
+}
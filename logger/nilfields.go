@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"reflect"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// nilFieldCore gives nil values passed to the field helpers a consistent
+// shape: nil errors never produce a field, and nil struct pointers (one handed
+// to Any or Stringer for example) are skipped unless keepNull is set, in which
+// case they are written as an explicit null. Other nil values, such as nil
+// slices and maps, are left to the encoder.
+type nilFieldCore struct {
+	zapcore.Core
+	keepNull bool
+}
+
+func newNilFieldCore(core zapcore.Core, keepNull bool) zapcore.Core {
+	return &nilFieldCore{Core: core, keepNull: keepNull}
+}
+
+func (c *nilFieldCore) With(fields []zapcore.Field) zapcore.Core {
+	return &nilFieldCore{Core: c.Core.With(c.filter(fields)), keepNull: c.keepNull}
+}
+
+func (c *nilFieldCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *nilFieldCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, c.filter(fields))
+}
+
+func (c *nilFieldCore) filter(fields []zapcore.Field) []zapcore.Field {
+	var out []zapcore.Field
+	for i, f := range fields {
+		isNil := isNilField(f)
+		if out == nil {
+			if !isNil {
+				continue
+			}
+			// first nil field, copy the ones before it and filter from here on
+			out = make([]zapcore.Field, i, len(fields))
+			copy(out, fields[:i])
+		}
+		switch {
+		case !isNil:
+			out = append(out, f)
+		case c.keepNull && f.Type != zapcore.ErrorType:
+			out = append(out, zap.Reflect(f.Key, nil))
+		}
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+func isNilField(f zapcore.Field) bool {
+	switch f.Type {
+	case zapcore.ErrorType:
+		return isNilError(f.Interface)
+	case zapcore.ReflectType, zapcore.StringerType,
+		zapcore.ObjectMarshalerType, zapcore.ArrayMarshalerType:
+		return isNilStructPointer(f.Interface)
+	}
+	return false
+}
+
+// isNilError reports whether v is a nil error, typed nils included.
+func isNilError(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface, reflect.Func, reflect.Chan:
+		return rv.IsNil()
+	}
+	return false
+}
+
+func isNilStructPointer(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Ptr && rv.IsNil() && rv.Type().Elem().Kind() == reflect.Struct
+}
+
+func nilFieldOption(keepNull bool) zap.Option {
+	return zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return newNilFieldCore(core, keepNull)
+	})
+}
@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type nilTestError struct{}
+
+func (*nilTestError) Error() string { return "never called" }
+
+func nilFields() []Field {
+	return []Field{
+		zap.String("kept", "x"),
+		zap.NamedError("cause", (*nilTestError)(nil)),
+		zap.Any("user", (*struct{ Name string })(nil)),
+		zap.Stringer("buf", (*bytes.Buffer)(nil)),
+		zap.Any("tags", map[string]string(nil)),
+		zap.Any("ids", []string(nil)),
+	}
+}
+
+func TestNilFieldCore(t *testing.T) {
+	for _, tt := range []struct {
+		keepNull bool
+		want     []string
+	}{
+		{false, []string{"kept", "tags", "ids"}},
+		{true, []string{"kept", "user", "buf", "tags", "ids"}},
+	} {
+		core, logs := observer.New(allLevels)
+		log := zap.New(newNilFieldCore(core, tt.keepNull))
+		log.Info("entry", nilFields()...)
+		log.With(nilFields()...).Info("context")
+
+		for _, e := range logs.AllUntimed() {
+			fields := e.Context
+			if len(fields) != len(tt.want) {
+				t.Errorf("keepNull=%v %s: fields = %v, want %v", tt.keepNull, e.Message, fields, tt.want)
+				continue
+			}
+			for i, f := range fields {
+				if f.Key != tt.want[i] {
+					t.Errorf("keepNull=%v %s: field %d = %s, want %s", tt.keepNull, e.Message, i, f.Key, tt.want[i])
+				}
+				if (f.Key == "user" || f.Key == "buf") && (f.Type != zapcore.ReflectType || f.Interface != nil) {
+					t.Errorf("keepNull=%v %s: %s = %+v, want an explicit null", tt.keepNull, e.Message, f.Key, f)
+				}
+			}
+		}
+	}
+}
+
+func TestNilFieldsAsNull(t *testing.T) {
+	for _, keepNull := range []bool{false, true} {
+		svc, path := newFileService(t, config.Logger{NilFieldsAsNull: keepNull})
+		svc.Infoz("nils", nilFields()...)
+
+		entry := readEntries(t, path)[0]
+		if _, ok := entry["cause"]; ok {
+			t.Errorf("NilFieldsAsNull=%v: a nil error was written: %v", keepNull, entry)
+		}
+		for _, key := range []string{"user", "buf"} {
+			v, ok := entry[key]
+			if ok != keepNull || v != nil {
+				t.Errorf("NilFieldsAsNull=%v: %s = %v (present %v)", keepNull, key, v, ok)
+			}
+		}
+		for _, key := range []string{"tags", "ids"} {
+			if _, ok := entry[key]; !ok {
+				t.Errorf("NilFieldsAsNull=%v: the nil %s was dropped: %v", keepNull, key, entry)
+			}
+		}
+	}
+}
+
+func TestNilFieldsWithoutNewService(t *testing.T) {
+	core, coreLogs := observer.New(allLevels)
+	testSvc, testLogs := NewTestLogger()
+	for _, tt := range []struct {
+		name string
+		svc  Service
+		logs *observer.ObservedLogs
+	}{
+		{"NewServiceFromCore", NewServiceFromCore(core), coreLogs},
+		{"NewTestLogger", testSvc, testLogs},
+	} {
+		tt.svc.Infoz("nils", nilFields()...)
+		var keys []string
+		for _, f := range tt.logs.All()[0].Context {
+			keys = append(keys, f.Key)
+		}
+		if got, want := strings.Join(keys, ","), "kept,tags,ids"; got != want {
+			t.Errorf("%s: fields = %s, want %s", tt.name, got, want)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const otlpLogsPath = "/v1/logs"
+
+// otlpSeverities maps levels to OTLP severity numbers.
+var otlpSeverities = map[zapcore.Level]int{
+	TRACE:  1,
+	DEBUG:  5,
+	INFO:   9,
+	AUDIT:  10,
+	WARN:   13,
+	ERROR:  17,
+	DPANIC: 21,
+	PANIC:  21,
+	FATAL:  21,
+}
+
+// The types below follow the JSON encoding of the OTLP logs protocol.
+
+type otlpExport struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano         string         `json:"timeUnixNano"`
+	ObservedTimeUnixNano string         `json:"observedTimeUnixNano"`
+	SeverityNumber       int            `json:"severityNumber"`
+	SeverityText         string         `json:"severityText"`
+	Body                 otlpAnyValue   `json:"body"`
+	Attributes           []otlpKeyValue `json:"attributes,omitempty"`
+	TraceID              string         `json:"traceId,omitempty"`
+	SpanID               string         `json:"spanId,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string         `json:"stringValue,omitempty"`
+	BoolValue   *bool           `json:"boolValue,omitempty"`
+	IntValue    *string         `json:"intValue,omitempty"` // int64 as a string, as OTLP/JSON encodes them
+	DoubleValue *float64        `json:"doubleValue,omitempty"`
+	ArrayValue  *otlpArrayValue `json:"arrayValue,omitempty"`
+	KvlistValue *otlpKvlist     `json:"kvlistValue,omitempty"`
+}
+
+type otlpArrayValue struct {
+	Values []otlpAnyValue `json:"values"`
+}
+
+type otlpKvlist struct {
+	Values []otlpKeyValue `json:"values"`
+}
+
+// NewOTLPCore returns a core exporting entries at or above minLevel as OTLP
+// log records to the OpenTelemetry collector at endpoint, e.g.
+// "http://localhost:4318", over OTLP/HTTP with JSON payloads; gRPC isn't
+// supported. Fields become attributes and string trace_id and span_id fields
+// holding hex ids set the trace context of the record. Records are sent in
+// batches of 100 or every second, whichever comes first. The returned core
+// implements io.Closer, Close exports whatever is left.
+func NewOTLPCore(endpoint string, minLevel zapcore.Level) zapcore.Core {
+	if !strings.HasSuffix(endpoint, otlpLogsPath) {
+		endpoint = strings.TrimSuffix(endpoint, "/") + otlpLogsPath
+	}
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	flush := func(batch []batchEntry) error {
+		body, err := json.Marshal(otlpPayload(batch, time.Now()))
+		if err != nil {
+			return err
+		}
+		resp, err := client.Post(endpoint, "application/json", bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("otlp export: unexpected status %s", resp.Status)
+		}
+		return nil
+	}
+
+	b := newBatcher(defaultBatchSize, defaultBatchInterval, flush)
+	return newBatchCore(zapcore.NewJSONEncoder(newEncoderConfig()), minLevel, b)
+}
+
+// otlpPayload converts batch to an OTLP export request observed at now.
+func otlpPayload(batch []batchEntry, now time.Time) otlpExport {
+	records := make([]otlpLogRecord, 0, len(batch))
+	for _, e := range batch {
+		records = append(records, otlpRecord(e, now))
+	}
+	return otlpExport{ResourceLogs: []otlpResourceLogs{{
+		Resource: otlpResource{Attributes: []otlpKeyValue{}},
+		ScopeLogs: []otlpScopeLogs{{
+			Scope:      otlpScope{Name: "github.com/dazzling420/go-logger"},
+			LogRecords: records,
+		}},
+	}}}
+}
+
+func otlpRecord(e batchEntry, now time.Time) otlpLogRecord {
+	severity, ok := otlpSeverities[e.Entry.Level]
+	if !ok {
+		severity = otlpSeverities[INFO]
+	}
+	record := otlpLogRecord{
+		TimeUnixNano:         strconv.FormatInt(e.Entry.Time.UnixNano(), 10),
+		ObservedTimeUnixNano: strconv.FormatInt(now.UnixNano(), 10),
+		SeverityNumber:       severity,
+		SeverityText:         LevelName(e.Entry.Level),
+		Body:                 otlpValue(e.Entry.Message),
+	}
+	if e.Entry.LoggerName != "" {
+		record.Attributes = append(record.Attributes, otlpKeyValue{"logger.name", otlpValue(e.Entry.LoggerName)})
+	}
+	if e.Entry.Caller.Defined {
+		record.Attributes = append(record.Attributes,
+			otlpKeyValue{"code.filepath", otlpValue(e.Entry.Caller.File)},
+			otlpKeyValue{"code.lineno", otlpValue(int64(e.Entry.Caller.Line))},
+		)
+	}
+
+	values := zapcore.NewMapObjectEncoder()
+	for _, f := range e.Fields {
+		f.AddTo(values)
+	}
+	for _, f := range e.Fields {
+		v, ok := values.Fields[f.Key]
+		if !ok {
+			continue
+		}
+		delete(values.Fields, f.Key) // a repeated key is only exported once
+		if id, ok := v.(string); ok {
+			switch {
+			case f.Key == "trace_id" && isHexID(id, 16):
+				record.TraceID = id
+				continue
+			case f.Key == "span_id" && isHexID(id, 8):
+				record.SpanID = id
+				continue
+			}
+		}
+		record.Attributes = append(record.Attributes, otlpKeyValue{f.Key, otlpValue(v)})
+	}
+	return record
+}
+
+// isHexID reports whether s is the hex encoding of an n byte id.
+func isHexID(s string, n int) bool {
+	b, err := hex.DecodeString(s)
+	return err == nil && len(b) == n
+}
+
+// otlpValue converts a value captured by a MapObjectEncoder to an OTLP
+// AnyValue.
+func otlpValue(v interface{}) otlpAnyValue {
+	switch v := v.(type) {
+	case string:
+		return otlpAnyValue{StringValue: &v}
+	case bool:
+		return otlpAnyValue{BoolValue: &v}
+	case int:
+		return otlpInt(int64(v))
+	case int64:
+		return otlpInt(v)
+	case int32:
+		return otlpInt(int64(v))
+	case int16:
+		return otlpInt(int64(v))
+	case int8:
+		return otlpInt(int64(v))
+	case uint:
+		return otlpInt(int64(v))
+	case uint64:
+		return otlpInt(int64(v))
+	case uint32:
+		return otlpInt(int64(v))
+	case uint16:
+		return otlpInt(int64(v))
+	case uint8:
+		return otlpInt(int64(v))
+	case float64:
+		return otlpAnyValue{DoubleValue: &v}
+	case float32:
+		f := float64(v)
+		return otlpAnyValue{DoubleValue: &f}
+	case []interface{}:
+		values := make([]otlpAnyValue, len(v))
+		for i, elem := range v {
+			values[i] = otlpValue(elem)
+		}
+		return otlpAnyValue{ArrayValue: &otlpArrayValue{Values: values}}
+	case map[string]interface{}:
+		values := make([]otlpKeyValue, 0, len(v))
+		for k, elem := range v {
+			values = append(values, otlpKeyValue{k, otlpValue(elem)})
+		}
+		return otlpAnyValue{KvlistValue: &otlpKvlist{Values: values}}
+	default:
+		return otlpValue(cellString(v))
+	}
+}
+
+func otlpInt(v int64) otlpAnyValue {
+	s := strconv.FormatInt(v, 10)
+	return otlpAnyValue{IntValue: &s}
+}
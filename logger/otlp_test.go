@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestOTLPCoreExportsRecords(t *testing.T) {
+	var mu sync.Mutex
+	var records []otlpLogRecord
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != otlpLogsPath || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("export to %s as %s", r.URL.Path, r.Header.Get("Content-Type"))
+		}
+		var export otlpExport
+		if err := json.NewDecoder(r.Body).Decode(&export); err != nil {
+			t.Error(err)
+		}
+		mu.Lock()
+		for _, rl := range export.ResourceLogs {
+			for _, sl := range rl.ScopeLogs {
+				records = append(records, sl.LogRecords...)
+			}
+		}
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	core := NewOTLPCore(srv.URL+"/", INFO)
+	log := zap.New(core, zap.AddCaller())
+	log.Debug("not exported")
+	log.Warn("slow",
+		zap.Int("attempt", 2),
+		zap.Bool("retried", true),
+		zap.Strings("hosts", []string{"a", "b"}),
+		zap.String("trace_id", "0102030405060708090a0b0c0d0e0f10"),
+		zap.String("span_id", "0102030405060708"),
+	)
+	log.Info("no trace", zap.String("trace_id", "not hex"))
+	if err := core.(io.Closer).Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(records) != 2 {
+		t.Fatalf("exported %d records, want 2", len(records))
+	}
+	r := records[0]
+	if *r.Body.StringValue != "slow" || r.SeverityNumber != 13 || r.SeverityText != "WARN" {
+		t.Errorf("record = %+v, want the WARN entry", r)
+	}
+	if r.TraceID != "0102030405060708090a0b0c0d0e0f10" || r.SpanID != "0102030405060708" {
+		t.Errorf("trace context = %s/%s", r.TraceID, r.SpanID)
+	}
+	attrs := map[string]otlpAnyValue{}
+	for _, kv := range r.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	if v := attrs["attempt"].IntValue; v == nil || *v != "2" {
+		t.Errorf("attempt = %+v, want the int 2", attrs["attempt"])
+	}
+	if v := attrs["retried"].BoolValue; v == nil || !*v {
+		t.Errorf("retried = %+v, want true", attrs["retried"])
+	}
+	if v := attrs["hosts"].ArrayValue; v == nil || len(v.Values) != 2 {
+		t.Errorf("hosts = %+v, want an array of 2", attrs["hosts"])
+	}
+	if attrs["code.filepath"].StringValue == nil || attrs["code.lineno"].IntValue == nil {
+		t.Errorf("attributes = %v, want the caller", attrs)
+	}
+	if _, ok := attrs["trace_id"]; ok {
+		t.Error("the trace id was also exported as an attribute")
+	}
+
+	if r := records[1]; r.TraceID != "" || len(r.Attributes) == 0 || r.Attributes[len(r.Attributes)-1].Key != "trace_id" {
+		t.Errorf("record = %+v, want an invalid trace_id kept as an attribute", r)
+	}
+}
@@ -0,0 +1,387 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// sinkSet opens output paths, handing out the same WriteSyncer when a path is
+// used more than once so a file is never rotated by two writers.
+type sinkSet struct {
+	conf       *config.Logger
+	sinks      map[string]zapcore.WriteSyncer
+	closers    []func() error
+	bufwriters []*bufwriter
+	fallbacks  []*fallbackSink
+	rotators   []rotator // the files that can be rotated on demand
+	errs       []error   // from preparing the files, see prepareLogFile
+
+	header       []byte // started every file with, nil unless written as CSV
+	streamHeader []byte // written to the standard streams first, likewise
+}
+
+// rotator is a file that can be rotated on demand.
+type rotator interface {
+	Rotate() error
+}
+
+func newSinkSet(conf *config.Logger) *sinkSet {
+	return &sinkSet{conf: conf, sinks: map[string]zapcore.WriteSyncer{}}
+}
+
+// open returns the sink for path, "stdout" and "stderr" being the standard
+// streams and anything else a rotating file, also rotated daily when
+// conf.RotateDaily is set, or a circular one when conf.CircularFileSize is
+// set. Writes failing on a file go to stderr when conf.FallbackToStderr is
+// set. Files are written from a background goroutine when conf.AsyncBuffer is
+// set, and buffered and flushed in the background when
+// conf.BackgroundFlushInterval is set.
+func (ss *sinkSet) open(path string) zapcore.WriteSyncer {
+	path = normalizePath(path)
+	if path == "stdout" || path == "stderr" {
+		return ss.openWithHeader(path, ss.streamHeader)
+	}
+	return ss.openWithHeader(path, ss.header)
+}
+
+// openWithHeader is open, path starting with header instead of the header of
+// the set, see headerSink. Circular files never have one.
+func (ss *sinkSet) openWithHeader(path string, header []byte) zapcore.WriteSyncer {
+	path = normalizePath(path)
+	if ws, ok := ss.sinks[path]; ok {
+		return ws
+	}
+	var ws zapcore.WriteSyncer
+	switch path {
+	case "stdout":
+		ws = zapcore.Lock(os.Stdout)
+	case "stderr":
+		ws = zapcore.Lock(os.Stderr)
+	default:
+		if err := prepareLogFile(path, ss.conf.LogFileMode); err != nil {
+			ss.errs = append(ss.errs, err)
+		}
+		if ss.conf.CircularFileSize > 0 {
+			circular := newCircularFile(path, ss.conf.CircularFileSize)
+			ss.closers = append(ss.closers, circular.Close)
+			ws = circular
+		} else {
+			var daily *dailyRotator
+			lj := newLumberjackSink(path, ss.conf)
+			var r rotator = lj.Logger
+			if ss.conf.RotateDaily {
+				daily = newDailyRotator(lj, systemClock{})
+				ss.closers = append(ss.closers, daily.Close)
+				ws = daily
+			} else {
+				ss.closers = append(ss.closers, lj.Close)
+				ws = lj
+			}
+			if header != nil {
+				hs := newHeaderSink(ws, header, path, int64(ss.conf.LogFileSizeCappingInMBs)*megabyte, lj.Rotate)
+				if daily != nil {
+					daily.notify(hs.markRotated)
+				}
+				ws, r = hs, hs
+			}
+			ss.rotators = append(ss.rotators, r)
+		}
+		if ss.conf.FallbackToStderr {
+			fallback := newFallbackSink(ws, zapcore.Lock(os.Stderr), fallbackRetryInterval)
+			ss.fallbacks = append(ss.fallbacks, fallback)
+			ws = fallback
+		}
+		if ss.conf.AsyncBuffer {
+			bw := newBufwriter(ss.conf.AsyncBufferSize, ws, ss.conf.AsyncBufferDropOnFull)
+			ss.closers = append(ss.closers, bw.Close)
+			ss.bufwriters = append(ss.bufwriters, bw)
+			ws = bw
+		}
+		if ss.conf.BackgroundFlushInterval > 0 {
+			buffered := &zapcore.BufferedWriteSyncer{
+				WS:            ws,
+				FlushInterval: ss.conf.BackgroundFlushInterval,
+			}
+			ss.closers = append(ss.closers, buffered.Stop)
+			ws = buffered
+		}
+	}
+	if header != nil && (path == "stdout" || path == "stderr") {
+		ws = newHeaderSink(ws, header, "", 0, nil)
+	}
+	ss.sinks[path] = ws
+	return ws
+}
+
+// dropped returns the number of entries dropped by the async buffers of the
+// set.
+func (ss *sinkSet) dropped() uint64 {
+	var n uint64
+	for _, bw := range ss.bufwriters {
+		n += bw.Dropped()
+	}
+	return n
+}
+
+// fallbackFailures returns the number of writes to the files of the set that
+// failed and went to stderr instead.
+func (ss *sinkSet) fallbackFailures() uint64 {
+	var n uint64
+	for _, f := range ss.fallbacks {
+		n += f.Failures()
+	}
+	return n
+}
+
+// prepareLogFile creates the missing directories of path and, when mode is
+// set, the file with that mode, in octal, or applies it to the existing file.
+// Rotation keeps the mode of the file it replaces.
+func prepareLogFile(path, mode string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if mode == "" {
+		return nil
+	}
+	perm, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil {
+		return fmt.Errorf("logger: invalid log file mode %q", mode)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, os.FileMode(perm))
+	if err != nil {
+		return err
+	}
+	f.Close()
+	// the umask may have narrowed the mode the file was created with
+	return os.Chmod(path, os.FileMode(perm))
+}
+
+// linkCurrentLog points the symlink at link to target, replacing the link it
+// finds there. Anything else found at link is left alone and reported.
+func linkCurrentLog(link, target string) error {
+	target, err := filepath.Abs(target)
+	if err != nil {
+		return err
+	}
+	if fi, err := os.Lstat(link); err == nil && fi.Mode()&os.ModeSymlink == 0 {
+		return fmt.Errorf("logger: current log symlink %s exists and is not a symlink", link)
+	}
+	// the link is replaced by a rename so tailers never find it missing
+	tmp := link + ".tmp"
+	os.Remove(tmp)
+	if err := os.Symlink(target, tmp); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, link); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// rotate rotates the files of the set, circular ones excepted.
+func (ss *sinkSet) rotate() error {
+	var errs []error
+	for _, r := range ss.rotators {
+		errs = append(errs, r.Rotate())
+	}
+	return errors.Join(errs...)
+}
+
+// normalizePath trims path and cleans it when it names a file, so the same
+// file is recognized however it is spelled.
+func normalizePath(path string) string {
+	path = strings.TrimSpace(path)
+	if path == "" || path == "stdout" || path == "stderr" {
+		return path
+	}
+	return filepath.Clean(path)
+}
+
+// openAll returns a WriteSyncer writing to every one of paths, ignoring empty
+// and repeated paths.
+func (ss *sinkSet) openAll(paths []string) zapcore.WriteSyncer {
+	seen := make(map[string]bool, len(paths))
+	var outputs []zapcore.WriteSyncer
+	for _, path := range paths {
+		path = normalizePath(path)
+		if path == "" || seen[path] {
+			continue
+		}
+		seen[path] = true
+		outputs = append(outputs, ss.open(path))
+	}
+	return zapcore.NewMultiWriteSyncer(outputs...)
+}
+
+// newLevelOutputsCore builds one core per entry of conf.LevelOutputs. Each
+// core receives the band of levels starting at its own level and ending below
+// the next configured level.
+func newLevelOutputsCore(encoder zapcore.Encoder, conf *config.Logger, sinks *sinkSet) zapcore.Core {
+	paths := map[zapcore.Level][]string{}
+	for name, outputs := range conf.LevelOutputs {
+		l := GetLevel(name)
+		paths[l] = append(paths[l], outputs...)
+	}
+
+	levels := make([]zapcore.Level, 0, len(paths))
+	for l := range paths {
+		levels = append(levels, l)
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+
+	cores := make([]zapcore.Core, 0, len(levels))
+	for i, low := range levels {
+		high := zapcore.InvalidLevel
+		if i+1 < len(levels) {
+			high = levels[i+1]
+		}
+		band := zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+			return l >= low && (high == zapcore.InvalidLevel || l < high)
+		})
+
+		cores = append(cores, zapcore.NewCore(encoder.Clone(), sinks.openAll(paths[low]), band))
+	}
+	return newTee(cores...)
+}
+
+// newStdStreamsCore writes the entries below stderrLevel to stdout, unless
+// withStdout is false, and the others to stderr, so collectors can tell them
+// apart by stream.
+func newStdStreamsCore(encoder zapcore.Encoder, stderrLevel zapcore.Level, withStdout bool, sinks *sinkSet) zapcore.Core {
+	stderr := zap.LevelEnablerFunc(func(l zapcore.Level) bool { return l >= stderrLevel })
+	stderrCore := zapcore.NewCore(encoder.Clone(), sinks.open("stderr"), stderr)
+	if !withStdout {
+		return stderrCore
+	}
+	stdout := zap.LevelEnablerFunc(func(l zapcore.Level) bool { return l < stderrLevel })
+	return newTee(zapcore.NewCore(encoder, sinks.open("stdout"), stdout), stderrCore)
+}
+
+// swappableSink is the LogFileName output, which Reconfigure can replace or
+// remove while entries are being written. It writes nothing while it has no
+// file.
+type swappableSink struct {
+	header []byte // see sinkSet
+
+	mu      sync.RWMutex
+	ws      zapcore.WriteSyncer
+	sinks   *sinkSet
+	closers []func() error
+}
+
+func newSwappableSink(conf *config.Logger, header []byte) (*swappableSink, error) {
+	s := &swappableSink{header: header}
+	return s, s.swap(conf)
+}
+
+// swap closes the current file, once everything written to it is flushed, and
+// opens conf.LogFileName with the rotation settings of conf instead. Errors
+// preparing the new file are returned too, it is still written to.
+func (s *swappableSink) swap(conf *config.Logger) error {
+	var ws zapcore.WriteSyncer
+	var ss *sinkSet
+	if path := normalizePath(conf.LogFileName); path != "" {
+		ss = newSinkSet(conf)
+		ss.header = s.header
+		ws = ss.open(path)
+		if conf.CurrentLogSymlink != "" {
+			if err := linkCurrentLog(conf.CurrentLogSymlink, path); err != nil {
+				ss.errs = append(ss.errs, err)
+			}
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.close()
+	s.ws, s.sinks = ws, ss
+	if ss != nil {
+		s.closers = ss.closers
+		err = errors.Join(append([]error{err}, ss.errs...)...)
+	}
+	return err
+}
+
+// close must be called with s.mu held.
+func (s *swappableSink) close() error {
+	var errs []error
+	for i := len(s.closers) - 1; i >= 0; i-- {
+		errs = append(errs, s.closers[i]())
+	}
+	s.ws, s.sinks, s.closers = nil, nil, nil
+	return errors.Join(errs...)
+}
+
+// rotate rotates the current file.
+func (s *swappableSink) rotate() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.sinks == nil {
+		return nil
+	}
+	return s.sinks.rotate()
+}
+
+// rotatable reports whether there is a current file that can be rotated.
+func (s *swappableSink) rotatable() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sinks != nil && len(s.sinks.rotators) > 0
+}
+
+// dropped returns the number of entries dropped by the async buffer of the
+// current file.
+func (s *swappableSink) dropped() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.sinks == nil {
+		return 0
+	}
+	return s.sinks.dropped()
+}
+
+// fallbackFailures returns the number of writes to the current file that
+// failed and went to stderr instead.
+func (s *swappableSink) fallbackFailures() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.sinks == nil {
+		return 0
+	}
+	return s.sinks.fallbackFailures()
+}
+
+func (s *swappableSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.close()
+}
+
+func (s *swappableSink) Write(p []byte) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.ws == nil {
+		return len(p), nil
+	}
+	return s.ws.Write(p)
+}
+
+func (s *swappableSink) Sync() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.ws == nil {
+		return nil
+	}
+	return s.ws.Sync()
+}
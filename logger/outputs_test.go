@@ -0,0 +1,392 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dazzling420/go-logger/config"
+)
+
+func newBufferedService(t *testing.T, path string, interval time.Duration) *standardLogger {
+	t.Helper()
+	svc, err := BuildService(config.Logger{LogFileName: path, DisableStdout: true, BackgroundFlushInterval: interval})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return svc
+}
+
+func TestBackgroundFlushIntervalBuffersUntilTheInterval(t *testing.T) {
+	path := tempLog(t, "app.log")
+	svc := newBufferedService(t, path, 200*time.Millisecond)
+	defer svc.Close()
+
+	svc.Infoz("buffered")
+	if got := readEntries(t, path); len(got) != 0 {
+		t.Fatalf("entries written before the interval: %v", got)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(readEntries(t, path)) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("the entry was not flushed after the interval")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if got := messages(readEntries(t, path)); len(got) != 1 || got[0] != "buffered" {
+		t.Errorf("messages = %q, want [buffered]", got)
+	}
+}
+
+func TestBackgroundFlushIntervalSyncFlushes(t *testing.T) {
+	path := tempLog(t, "app.log")
+	svc := newBufferedService(t, path, time.Hour)
+	defer svc.Close()
+
+	svc.Infoz("synced")
+	if err := svc.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if got := messages(readEntries(t, path)); len(got) != 1 || got[0] != "synced" {
+		t.Errorf("messages after Sync = %q, want [synced]", got)
+	}
+}
+
+func TestBackgroundFlushIntervalCloseFlushes(t *testing.T) {
+	path := tempLog(t, "app.log")
+	svc := newBufferedService(t, path, time.Hour)
+
+	svc.Infoz("closed")
+	if err := svc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := messages(readEntries(t, path)); len(got) != 1 || got[0] != "closed" {
+		t.Errorf("messages after Close = %q, want [closed]", got)
+	}
+}
+
+func TestLevelOutputs(t *testing.T) {
+	dir := t.TempDir()
+	debug, warn, both := filepath.Join(dir, "debug.log"), filepath.Join(dir, "warn.log"), filepath.Join(dir, "all.log")
+	svc, err := BuildService(config.Logger{
+		LoggingLevel: "DEBUG",
+		LevelOutputs: map[string][]string{
+			"DEBUG": {debug, both},
+			"WARN":  {warn, both},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.Debugz("debug")
+	svc.Infoz("info")
+	svc.Warnz("warn")
+	svc.Errorz("error")
+	svc.Close()
+
+	for path, want := range map[string]string{
+		debug: "debug,info",
+		warn:  "warn,error",
+		both:  "debug,info,warn,error",
+	} {
+		if got := strings.Join(messages(readEntries(t, path)), ","); got != want {
+			t.Errorf("%s = %s, want %s", filepath.Base(path), got, want)
+		}
+	}
+}
+
+func TestLevelOutputsFollowTheLevel(t *testing.T) {
+	path := tempLog(t, "debug.log")
+	svc, err := BuildService(config.Logger{LoggingLevel: "INFO", LevelOutputs: map[string][]string{"DEBUG": {path}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.Debugz("below the level")
+	svc.Infoz("info")
+	svc.Close()
+
+	if got := strings.Join(messages(readEntries(t, path)), ","); got != "info" {
+		t.Errorf("debug.log = %s, want info alone", got)
+	}
+}
+
+func TestBackgroundFlushIntervalAppliesToEveryFile(t *testing.T) {
+	dir := t.TempDir()
+	path, errPath := filepath.Join(dir, "app.log"), filepath.Join(dir, "error.log")
+	svc, err := BuildService(config.Logger{
+		LogFileName:             path,
+		ErrorLogFileName:        errPath,
+		DisableStdout:           true,
+		BackgroundFlushInterval: time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer svc.Close()
+
+	svc.Errorz("failed")
+	for _, p := range []string{path, errPath} {
+		if got := readEntries(t, p); len(got) != 0 {
+			t.Errorf("%s written before a flush: %v", filepath.Base(p), got)
+		}
+	}
+	svc.Sync()
+	for _, p := range []string{path, errPath} {
+		if got := messages(readEntries(t, p)); len(got) != 1 || got[0] != "failed" {
+			t.Errorf("%s = %q after Sync, want [failed]", filepath.Base(p), got)
+		}
+	}
+}
+
+func TestAdditionalLogFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	extra := filepath.Join(dir, "extra.log")
+	svc, err := BuildService(config.Logger{
+		LogFileName: path,
+		// repeated, and naming the main file again, each written once
+		AdditionalLogFiles: []string{extra, " " + extra, "", path},
+		DisableStdout:      true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.Infoz("fanned out")
+	svc.Close()
+
+	for _, p := range []string{path, extra} {
+		if got := messages(readEntries(t, p)); len(got) != 1 || got[0] != "fanned out" {
+			t.Errorf("%s = %q, want the entry once", filepath.Base(p), got)
+		}
+	}
+}
+
+func TestAdditionalLogFilesWithStdout(t *testing.T) {
+	extra := tempLog(t, "extra.log")
+	out := captureStdout(t, func() {
+		svc, err := BuildService(config.Logger{AdditionalLogFiles: []string{extra, "stdout"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		svc.Infoz("fanned out")
+		svc.Close()
+	})
+	if strings.Count(out, "fanned out") != 1 {
+		t.Errorf("stdout = %q, want the entry once", out)
+	}
+	if got := messages(readEntries(t, extra)); len(got) != 1 {
+		t.Errorf("extra.log = %q, want the entry", got)
+	}
+}
+
+func TestAsyncBufferDropOnFullCountsEveryFile(t *testing.T) {
+	dir := t.TempDir()
+	conf := config.Logger{
+		LogFileName:           filepath.Join(dir, "app.log"),
+		ErrorLogFileName:      filepath.Join(dir, "error.log"),
+		DisableStdout:         true,
+		AsyncBuffer:           true,
+		AsyncBufferSize:       1024,
+		AsyncBufferDropOnFull: true,
+	}.WithDefaults()
+	svc, err := BuildService(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		svc.Errorz("queued")
+	}
+	svc.Sync()
+	if n := svc.sinks.dropped() + svc.logFile.dropped(); n != 0 {
+		t.Errorf("dropped %d entries with room in the buffers", n)
+	}
+	if got := len(svc.sinks.bufwriters) + len(svc.logFile.sinks.bufwriters); got != 2 {
+		t.Errorf("%d async buffers, want one per file", got)
+	}
+	svc.Close()
+
+	for _, name := range []string{"app.log", "error.log"} {
+		if got := readEntries(t, filepath.Join(dir, name)); len(got) != 100 {
+			t.Errorf("%s has %d entries, want 100", name, len(got))
+		}
+	}
+}
+
+func TestSinkSetDroppedSumsTheBuffers(t *testing.T) {
+	ss := &sinkSet{}
+	for i := 0; i < 2; i++ {
+		out := newBlockingSink()
+		bw := newBufwriter(1, out, true)
+		bw.Write([]byte("taken"))
+		<-out.started
+		bw.Write([]byte("queued"))
+		bw.Write([]byte("dropped"))
+		ss.bufwriters = append(ss.bufwriters, bw)
+		defer bw.Close()
+		defer close(out.release)
+	}
+	if got := ss.dropped(); got != 2 {
+		t.Errorf("dropped() = %d, want 2", got)
+	}
+}
+
+func TestErrorsToStderr(t *testing.T) {
+	path := tempLog(t, "app.log")
+	var stdout string
+	stderr := captureStderr(t, func() {
+		stdout = captureStdout(t, func() {
+			svc, err := BuildService(config.Logger{LogFileName: path, ErrorsToStderr: true, StderrLevel: "ERROR"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			svc.Infoz("info")
+			svc.Warnz("warn")
+			svc.Errorz("error")
+			svc.Close()
+		})
+	})
+
+	if !strings.Contains(stdout, "info") || !strings.Contains(stdout, "warn") || strings.Contains(stdout, `"error"`) {
+		t.Errorf("stdout = %q, want the entries below ERROR", stdout)
+	}
+	if strings.Count(stderr, "\n") != 1 || !strings.Contains(stderr, `"message":"error"`) {
+		t.Errorf("stderr = %q, want the ERROR entry alone", stderr)
+	}
+	if got := strings.Join(messages(readEntries(t, path)), ","); got != "info,warn,error" {
+		t.Errorf("app.log = %s, want every entry", got)
+	}
+}
+
+func TestRotate(t *testing.T) {
+	dir := t.TempDir()
+	svc, err := BuildService(config.Logger{
+		LogFileName:      filepath.Join(dir, "app.log"),
+		ErrorLogFileName: filepath.Join(dir, "error.log"),
+		DisableStdout:    true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.Errorz("before")
+	if err := svc.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	svc.Errorz("after")
+	svc.Close()
+
+	for _, prefix := range []string{"app", "error"} {
+		files, _ := filepath.Glob(filepath.Join(dir, prefix+"*.log"))
+		if len(files) != 2 {
+			t.Errorf("%s files = %q, want the file and one backup", prefix, files)
+		}
+		if got := messages(readEntries(t, filepath.Join(dir, prefix+".log"))); len(got) != 1 || got[0] != "after" {
+			t.Errorf("%s.log = %q, want [after]", prefix, got)
+		}
+	}
+}
+
+func TestLogDirectoriesAreCreated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "dirs", "app.log")
+	svc, err := BuildService(config.Logger{LogFileName: path, ErrorLogFileName: filepath.Join(filepath.Dir(path), "errors", "error.log"), DisableStdout: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.Errorz("written")
+	svc.Close()
+
+	for _, p := range []string{path, filepath.Join(filepath.Dir(path), "errors", "error.log")} {
+		if got := messages(readEntries(t, p)); len(got) != 1 {
+			t.Errorf("%s = %q, want the entry", filepath.Base(p), got)
+		}
+	}
+}
+
+func TestLogFileMode(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing.log")
+	if err := os.WriteFile(existing, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	created := filepath.Join(dir, "created.log")
+	svc, err := BuildService(config.Logger{
+		LogFileName:        created,
+		AdditionalLogFiles: []string{existing},
+		LogFileMode:        "0600",
+		DisableStdout:      true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.Infoz("written")
+	svc.Close()
+
+	for _, p := range []string{created, existing} {
+		info, err := os.Stat(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mode := info.Mode().Perm(); mode != 0o600 {
+			t.Errorf("%s has mode %o, want 600", filepath.Base(p), mode)
+		}
+	}
+}
+
+func TestLogFileDirectoryError(t *testing.T) {
+	dir := t.TempDir()
+	blocker := filepath.Join(dir, "logs")
+	if err := os.WriteFile(blocker, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	svc, err := BuildService(config.Logger{LogFileName: filepath.Join(blocker, "app.log"), DisableStdout: true})
+	if err == nil {
+		svc.Close()
+		t.Fatal("BuildService() succeeded with a file in place of the log directory")
+	}
+	if svc != nil {
+		t.Error("BuildService() returned a logger along with its error")
+	}
+}
+
+func TestCurrentLogSymlink(t *testing.T) {
+	dir := t.TempDir()
+	link := filepath.Join(dir, "current.log")
+	conf := config.Logger{LogFileName: filepath.Join(dir, "app-1.log"), CurrentLogSymlink: link, DisableStdout: true}
+	svc, err := BuildService(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer svc.Close()
+	svc.Infoz("first")
+	if got := messages(readEntries(t, link)); len(got) != 1 || got[0] != "first" {
+		t.Errorf("read %q through the link, want [first]", got)
+	}
+
+	conf.LogFileName = filepath.Join(dir, "app-2.log")
+	if err := svc.Reconfigure(conf); err != nil {
+		t.Fatal(err)
+	}
+	svc.Infoz("second")
+	if target, _ := os.Readlink(link); target != conf.LogFileName {
+		t.Errorf("link points at %s, want %s", target, conf.LogFileName)
+	}
+	if got := messages(readEntries(t, link)); len(got) != 1 || got[0] != "second" {
+		t.Errorf("read %q through the link, want [second]", got)
+	}
+}
+
+func TestCurrentLogSymlinkLeavesAFileAlone(t *testing.T) {
+	dir := t.TempDir()
+	link := filepath.Join(dir, "current.log")
+	if err := os.WriteFile(link, []byte("keep me\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := BuildService(config.Logger{LogFileName: filepath.Join(dir, "app.log"), CurrentLogSymlink: link, DisableStdout: true}); err == nil {
+		t.Error("BuildService() succeeded with a file in place of the link")
+	}
+
+	if got := readFile(t, link); got != "keep me\n" {
+		t.Errorf("current.log = %q, want it untouched", got)
+	}
+}
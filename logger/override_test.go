@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// observedMessages returns the messages of the entries logs recorded.
+func observedMessages(logs *observer.ObservedLogs) []string {
+	var got []string
+	for _, e := range logs.All() {
+		got = append(got, e.Message)
+	}
+	return got
+}
+
+func TestFieldLevelOverride(t *testing.T) {
+	svc, logs := NewTestLogger()
+	s := svc.(*standardLogger)
+	s.SetLevel("INFO")
+	s.SetFieldLevelOverride("tenant_id", "acme", DEBUG)
+
+	s.Debugz("acme debug", zap.String("tenant_id", "acme"))
+	s.Debugz("other debug", zap.String("tenant_id", "globex"))
+	s.Debugz("no tenant")
+	s.Tracez("acme trace", zap.String("tenant_id", "acme"))
+	s.With(zap.String("tenant_id", "acme")).Debugz("bound acme debug")
+	s.With(zap.String("tenant_id", "globex")).Debugz("bound other debug")
+	s.Infoz("info")
+
+	if got, want := strings.Join(observedMessages(logs), ","), "acme debug,bound acme debug,info"; got != want {
+		t.Errorf("logged %s, want %s", got, want)
+	}
+}
+
+func TestFieldLevelOverrideMatchesStringsOnly(t *testing.T) {
+	svc, logs := NewTestLogger()
+	s := svc.(*standardLogger)
+	s.SetLevel("INFO")
+	s.SetFieldLevelOverride("tenant_id", "42", DEBUG)
+
+	s.Debugz("number", zap.Int("tenant_id", 42))
+	s.Debugz("string", zap.String("tenant_id", "42"))
+
+	if got := observedMessages(logs); len(got) != 1 || got[0] != "string" {
+		t.Errorf("logged %q, want only the string field to match", got)
+	}
+}
+
+func TestClearFieldLevelOverride(t *testing.T) {
+	svc, logs := NewTestLogger()
+	s := svc.(*standardLogger)
+	s.SetLevel("INFO")
+	s.SetFieldLevelOverride("tenant_id", "acme", DEBUG)
+	s.SetFieldLevelOverride("tenant_id", "globex", DEBUG)
+	s.ClearFieldLevelOverride("tenant_id", "acme")
+
+	s.Debugz("acme", zap.String("tenant_id", "acme"))
+	s.Debugz("globex", zap.String("tenant_id", "globex"))
+	s.ClearFieldLevelOverride("tenant_id", "globex")
+	s.Debugz("globex cleared", zap.String("tenant_id", "globex"))
+
+	if got := observedMessages(logs); len(got) != 1 || got[0] != "globex" {
+		t.Errorf("logged %q, want only globex before it was cleared", got)
+	}
+	if s.overrides.enabled(DEBUG) {
+		t.Error("overrides still enabled once all were cleared")
+	}
+}
+
+func TestFieldLevelOverrideSharedWithClones(t *testing.T) {
+	s, path := newFileService(t, config.Logger{})
+	s.SetLevel("INFO")
+	child := s.With(zap.String("component", "billing"))
+	s.SetFieldLevelOverride("tenant_id", "acme", DEBUG)
+
+	child.Debugz("acme debug", zap.String("tenant_id", "acme"))
+	child.Debugz("other debug", zap.String("tenant_id", "globex"))
+	s.Sync()
+
+	if got, want := strings.Join(messages(readEntries(t, path)), ","), "acme debug"; got != want {
+		t.Errorf("logged %s, want %s", got, want)
+	}
+}
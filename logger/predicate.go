@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithPredicate returns an option, for NewService or zap loggers, that drops
+// every entry for which pred returns false. pred receives the entry along
+// with its fields, including those bound with With, and is called for every
+// entry that passes the level check so it should be cheap.
+func WithPredicate(pred func(zapcore.Entry, []zapcore.Field) bool) zap.Option {
+	return zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &predicateCore{Core: core, pred: pred}
+	})
+}
+
+type predicateCore struct {
+	zapcore.Core
+	pred    func(zapcore.Entry, []zapcore.Field) bool
+	context []zapcore.Field
+}
+
+func (c *predicateCore) With(fields []zapcore.Field) zapcore.Core {
+	context := make([]zapcore.Field, 0, len(c.context)+len(fields))
+	context = append(append(context, c.context...), fields...)
+	return &predicateCore{Core: c.Core.With(fields), pred: c.pred, context: context}
+}
+
+func (c *predicateCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *predicateCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	all := fields
+	if len(c.context) > 0 {
+		all = make([]zapcore.Field, 0, len(c.context)+len(fields))
+		all = append(append(all, c.context...), fields...)
+	}
+	if !c.pred(ent, all) {
+		return nil
+	}
+	return c.Core.Write(ent, fields)
+}
@@ -0,0 +1,71 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// dropHealthChecks drops the entries with a path field of /healthz.
+func dropHealthChecks(_ zapcore.Entry, fields []zapcore.Field) bool {
+	for _, f := range fields {
+		if f.Key == "path" && f.String == "/healthz" {
+			return false
+		}
+	}
+	return true
+}
+
+func TestWithPredicate(t *testing.T) {
+	path := tempLog(t, "app.log")
+	svc, err := BuildService(config.Logger{LogFileName: path, DisableStdout: true}, WithPredicate(dropHealthChecks))
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.Infoz("request", zap.String("path", "/healthz"))
+	svc.Infoz("request", zap.String("path", "/orders"))
+	svc.With(zap.String("path", "/healthz")).Infoz("bound request")
+	svc.Infoz("no path")
+	svc.Close()
+
+	if got := strings.Join(messages(readEntries(t, path)), ","); got != "request,no path" {
+		t.Errorf("messages = %s, want request,no path", got)
+	}
+}
+
+func TestWithPredicateSeesTheEntry(t *testing.T) {
+	path := tempLog(t, "app.log")
+	svc, err := BuildService(config.Logger{LogFileName: path, DisableStdout: true},
+		WithPredicate(func(e zapcore.Entry, _ []zapcore.Field) bool {
+			return e.Level >= zapcore.WarnLevel || !strings.HasPrefix(e.Message, "noisy")
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.Infoz("noisy info")
+	svc.Warnz("noisy warning")
+	svc.Infoz("quiet info")
+	svc.Close()
+
+	if got := strings.Join(messages(readEntries(t, path)), ","); got != "noisy warning,quiet info" {
+		t.Errorf("messages = %s, want noisy warning,quiet info", got)
+	}
+}
+
+func TestNewServiceAppliesZapOptions(t *testing.T) {
+	path := tempLog(t, "app.log")
+	svc, err := BuildService(config.Logger{LogFileName: path, DisableStdout: true}, zap.Fields(zap.String("service", "api")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.Infoz("started")
+	svc.Close()
+
+	entries := readEntries(t, path)
+	if len(entries) != 1 || entries[0]["service"] != "api" {
+		t.Errorf("entries = %v, want service=api", entries)
+	}
+}
@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+var prettyPool = buffer.NewPool()
+
+// prettyEncoder indents the JSON entries of its encoder by two spaces, one
+// key per line, for reading logs during development. Each entry still ends
+// with lineEnding and parses on its own, but line oriented tools no longer see
+// one entry per line.
+type prettyEncoder struct {
+	zapcore.Encoder
+	lineEnding string
+}
+
+func (e prettyEncoder) Clone() zapcore.Encoder {
+	return prettyEncoder{e.Encoder.Clone(), e.lineEnding}
+}
+
+func (e prettyEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	line, err := e.Encoder.EncodeEntry(ent, fields)
+	if err != nil {
+		return nil, err
+	}
+	defer line.Free()
+
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, bytes.TrimRight(line.Bytes(), "\r\n"), "", "  "); err != nil {
+		return nil, err
+	}
+	buf := prettyPool.Get()
+	buf.Write(indented.Bytes())
+	buf.AppendString(e.lineEnding)
+	return buf, nil
+}
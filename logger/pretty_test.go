@@ -0,0 +1,37 @@
+package logger
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap"
+)
+
+func TestPrettyPrint(t *testing.T) {
+	svc, path := newFileService(t, config.Logger{PrettyPrint: true})
+	svc.Infoz("first", zap.Int("n", 1))
+	svc.Infoz("second")
+	svc.Sync()
+
+	got := readFile(t, path)
+	if !strings.HasPrefix(got, "{\n  \"level\": \"INFO\",\n") || !strings.HasSuffix(got, "}\n") {
+		t.Errorf("file = %q, want indented entries each ending with a newline", got)
+	}
+	dec := json.NewDecoder(strings.NewReader(got))
+	var msgs []string
+	for {
+		var e map[string]interface{}
+		if err := dec.Decode(&e); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		msgs = append(msgs, e["message"].(string))
+	}
+	if strings.Join(msgs, ",") != "first,second" {
+		t.Errorf("decoded %q, want both entries", msgs)
+	}
+}
@@ -0,0 +1,36 @@
+package logger
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Progress logs the progress of a long running operation op at INFO with the
+// done and total counts, the percent complete and an eta estimated from the
+// time elapsed since the first Progress call for op. Tracking for op is reset
+// once done reaches total.
+func (s *standardLogger) Progress(op string, done, total int64, fields ...Field) {
+	now := time.Now()
+	started, _ := s.progress.LoadOrStore(op, now)
+	elapsed := now.Sub(started.(time.Time))
+
+	progressFields := []Field{
+		zap.String("op", op),
+		zap.Int64("done", done),
+		zap.Int64("total", total),
+	}
+	if total > 0 {
+		progressFields = append(progressFields, zap.Float64("percent", float64(done)*100/float64(total)))
+	}
+	switch {
+	case total > 0 && done >= total:
+		progressFields = append(progressFields, zap.Duration("eta", 0))
+		s.progress.Delete(op)
+	case done > 0:
+		eta := time.Duration(float64(elapsed) / float64(done) * float64(total-done))
+		progressFields = append(progressFields, zap.Duration("eta", eta))
+	}
+
+	s.log.Info("progress", append(progressFields, fields...)...)
+}
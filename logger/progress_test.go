@@ -0,0 +1,57 @@
+package logger
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProgress(t *testing.T) {
+	svc, logs := NewTestLogger()
+	log := svc.(*standardLogger)
+
+	log.Progress("import", 0, 4)
+	time.Sleep(20 * time.Millisecond)
+	log.Progress("import", 1, 4, String("file", "a.csv"))
+	log.Progress("import", 4, 4)
+	log.Progress("import", 0, 0)
+
+	entries := logs.AllUntimed()
+	if len(entries) != 4 {
+		t.Fatalf("logged %d entries, want 4", len(entries))
+	}
+	for _, e := range entries {
+		if e.Message != "progress" || e.Level != INFO || e.ContextMap()["op"] != "import" {
+			t.Errorf("entry = %s %q %v, want INFO progress for op import", LevelName(e.Level), e.Message, e.ContextMap())
+		}
+	}
+
+	start := entries[0].ContextMap()
+	if start["done"] != int64(0) || start["total"] != int64(4) || start["percent"] != float64(0) {
+		t.Errorf("start = %v", start)
+	}
+	if _, ok := start["eta"]; ok {
+		t.Errorf("start = %v, want no eta before anything is done", start)
+	}
+
+	quarter := entries[1].ContextMap()
+	if quarter["percent"] != float64(25) || quarter["file"] != "a.csv" {
+		t.Errorf("a quarter done = %v", quarter)
+	}
+	// three times the 20ms the first quarter took
+	if eta, _ := quarter["eta"].(time.Duration); eta < 60*time.Millisecond {
+		t.Errorf("eta = %v, want at least 60ms", quarter["eta"])
+	}
+
+	if done := entries[2].ContextMap(); done["percent"] != float64(100) || done["eta"] != time.Duration(0) {
+		t.Errorf("done = %v, want 100 percent and no eta left", done)
+	}
+
+	// completion resets the tracking, and an unknown total has no percent
+	restarted := entries[3].ContextMap()
+	if _, ok := restarted["percent"]; ok {
+		t.Errorf("unknown total = %v, want no percent", restarted)
+	}
+	if _, ok := log.progress.Load("import"); !ok {
+		t.Error("the restarted op is not tracked")
+	}
+}
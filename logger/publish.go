@@ -0,0 +1,18 @@
+package logger
+
+import "go.uber.org/zap"
+
+// Publish logs the outcome of publishing a message of size bytes to topic
+// under key: at INFO when err is nil, at ERROR with the error otherwise.
+func (s *standardLogger) Publish(topic, key string, size int, err error) {
+	fields := []Field{
+		zap.String("topic", topic),
+		zap.String("key", key),
+		zap.Int("size", size),
+	}
+	if isNilError(err) {
+		s.log.Info("message published", append(fields, zap.String("result", "success"))...)
+		return
+	}
+	s.log.Error("message publish failed", append(fields, zap.String("result", "failure"), zap.Error(err))...)
+}
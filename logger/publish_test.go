@@ -0,0 +1,36 @@
+package logger
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPublish(t *testing.T) {
+	svc, logs := NewTestLogger()
+	log := svc.(*standardLogger)
+
+	log.Publish("orders", "42", 128, nil)
+	log.Publish("orders", "43", 64, errors.New("broker down"))
+
+	entries := logs.AllUntimed()
+	if len(entries) != 2 {
+		t.Fatalf("logged %d entries, want 2", len(entries))
+	}
+
+	ok := entries[0]
+	fields := ok.ContextMap()
+	if ok.Level != INFO || ok.Message != "message published" || fields["topic"] != "orders" ||
+		fields["key"] != "42" || fields["size"] != int64(128) || fields["result"] != "success" {
+		t.Errorf("success = %s %q %v", LevelName(ok.Level), ok.Message, fields)
+	}
+	if _, has := fields["error"]; has {
+		t.Errorf("success = %v, want no error", fields)
+	}
+
+	failed := entries[1]
+	fields = failed.ContextMap()
+	if failed.Level != ERROR || failed.Message != "message publish failed" || fields["key"] != "43" ||
+		fields["result"] != "failure" || fields["error"] != "broker down" {
+		t.Errorf("failure = %s %q %v", LevelName(failed.Level), failed.Message, fields)
+	}
+}
@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// QueryLoggerOption configures QueryLogger.
+type QueryLoggerOption func(*queryLogger)
+
+// WithQueryArgValues logs the values of query arguments rather than their
+// types. They may hold personal data, only use it where the logs may too.
+func WithQueryArgValues() QueryLoggerOption {
+	return func(q *queryLogger) {
+		q.argValues = true
+	}
+}
+
+// QueryFunc runs a query through run and logs it, returning the error of run.
+type QueryFunc func(query string, args []interface{}, run func() error) error
+
+type queryLogger struct {
+	argValues bool
+}
+
+// QueryLogger returns a function to call around database/sql queries, logging
+// one line per query with its statement, arguments, duration and error:
+//
+//	logQuery := logger.QueryLogger(svc)
+//	err := logQuery(query, args, func() error {
+//		_, err := db.ExecContext(ctx, query, args...)
+//		return err
+//	})
+//
+// Queries are logged at DEBUG, or ERROR when they fail; sql.ErrNoRows is not
+// a failure. Argument values are redacted, each replaced by its type, e.g.
+// "<string>", unless WithQueryArgValues is given.
+func QueryLogger(svc Service, opts ...QueryLoggerOption) QueryFunc {
+	q := &queryLogger{}
+	for _, opt := range opts {
+		opt(q)
+	}
+	log := svc.GetZapLogger().WithOptions(zap.AddCallerSkip(1))
+
+	return func(query string, args []interface{}, run func() error) error {
+		start := time.Now()
+		err := run()
+		elapsed := time.Since(start)
+
+		level := DEBUG
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			level = ERROR
+		}
+		if ce := log.Check(level, "sql query"); ce != nil {
+			fields := []Field{
+				zap.String("sql", query),
+				q.args(args),
+				zap.Duration("duration", elapsed),
+			}
+			if err != nil {
+				fields = append(fields, zap.Error(err))
+			}
+			ce.Write(fields...)
+		}
+		return err
+	}
+}
+
+func (q *queryLogger) args(args []interface{}) Field {
+	if q.argValues {
+		return zap.Any("args", args)
+	}
+	redacted := make([]string, len(args))
+	for i, arg := range args {
+		if named, ok := arg.(sql.NamedArg); ok {
+			redacted[i] = fmt.Sprintf("@%s=<%T>", named.Name, named.Value)
+			continue
+		}
+		redacted[i] = fmt.Sprintf("<%T>", arg)
+	}
+	return zap.Strings("args", redacted)
+}
@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQueryLogger(t *testing.T) {
+	svc, logs := NewTestLogger()
+	logQuery := QueryLogger(svc)
+
+	err := logQuery("SELECT * FROM users WHERE email = ? AND age > ?", []interface{}{"bob@example.com", 42}, func() error {
+		time.Sleep(time.Millisecond)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	failure := errors.New("relation \"users\" does not exist")
+	err = logQuery("DELETE FROM users WHERE id = @id", []interface{}{sql.Named("id", 7)}, func() error { return failure })
+	if err != failure {
+		t.Errorf("QueryFunc() = %v, want the error of the query", err)
+	}
+
+	entries := logs.AllUntimed()
+	if len(entries) != 2 {
+		t.Fatalf("logged %d entries, want 2", len(entries))
+	}
+	ok, failed := entries[0], entries[1]
+	if ok.Level != DEBUG || ok.Message != "sql query" || failed.Level != ERROR {
+		t.Errorf("levels = %s and %s, want DEBUG and ERROR", LevelName(ok.Level), LevelName(failed.Level))
+	}
+	fields := ok.ContextMap()
+	if fields["sql"] != "SELECT * FROM users WHERE email = ? AND age > ?" {
+		t.Errorf("sql = %v", fields["sql"])
+	}
+	if want := []interface{}{"<string>", "<int>"}; !reflect.DeepEqual(fields["args"], want) {
+		t.Errorf("args = %v, want %v", fields["args"], want)
+	}
+	if d, _ := fields["duration"].(time.Duration); d < time.Millisecond {
+		t.Errorf("duration = %v, want the time the query took", fields["duration"])
+	}
+	if _, ok := fields["error"]; ok {
+		t.Error("error added for a successful query")
+	}
+	fields = failed.ContextMap()
+	if want := []interface{}{"@id=<int>"}; !reflect.DeepEqual(fields["args"], want) {
+		t.Errorf("args = %v, want %v", fields["args"], want)
+	}
+	if fields["error"] != failure.Error() {
+		t.Errorf("error = %v, want the error of the query", fields["error"])
+	}
+	for i, e := range entries {
+		if !strings.HasSuffix(e.Caller.File, "query_test.go") {
+			t.Errorf("entry %d caller = %s, want this file", i, e.Caller.File)
+		}
+	}
+}
+
+func TestQueryLoggerNoRowsIsNotAFailure(t *testing.T) {
+	svc, logs := NewTestLogger()
+	err := QueryLogger(svc)("SELECT 1", nil, func() error { return sql.ErrNoRows })
+	if err != sql.ErrNoRows {
+		t.Errorf("QueryFunc() = %v, want sql.ErrNoRows", err)
+	}
+	if e := logs.All()[0]; e.Level != DEBUG || e.ContextMap()["error"] != sql.ErrNoRows.Error() {
+		t.Errorf("entry = %s %v, want DEBUG with the error", LevelName(e.Level), e.ContextMap())
+	}
+}
+
+func TestQueryLoggerWithArgValues(t *testing.T) {
+	svc, logs := NewTestLogger()
+	QueryLogger(svc, WithQueryArgValues())("SELECT ?", []interface{}{"bob@example.com"}, func() error { return nil })
+	if got := logs.All()[0].ContextMap()["args"]; !reflect.DeepEqual(got, []interface{}{"bob@example.com"}) {
+		t.Errorf("args = %v, want the values", got)
+	}
+}
+
+func TestQueryLoggerSkipsDisabledLevels(t *testing.T) {
+	svc, logs := NewTestLogger()
+	svc.(*standardLogger).SetLevel("INFO")
+	ran := false
+	QueryLogger(svc)("SELECT 1", nil, func() error {
+		ran = true
+		return nil
+	})
+	if !ran || logs.Len() != 0 {
+		t.Errorf("ran = %v with %d entries, want the query run and nothing logged at INFO", ran, logs.Len())
+	}
+}
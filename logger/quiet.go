@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"time"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithQuietHours returns an option, for NewService or zap loggers, that drops
+// the entries below the level of a window of hours while local time is in it,
+// see config.QuietHours. ERROR and above always pass. Invalid windows are
+// ignored, config.Logger.Validate reports them.
+func WithQuietHours(hours ...config.QuietHours) zap.Option {
+	return zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return newQuietCore(core, hours)
+	})
+}
+
+// quietWindow is a parsed config.QuietHours, its bounds as offsets from
+// midnight.
+type quietWindow struct {
+	start, end time.Duration
+	level      zapcore.Level
+}
+
+// contains reports whether the time of day d is in w, which wraps past
+// midnight when it ends before it starts.
+func (w quietWindow) contains(d time.Duration) bool {
+	if w.start <= w.end {
+		return d >= w.start && d < w.end
+	}
+	return d >= w.start || d < w.end
+}
+
+func newQuietCore(core zapcore.Core, hours []config.QuietHours) zapcore.Core {
+	var windows []quietWindow
+	for _, h := range hours {
+		start, err1 := time.Parse(config.QuietHoursLayout, h.Start)
+		end, err2 := time.Parse(config.QuietHoursLayout, h.End)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		level := ERROR
+		if h.Level != "" && GetLevel(h.Level) < ERROR {
+			level = GetLevel(h.Level)
+		}
+		windows = append(windows, quietWindow{start: sinceMidnight(start), end: sinceMidnight(end), level: level})
+	}
+	if len(windows) == 0 {
+		return core
+	}
+	return &quietCore{Core: core, windows: windows, now: time.Now}
+}
+
+func sinceMidnight(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+}
+
+type quietCore struct {
+	zapcore.Core
+	windows []quietWindow
+	now     func() time.Time
+}
+
+func (c *quietCore) With(fields []zapcore.Field) zapcore.Core {
+	return &quietCore{Core: c.Core.With(fields), windows: c.windows, now: c.now}
+}
+
+func (c *quietCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *quietCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if c.quiet(ent.Level) {
+		return nil
+	}
+	return c.Core.Write(ent, fields)
+}
+
+// quiet reports whether entries at l are to be dropped now.
+func (c *quietCore) quiet(l zapcore.Level) bool {
+	if l >= ERROR {
+		return false
+	}
+	d := sinceMidnight(c.now())
+	for _, w := range c.windows {
+		if l < w.level && w.contains(d) {
+			return true
+		}
+	}
+	return false
+}
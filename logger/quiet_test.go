@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestQuietHours(t *testing.T) {
+	observed, logs := observer.New(allLevels)
+	core := newQuietCore(observed, []config.QuietHours{
+		{Start: "22:00", End: "06:00"},
+		{Start: "12:00", End: "13:00", Level: "INFO"},
+	}).(*quietCore)
+	var now time.Time
+	core.now = func() time.Time { return now }
+	log := zap.New(core).With(zap.String("job", "batch"))
+
+	for _, tt := range []struct {
+		clock string
+		want  string // the levels passing
+	}{
+		{"21:59", "DEBUG,INFO,WARN,ERROR"},
+		{"22:00", "ERROR"},
+		{"03:30", "ERROR"},
+		{"06:00", "DEBUG,INFO,WARN,ERROR"},
+		{"12:30", "INFO,WARN,ERROR"},
+		{"13:00", "DEBUG,INFO,WARN,ERROR"},
+	} {
+		clock, _ := time.Parse(config.QuietHoursLayout, tt.clock)
+		now = time.Date(2024, 3, 1, clock.Hour(), clock.Minute(), 0, 0, time.Local)
+		logs.TakeAll()
+		log.Debug("debug")
+		log.Info("info")
+		log.Warn("warn")
+		log.Error("error")
+
+		var levels []string
+		for _, e := range logs.All() {
+			levels = append(levels, LevelName(e.Level))
+		}
+		if got := strings.Join(levels, ","); got != tt.want {
+			t.Errorf("at %s logged %s, want %s", tt.clock, got, tt.want)
+		}
+	}
+}
+
+func TestQuietHoursLevelCappedAtError(t *testing.T) {
+	observed, logs := observer.New(allLevels)
+	core := newQuietCore(observed, []config.QuietHours{{Start: "00:00", End: "23:59", Level: "FATAL"}}).(*quietCore)
+	core.now = func() time.Time { return time.Date(2024, 3, 1, 12, 0, 0, 0, time.Local) }
+	log := zap.New(core)
+	log.Warn("warn")
+	log.Error("error")
+
+	if got := observedMessages(logs); len(got) != 1 || got[0] != "error" {
+		t.Errorf("logged %q, want ERROR to pass", got)
+	}
+}
+
+func TestQuietHoursWithoutValidWindows(t *testing.T) {
+	observed, _ := observer.New(allLevels)
+	if core := newQuietCore(observed, []config.QuietHours{{Start: "10pm", End: "06:00"}}); core != observed {
+		t.Errorf("newQuietCore() = %T, want the core as is", core)
+	}
+}
+
+func TestWithQuietHours(t *testing.T) {
+	svc, logs := NewTestLogger(WithQuietHours(config.QuietHours{Start: "00:00", End: "00:00"}))
+	svc.Infoz("passes, the window is empty")
+	if len(logs.All()) != 1 {
+		t.Error("an empty window dropped the entry")
+	}
+}
@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"runtime/debug"
+
+	"go.uber.org/zap"
+)
+
+// RecoverOption configures Recover and Go.
+type RecoverOption func(*recoverOptions)
+
+type recoverOptions struct {
+	fatal   bool
+	repanic bool
+}
+
+// RecoverAsFatal logs recovered panics at Fatal instead of Error, which exits
+// the process once the entry is written.
+func RecoverAsFatal() RecoverOption {
+	return func(o *recoverOptions) {
+		o.fatal = true
+	}
+}
+
+// Repanic panics again with the recovered value once it is logged, for
+// callers that only want the panic on record before it crashes the process.
+func Repanic() RecoverOption {
+	return func(o *recoverOptions) {
+		o.repanic = true
+	}
+}
+
+// Recover recovers a panic and logs it to svc at Error, with the recovered
+// value as a "panic" field and the stack of the panicking goroutine as a
+// "stacktrace" field. It only works when deferred directly:
+//
+//	defer logger.Recover(svc)
+func Recover(svc Service, opts ...RecoverOption) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	var o recoverOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	fields := []Field{zap.Any("panic", r), zap.String("stacktrace", string(debug.Stack()))}
+	if o.fatal {
+		svc.Fatalz("Recovered from a panic!", fields...)
+	} else {
+		svc.Errorz("Recovered from a panic!", fields...)
+	}
+	if o.repanic {
+		panic(r)
+	}
+}
+
+// Go runs f in a new goroutine guarded by Recover, so a panic in f is logged
+// to svc rather than crashing the process.
+func Go(svc Service, f func(), opts ...RecoverOption) {
+	go func() {
+		defer Recover(svc, opts...)
+		f()
+	}()
+}
@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func panicking() {
+	panic("boom")
+}
+
+func TestRecover(t *testing.T) {
+	svc, logs := NewTestLogger()
+	func() {
+		defer Recover(svc)
+		panicking()
+	}()
+
+	entries := logs.AllUntimed()
+	if len(entries) != 1 {
+		t.Fatalf("logged %d entries, want 1", len(entries))
+	}
+	e := entries[0]
+	f := e.ContextMap()
+	if e.Level != ERROR || f["panic"] != "boom" {
+		t.Errorf("logged %s %v, want ERROR with the panic", LevelName(e.Level), f)
+	}
+	if stack, _ := f["stacktrace"].(string); !strings.Contains(stack, "logger.panicking") {
+		t.Errorf("stacktrace = %q, want the panicking function", stack)
+	}
+}
+
+func TestRecoverWithoutAPanic(t *testing.T) {
+	svc, logs := NewTestLogger()
+	func() {
+		defer Recover(svc)
+	}()
+	if logs.Len() != 0 {
+		t.Errorf("logged %v without a panic", logs.AllUntimed())
+	}
+}
+
+func TestRecoverRepanic(t *testing.T) {
+	svc, logs := NewTestLogger()
+	v := recovered(func() {
+		defer Recover(svc, Repanic())
+		panicking()
+	})
+	if v != "boom" || logs.Len() != 1 {
+		t.Errorf("panicked again with %v after %d entries, want boom after 1", v, logs.Len())
+	}
+}
+
+func TestRecoverAsFatal(t *testing.T) {
+	hook := &recordingHook{}
+	svc, logs := NewTestLogger(zap.WithFatalHook(hook))
+	func() {
+		defer Recover(svc, RecoverAsFatal())
+		panicking()
+	}()
+	if entries := logs.AllUntimed(); len(entries) != 1 || entries[0].Level != FATAL || len(hook.entries) != 1 {
+		t.Errorf("logged %v, want one FATAL entry", entries)
+	}
+}
+
+func TestGo(t *testing.T) {
+	svc, logs := NewTestLogger()
+	done := make(chan struct{})
+	Go(svc, func() {
+		defer close(done)
+		panicking()
+	})
+	<-done
+	// the deferred close runs before Recover logs
+	for i := 0; logs.Len() == 0 && i < 100; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if logs.Len() != 1 {
+		t.Errorf("logged %d entries, want the panic", logs.Len())
+	}
+}
@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/dazzling420/go-logger/config"
+)
+
+// NewServices builds a logger per named logger of cfg.Loggers with
+// BuildService, once all of them are valid. cfg.Logger is left alone. A
+// Registry built with NewRegistry also closes them together.
+func NewServices(cfg config.Config) (map[string]Service, error) {
+	r, err := NewRegistry(cfg)
+	if err != nil {
+		return nil, err
+	}
+	services := make(map[string]Service, len(r.loggers))
+	for name, l := range r.loggers {
+		services[name] = l
+	}
+	return services, nil
+}
+
+// Registry holds named loggers so code can fetch the one meant for it, e.g.
+// the "access" logger in HTTP middleware.
+type Registry struct {
+	mu      sync.RWMutex
+	loggers map[string]*standardLogger
+}
+
+// NewRegistry returns a Registry holding a logger per named logger of
+// cfg.Loggers, see NewServices.
+func NewRegistry(cfg config.Config) (*Registry, error) {
+	if err := cfg.ValidateLoggers(); err != nil {
+		return nil, err
+	}
+	r := &Registry{loggers: make(map[string]*standardLogger, len(cfg.Loggers))}
+	for name, conf := range cfg.Loggers {
+		l, err := BuildService(conf)
+		if err != nil {
+			// close the loggers already built
+			r.Close()
+			return nil, fmt.Errorf("loggers[%q]: %w", name, err)
+		}
+		r.loggers[name] = l
+	}
+	return r, nil
+}
+
+// Get returns the logger named name, nil when there is none.
+func (r *Registry) Get(name string) Service {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if l, ok := r.loggers[name]; ok {
+		return l
+	}
+	return nil
+}
+
+// Names returns the names of the loggers, sorted.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.loggers))
+	for name := range r.loggers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Close closes every logger, see standardLogger.Close. The registry must not
+// be used afterwards.
+func (r *Registry) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var errs []error
+	for _, l := range r.loggers {
+		errs = append(errs, l.Close())
+	}
+	r.loggers = nil
+	return errors.Join(errs...)
+}
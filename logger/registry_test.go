@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dazzling420/go-logger/config"
+)
+
+func TestNewServicesIsolatesTheLoggers(t *testing.T) {
+	dir := t.TempDir()
+	app, access := filepath.Join(dir, "app.log"), filepath.Join(dir, "access.log")
+	services, err := NewServices(config.Config{Loggers: map[string]config.Logger{
+		"app":    {LogFileName: app, LoggingLevel: "WARN", DisableStdout: true},
+		"access": {LogFileName: access, LoggingLevel: "DEBUG", DisableStdout: true},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(services) != 2 {
+		t.Fatalf("built %d loggers, want 2", len(services))
+	}
+	services["app"].Infoz("app info")
+	services["app"].Warnz("app warn")
+	services["access"].Debugz("access debug")
+	for _, s := range services {
+		s.(*standardLogger).Close()
+	}
+
+	if got := strings.Join(messages(readEntries(t, app)), ","); got != "app warn" {
+		t.Errorf("app.log has %s, want app warn", got)
+	}
+	if got := strings.Join(messages(readEntries(t, access)), ","); got != "access debug" {
+		t.Errorf("access.log has %s, want access debug", got)
+	}
+}
+
+func TestNewServicesRejectsInvalidLoggers(t *testing.T) {
+	dir := t.TempDir()
+	shared := filepath.Join(dir, "shared.log")
+	_, err := NewServices(config.Config{Loggers: map[string]config.Logger{
+		"app":    {LogFileName: shared},
+		"access": {LogFileName: shared},
+	}})
+	if err == nil || !strings.Contains(err.Error(), "both write") {
+		t.Errorf("NewServices() = %v, want the shared file reported", err)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	dir := t.TempDir()
+	r, err := NewRegistry(config.Config{Loggers: map[string]config.Logger{
+		"app":   {LogFileName: filepath.Join(dir, "app.log"), DisableStdout: true},
+		"audit": {LogFileName: filepath.Join(dir, "audit.log"), DisableStdout: true},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(r.Names(), ","); got != "app,audit" {
+		t.Errorf("Names() = %s, want app,audit", got)
+	}
+	if r.Get("app") == nil || r.Get("audit") == nil {
+		t.Error("Get() = nil for a named logger")
+	}
+	if r.Get("access") != nil {
+		t.Error("Get() != nil for an unknown name")
+	}
+	r.Get("audit").Infoz("audited")
+	if err := r.Close(); err != nil {
+		t.Errorf("Close() = %v", err)
+	}
+	if got := messages(readEntries(t, filepath.Join(dir, "audit.log"))); len(got) != 1 || got[0] != "audited" {
+		t.Errorf("audit.log has %q, want the entry flushed by Close", got)
+	}
+	if r.Get("app") != nil {
+		t.Error("Get() != nil once closed")
+	}
+}
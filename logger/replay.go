@@ -0,0 +1,232 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxReplayLineSize is the longest log line Replay accepts.
+const maxReplayLineSize = 10 << 20
+
+// Entry is a log line written by one of this package's JSON encodings, as
+// parsed by Replay.
+type Entry struct {
+	Level   zapcore.Level
+	Time    time.Time
+	Caller  string
+	Message string
+	Fields  []Field // every other key, in the order it was written
+}
+
+// ReplayOption configures Replay.
+type ReplayOption func(*replayer)
+
+// WithReplayConfig has Replay read lines written by a logger configured with
+// conf, rather than with the configuration of the logger replayed to.
+func WithReplayConfig(conf config.Logger) ReplayOption {
+	return func(r *replayer) {
+		conf = conf.WithDefaults()
+		r.conf = &conf
+	}
+}
+
+type replayer struct {
+	conf *config.Logger
+}
+
+// Replay parses the JSON log lines read from r and re-emits every entry for
+// which filter returns true through out, keeping the original time, level
+// and caller. A nil filter replays everything. The keys and level names are
+// those the configuration of out encodes entries with, e.g. severity and
+// WARNING for the gcp encoding, see WithReplayConfig for lines written with
+// another one. Levels are matched regardless of case, unknown ones are read
+// as INFO. Lines that aren't valid JSON objects are skipped and counted in
+// malformed. Panic and Fatal entries are written but never panic or exit.
+func Replay(r io.Reader, filter func(Entry) bool, out *standardLogger, opts ...ReplayOption) (malformed int, err error) {
+	rp := &replayer{}
+	if out.conf != nil {
+		rp.conf = out.conf.Load()
+	}
+	for _, opt := range opts {
+		opt(rp)
+	}
+	if rp.conf == nil {
+		conf := config.Logger{}.WithDefaults()
+		rp.conf = &conf
+	}
+	format := newReplayFormat(rp.conf)
+	core := out.log.Core()
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxReplayLineSize)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		e, ok := format.parseEntry(line)
+		if !ok {
+			malformed++
+			continue
+		}
+		if filter != nil && !filter(e) {
+			continue
+		}
+
+		ent := zapcore.Entry{
+			Level:   e.Level,
+			Time:    e.Time,
+			Message: e.Message,
+			Caller:  parseCaller(e.Caller),
+		}
+		if ce := core.Check(ent, nil); ce != nil {
+			ce.Write(e.Fields...)
+		}
+	}
+	return malformed, scanner.Err()
+}
+
+// replayFormat holds the keys and level names of the lines Replay reads.
+type replayFormat struct {
+	levelKey, timeKey, callerKey, messageKey string
+
+	ecs    bool                     // the caller is the log.origin object
+	levels map[string]zapcore.Level // by lowercase name
+}
+
+func newReplayFormat(conf *config.Logger) replayFormat {
+	cfg := encoderConfigFor(conf)
+	switch conf.Encoding {
+	case EncodingGCP:
+		cfg = gcpEncoderConfig(cfg)
+	case EncodingDatadog:
+		cfg = datadogEncoderConfig(cfg)
+	case EncodingECS:
+		cfg = ecsEncoderConfig(cfg)
+	}
+
+	f := replayFormat{
+		levelKey:   cfg.LevelKey,
+		timeKey:    cfg.TimeKey,
+		callerKey:  cfg.CallerKey,
+		messageKey: cfg.MessageKey,
+		ecs:        conf.Encoding == EncodingECS,
+		levels:     map[string]zapcore.Level{},
+	}
+	levels := []zapcore.Level{TRACE, DEBUG, INFO, WARN, ERROR, DPANIC, PANIC, FATAL, AUDIT}
+	// the names the encoding writes win over the standard ones, GCP calling
+	// WARN "WARNING" among others
+	for _, l := range levels {
+		name := encodeCell(func(enc zapcore.PrimitiveArrayEncoder) { cfg.EncodeLevel(l, enc) })
+		f.levels[strings.ToLower(name)] = l
+	}
+	for _, l := range levels {
+		if _, ok := f.levels[lowercaseLevelName(l)]; !ok {
+			f.levels[lowercaseLevelName(l)] = l
+		}
+	}
+	return f
+}
+
+func (f replayFormat) parseEntry(line []byte) (Entry, bool) {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return Entry{}, false
+	}
+
+	e := Entry{Level: INFO}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return Entry{}, false
+		}
+		key := tok.(string)
+		var v interface{}
+		if err := dec.Decode(&v); err != nil {
+			return Entry{}, false
+		}
+
+		s, isString := v.(string)
+		switch {
+		case key == f.levelKey && isString:
+			if l, ok := f.levels[strings.ToLower(s)]; ok {
+				e.Level = l
+			}
+		case key == f.timeKey && isString:
+			e.Time = parseTime(s)
+		case key == f.callerKey && isString:
+			e.Caller = s
+		case key == f.messageKey && isString:
+			e.Message = s
+		case f.ecs && key == "log" && ecsCaller(v) != "":
+			e.Caller = ecsCaller(v)
+		case f.ecs && key == "ecs.version":
+			// written again by the ecs encoding
+		default:
+			e.Fields = append(e.Fields, replayField(key, v))
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return Entry{}, false
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	return e, true
+}
+
+// ecsCaller returns the caller held by the ECS log object v as "file:line",
+// "" when v holds none.
+func ecsCaller(v interface{}) string {
+	log, _ := v.(map[string]interface{})
+	origin, _ := log["origin"].(map[string]interface{})
+	file, _ := origin["file"].(map[string]interface{})
+	name, _ := file["name"].(string)
+	line, _ := file["line"].(json.Number)
+	if name == "" || line == "" {
+		return ""
+	}
+	return name + ":" + line.String()
+}
+
+func replayField(key string, v interface{}) Field {
+	if n, ok := v.(json.Number); ok {
+		if i, err := n.Int64(); err == nil {
+			return zap.Int64(key, i)
+		}
+		f, _ := n.Float64()
+		return zap.Float64(key, f)
+	}
+	return zap.Any(key, v)
+}
+
+func parseTime(s string) time.Time {
+	for _, layout := range []string{"2006-01-02T15:04:05.000Z0700", time.RFC3339Nano} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func parseCaller(s string) zapcore.EntryCaller {
+	i := strings.LastIndexByte(s, ':')
+	if i < 0 {
+		return zapcore.EntryCaller{}
+	}
+	line, err := strconv.Atoi(s[i+1:])
+	if err != nil {
+		return zapcore.EntryCaller{}
+	}
+	return zapcore.EntryCaller{Defined: true, File: s[:i], Line: line}
+}
@@ -0,0 +1,152 @@
+package logger
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func newReplayTarget(t *testing.T) (*standardLogger, *observer.ObservedLogs) {
+	t.Helper()
+	svc, logs := NewTestLogger()
+	return svc.(*standardLogger), logs
+}
+
+func TestReplayParsesEntries(t *testing.T) {
+	out, logs := newReplayTarget(t)
+	input := strings.Join([]string{
+		`{"level":"INFO","time":"2024-03-01T10:00:00.000Z","caller":"app/main.go:12","message":"started","user":"bob","n":3}`,
+		`not json`,
+		`{"level":"warn","message":"lowercase"}`,
+		`{"level":"Trace","message":"mixed case"}`,
+		`{"level":"AUDIT","message":"audited"}`,
+		`{"level":"LOUD","message":"unknown level"}`,
+	}, "\n")
+
+	malformed, err := Replay(strings.NewReader(input), nil, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if malformed != 1 {
+		t.Errorf("malformed = %d, want 1", malformed)
+	}
+
+	entries := logs.All()
+	want := []struct {
+		level   string
+		message string
+	}{{"INFO", "started"}, {"WARN", "lowercase"}, {"TRACE", "mixed case"}, {"AUDIT", "audited"}, {"INFO", "unknown level"}}
+	if len(entries) != len(want) {
+		t.Fatalf("replayed %d entries, want %d", len(entries), len(want))
+	}
+	for i, w := range want {
+		if got := LevelName(entries[i].Level); got != w.level || entries[i].Message != w.message {
+			t.Errorf("entry %d = %s %q, want %s %q", i, got, entries[i].Message, w.level, w.message)
+		}
+	}
+
+	first := entries[0]
+	if want := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC); !first.Time.Equal(want) {
+		t.Errorf("time = %v, want %v", first.Time, want)
+	}
+	if !first.Caller.Defined || first.Caller.File != "app/main.go" || first.Caller.Line != 12 {
+		t.Errorf("caller = %+v, want app/main.go:12", first.Caller)
+	}
+	fields := first.ContextMap()
+	if fields["user"] != "bob" || fields["n"] != int64(3) {
+		t.Errorf("fields = %v, want user=bob n=3", fields)
+	}
+}
+
+func TestReplayFilter(t *testing.T) {
+	out, logs := newReplayTarget(t)
+	input := `{"level":"INFO","message":"kept out"}` + "\n" + `{"level":"ERROR","message":"kept"}`
+
+	_, err := Replay(strings.NewReader(input), func(e Entry) bool { return e.Level >= WARN }, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries := logs.AllUntimed(); len(entries) != 1 || entries[0].Message != "kept" {
+		t.Errorf("replayed %v, want only the ERROR entry", entries)
+	}
+}
+
+func TestReplayReadsTheKeysOfTheEncoding(t *testing.T) {
+	msg := "msg"
+	for name, conf := range map[string]config.Logger{
+		"json":             {},
+		"renamed keys":     {MessageKey: &msg},
+		"lowercase levels": {LevelEncoding: LevelEncodingLowercase},
+		EncodingGCP:        {Encoding: EncodingGCP},
+		EncodingECS:        {Encoding: EncodingECS},
+		EncodingDatadog:    {Encoding: EncodingDatadog},
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := tempLog(t, "app.log")
+			conf.LogFileName = path
+			conf.DisableStdout = true
+			svc, err := BuildService(conf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			svc.Warnz("careful")
+			svc.Audit("audited")
+			svc.Close()
+
+			f, err := os.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			out, logs := newReplayTarget(t)
+			malformed, err := Replay(f, nil, out, WithReplayConfig(conf))
+			if err != nil || malformed != 0 {
+				t.Fatalf("Replay = %d, %v", malformed, err)
+			}
+
+			entries := logs.AllUntimed()
+			if len(entries) != 2 {
+				t.Fatalf("replayed %d entries, want 2", len(entries))
+			}
+			if entries[0].Level != WARN || entries[0].Message != "careful" {
+				t.Errorf("first entry = %s %q, want WARN careful", LevelName(entries[0].Level), entries[0].Message)
+			}
+			if entries[1].Level != AUDIT || entries[1].Message != "audited" {
+				t.Errorf("second entry = %s %q, want AUDIT audited", LevelName(entries[1].Level), entries[1].Message)
+			}
+			if !entries[0].Caller.Defined || !strings.HasSuffix(entries[0].Caller.File, "replay_test.go") {
+				t.Errorf("caller = %+v, want this file", entries[0].Caller)
+			}
+			for _, f := range entries[0].Context {
+				switch f.Key {
+				case "level", "severity", "status", "log.level", "message", "msg", "time", "timestamp", "@timestamp", "caller", "log", "ecs.version":
+					t.Errorf("standard key %q replayed as a field", f.Key)
+				}
+			}
+		})
+	}
+}
+
+func TestReplayUsesTheConfigOfTheLogger(t *testing.T) {
+	msg := "msg"
+	out, err := BuildService(config.Logger{MessageKey: &msg, LogFileName: tempLog(t, "out.log"), DisableStdout: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	var got []Entry
+	_, err = Replay(strings.NewReader(`{"level":"INFO","msg":"renamed"}`), func(e Entry) bool {
+		got = append(got, e)
+		return false
+	}, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Message != "renamed" || len(got[0].Fields) != 0 {
+		t.Errorf("parsed %+v, want the message under msg", got)
+	}
+}
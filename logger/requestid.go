@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// RequestIDHeader is the header, and lowercased the gRPC metadata key, an
+// upstream assigned request id is read from and written back to.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds the incoming ids reused, longer ones being
+// replaced like any other implausible id.
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// newRequestID returns a random (version 4) UUID.
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// validRequestID reports whether id may be reused as is: it comes from the
+// outside, so it has to be short and printable ASCII to be logged safely.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// WithRequestID returns ctx as is when it already carries a request id,
+// otherwise a copy carrying a newly generated UUID, see WithIncomingRequestID.
+func WithRequestID(ctx context.Context) context.Context {
+	if RequestIDFromContext(ctx) != "" {
+		return ctx
+	}
+	return WithIncomingRequestID(ctx, "")
+}
+
+// WithIncomingRequestID returns a copy of ctx carrying id, typically the
+// RequestIDHeader of an incoming request, so upstream assigned ids propagate.
+// A new UUID is used instead when id is empty, longer than 128 bytes or holds
+// anything but printable ASCII. The copy also carries a child of
+// FromContext(ctx) with a request_id field, if there is a logger, for
+// FromContext to return. HTTPMiddleware and the loggrpc interceptors call it
+// for every request.
+func WithIncomingRequestID(ctx context.Context, id string) context.Context {
+	if !validRequestID(id) {
+		id = newRequestID()
+	}
+	ctx = context.WithValue(ctx, requestIDKey{}, id)
+	if svc := FromContext(ctx); svc != nil {
+		ctx = NewContext(ctx, svc.GetLogger().With(zap.String("request_id", id)))
+	}
+	return ctx
+}
+
+// RequestIDFromContext returns the request id stored in ctx by WithRequestID
+// or WithIncomingRequestID, "" when there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var uuidV4 = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestWithRequestIDGeneratesAnID(t *testing.T) {
+	if id := RequestIDFromContext(context.Background()); id != "" {
+		t.Errorf("RequestIDFromContext() = %q without an id, want empty", id)
+	}
+
+	a := RequestIDFromContext(WithRequestID(context.Background()))
+	b := RequestIDFromContext(WithRequestID(context.Background()))
+	if !uuidV4.MatchString(a) || !uuidV4.MatchString(b) {
+		t.Errorf("ids = %q and %q, want version 4 UUIDs", a, b)
+	}
+	if a == b {
+		t.Errorf("both ids are %s", a)
+	}
+}
+
+func TestWithRequestIDKeepsTheIDOfTheContext(t *testing.T) {
+	ctx := WithIncomingRequestID(context.Background(), "upstream-1")
+	if got := WithRequestID(ctx); got != ctx {
+		t.Error("WithRequestID() replaced the id of the context")
+	}
+}
+
+func TestWithIncomingRequestID(t *testing.T) {
+	for _, tt := range []struct {
+		name, id string
+		reused   bool
+	}{
+		{"upstream id", "upstream-1", true},
+		{"longest id", strings.Repeat("a", maxRequestIDLength), true},
+		{"empty", "", false},
+		{"too long", strings.Repeat("a", maxRequestIDLength+1), false},
+		{"space", "two words", false},
+		{"newline", "forged\nline", false},
+		{"non ASCII", "id-é", false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RequestIDFromContext(WithIncomingRequestID(context.Background(), tt.id))
+			if tt.reused && got != tt.id {
+				t.Errorf("id = %q, want %q reused", got, tt.id)
+			}
+			if !tt.reused && !uuidV4.MatchString(got) {
+				t.Errorf("id = %q, want a generated UUID in place of %q", got, tt.id)
+			}
+		})
+	}
+}
+
+func TestWithRequestIDAddsTheFieldToTheLogger(t *testing.T) {
+	svc, logs := NewTestLogger()
+	ctx := WithIncomingRequestID(NewContext(context.Background(), svc), "upstream-1")
+	FromContext(ctx).Infoz("handling")
+	svc.Infoz("parent")
+
+	entries := logs.AllUntimed()
+	if got := entries[0].ContextMap()["request_id"]; got != "upstream-1" {
+		t.Errorf("request_id = %v, want upstream-1", got)
+	}
+	if _, ok := entries[1].ContextMap()["request_id"]; ok {
+		t.Error("the id was added to the parent logger")
+	}
+}
+
+func TestWithRequestIDWithoutALogger(t *testing.T) {
+	useGlobal(t, nil)
+	ctx := WithRequestID(context.Background())
+	if RequestIDFromContext(ctx) == "" || FromContext(ctx) != nil {
+		t.Error("want an id and still no logger")
+	}
+}
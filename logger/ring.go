@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"bytes"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// RingCore keeps the last entries enabled by its level in memory, encoded,
+// so they can be dumped after a crash or from a debug endpoint even when they
+// were below the level of the other outputs. It holds at most size entries,
+// older ones being overwritten.
+type RingCore struct {
+	zapcore.Core
+	ring *ring
+}
+
+// NewRingCore returns a RingCore keeping the last size entries at or above
+// level as JSON lines.
+func NewRingCore(size int, level zapcore.Level) *RingCore {
+	return newRingCore(zapcore.NewJSONEncoder(newEncoderConfig()), size, level)
+}
+
+func newRingCore(enc zapcore.Encoder, size int, enab zapcore.LevelEnabler) *RingCore {
+	r := &ring{lines: make([]string, size)}
+	return &RingCore{Core: zapcore.NewCore(enc, r, enab), ring: r}
+}
+
+// DumpRecent returns the entries kept, oldest first, without their line
+// ending.
+func (c *RingCore) DumpRecent() []string {
+	return c.ring.dump()
+}
+
+// ring is a WriteSyncer keeping the last len(lines) writes, each being one
+// encoded entry.
+type ring struct {
+	mu    sync.Mutex
+	lines []string
+	next  int
+	full  bool
+}
+
+func (r *ring) Write(p []byte) (int, error) {
+	if len(r.lines) == 0 {
+		return len(p), nil
+	}
+	line := string(bytes.TrimRight(p, "\r\n"))
+	r.mu.Lock()
+	r.lines[r.next] = line
+	r.next++
+	if r.next == len(r.lines) {
+		r.next = 0
+		r.full = true
+	}
+	r.mu.Unlock()
+	return len(p), nil
+}
+
+func (r *ring) Sync() error {
+	return nil
+}
+
+func (r *ring) dump() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([]string(nil), r.lines[:r.next]...)
+	}
+	lines := make([]string, 0, len(r.lines))
+	lines = append(lines, r.lines[r.next:]...)
+	return append(lines, r.lines[:r.next]...)
+}
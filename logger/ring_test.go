@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap"
+)
+
+// ringMessages returns the messages of the JSON lines dumped.
+func ringMessages(t *testing.T, lines []string) []string {
+	t.Helper()
+	var got []string
+	for _, line := range lines {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("%q: %v", line, err)
+		}
+		got = append(got, fmt.Sprint(entry["message"]))
+	}
+	return got
+}
+
+func TestRingCoreKeepsTheLastEntries(t *testing.T) {
+	core := NewRingCore(3, DEBUG)
+	log := zap.New(core)
+
+	if got := core.DumpRecent(); len(got) != 0 {
+		t.Errorf("DumpRecent() = %q before logging", got)
+	}
+	log.Info("0")
+	log.Info("1")
+	if got := strings.Join(ringMessages(t, core.DumpRecent()), ","); got != "0,1" {
+		t.Errorf("DumpRecent() = %s, want 0,1", got)
+	}
+
+	for i := 2; i < 8; i++ {
+		log.Debug(fmt.Sprint(i))
+	}
+	log.Check(TRACE, "below the level").Write()
+	lines := core.DumpRecent()
+	if got := strings.Join(ringMessages(t, lines), ","); got != "5,6,7" {
+		t.Errorf("DumpRecent() = %s, want 5,6,7", got)
+	}
+	for _, line := range lines {
+		if strings.HasSuffix(line, "\n") {
+			t.Errorf("line %q kept its line ending", line)
+		}
+	}
+}
+
+func TestRingCoreConcurrently(t *testing.T) {
+	core := NewRingCore(10, DEBUG)
+	log := zap.New(core)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				log.Info("entry")
+				core.DumpRecent()
+			}
+		}()
+	}
+	wg.Wait()
+	if got := len(core.DumpRecent()); got != 10 {
+		t.Errorf("kept %d entries, want 10", got)
+	}
+}
+
+func TestRecentEntries(t *testing.T) {
+	s, path := newFileService(t, config.Logger{LoggingLevel: "INFO", RecentEntries: 2})
+	s.Debugz("debug")
+	s.Tracez("trace")
+	s.Infoz("info")
+	s.Sync()
+
+	if got := strings.Join(ringMessages(t, s.DumpRecent()), ","); got != "debug,info" {
+		t.Errorf("DumpRecent() = %s, want debug,info", got)
+	}
+	if got := strings.Join(messages(readEntries(t, path)), ","); got != "info" {
+		t.Errorf("logged %s, want only info", got)
+	}
+}
+
+func TestDumpRecentWithoutRecentEntries(t *testing.T) {
+	s, _ := newFileService(t, config.Logger{})
+	s.Infoz("info")
+	if got := s.DumpRecent(); got != nil {
+		t.Errorf("DumpRecent() = %q, want nil", got)
+	}
+}
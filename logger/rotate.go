@@ -0,0 +1,109 @@
+package logger
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat is the timestamp lumberjack puts in the name of rotated
+// files, reused so it still counts ours for MaxBackups and MaxAge.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// clock abstracts time for the daily rotation.
+type clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// dailyRotator rotates a lumberjack file at every local midnight, on top of
+// lumberjack's own size based rotation. The rotated file is named after the
+// last instant of the day it holds, e.g. app-2024-03-01T23-59-59.999.log.
+type dailyRotator struct {
+	lumberjackSink
+	clock clock
+
+	mu       sync.Mutex // held by writes so none lands between closing and renaming
+	onRotate func()     // called once the file is closed, see notify
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+func newDailyRotator(sink lumberjackSink, clock clock) *dailyRotator {
+	r := &dailyRotator{
+		lumberjackSink: sink,
+		clock:          clock,
+		stop:           make(chan struct{}),
+		done:           make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+func (r *dailyRotator) run() {
+	defer close(r.done)
+	for {
+		now := r.clock.Now()
+		midnight := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+		select {
+		case <-r.clock.After(midnight.Sub(now)):
+			r.rotate(midnight.Add(-time.Millisecond))
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// rotate moves the current file aside under a name stamped with dayEnd. The
+// next write opens a new file, which also lets lumberjack prune backups.
+func (r *dailyRotator) rotate(dayEnd time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.Logger.Close(); err != nil {
+		return err
+	}
+	if r.onRotate != nil {
+		r.onRotate()
+	}
+	name := r.Logger.Filename
+	if _, err := os.Stat(name); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	ext := filepath.Ext(name)
+	backup := strings.TrimSuffix(name, ext) + "-" + dayEnd.Format(backupTimeFormat) + ext
+	return os.Rename(name, backup)
+}
+
+// notify has f called at every daily rotation, with writes held.
+func (r *dailyRotator) notify(f func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onRotate = f
+}
+
+func (r *dailyRotator) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.Logger.Write(p)
+}
+
+// Close stops the daily rotation and closes the file.
+func (r *dailyRotator) Close() error {
+	r.stopOnce.Do(func() { close(r.stop) })
+	<-r.done
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.Logger.Close()
+}
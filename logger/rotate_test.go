@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dazzling420/go-logger/config"
+)
+
+// fakeClock hands the channels of After to the test, which fires them.
+type fakeClock struct {
+	now     time.Time
+	waiting chan chan time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) After(time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	c.waiting <- ch
+	return ch
+}
+
+func TestDailyRotatorRotatesAtMidnight(t *testing.T) {
+	path := tempLog(t, "app.log")
+	conf := config.Logger{}.WithDefaults()
+	// The backup is dated 2024, keep lumberjack from pruning it by age.
+	conf.MaxOldLogRetentionInDays, conf.MaxLogBackupsCount = 0, 0
+	clk := &fakeClock{now: time.Date(2024, 3, 1, 10, 0, 0, 0, time.Local), waiting: make(chan chan time.Time)}
+	r := newDailyRotator(newLumberjackSink(path, &conf), clk)
+	defer r.Close()
+	rotated := 0
+	r.notify(func() { rotated++ })
+
+	midnight := <-clk.waiting
+	r.Write([]byte("yesterday\n"))
+	midnight <- clk.now
+	<-clk.waiting // rotated, waiting for the next midnight
+	r.Write([]byte("today\n"))
+
+	if rotated != 1 {
+		t.Errorf("notified of %d rotations, want 1", rotated)
+	}
+	backup := filepath.Join(filepath.Dir(path), "app-2024-03-01T23-59-59.999.log")
+	if got := readFile(t, backup); got != "yesterday\n" {
+		t.Errorf("%s = %q, want yesterday's line", filepath.Base(backup), got)
+	}
+	if got := readFile(t, path); got != "today\n" {
+		t.Errorf("app.log = %q, want today's line", got)
+	}
+}
+
+func TestDailyRotatorWithoutAFile(t *testing.T) {
+	path := tempLog(t, "app.log")
+	conf := config.Logger{}.WithDefaults()
+	r := newDailyRotator(newLumberjackSink(path, &conf), stoppedClock{})
+	defer r.Close()
+
+	if err := r.rotate(time.Now()); err != nil {
+		t.Errorf("rotate() = %v before any write", err)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 0 {
+		t.Errorf("rotating created %d files", len(entries))
+	}
+}
+
+func TestRotateDaily(t *testing.T) {
+	svc, path := newFileService(t, config.Logger{RotateDaily: true})
+	svc.Infoz("before")
+	if err := svc.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	svc.Infoz("after")
+	svc.Close()
+
+	files, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "*.log"))
+	if len(files) != 2 {
+		t.Fatalf("files = %q, want the log and one backup", files)
+	}
+	if got := messages(readEntries(t, path)); len(got) != 1 || got[0] != "after" {
+		t.Errorf("app.log = %q, want [after]", got)
+	}
+}
@@ -0,0 +1,193 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// samplingLevels is the number of levels a sampler tells apart, TRACE to
+	// AUDIT.
+	samplingLevels = int(AUDIT-TRACE) + 1
+	// samplingCounters is the number of messages counted apart per level,
+	// messages beyond it share counters.
+	samplingCounters = 1024
+)
+
+// samplingRule keeps the first initial entries with a given level and
+// message per tick, then every thereafter-th, none when thereafter is 0.
+type samplingRule struct {
+	initial    uint64
+	thereafter uint64
+}
+
+// sampler counts entries by level and message like zap's sampler does and
+// how many it drops per level.
+type sampler struct {
+	tick     time.Duration
+	rules    [samplingLevels]*samplingRule // nil for levels that aren't sampled
+	counters [samplingLevels][samplingCounters]samplingCounter
+	dropped  [samplingLevels]atomic.Uint64
+}
+
+// newSampler returns a sampler applying conf, see config.Sampling. AUDIT
+// entries are never sampled.
+func newSampler(conf *config.Sampling) *sampler {
+	s := &sampler{tick: conf.Tick}
+	if conf.Initial > 0 {
+		rule := newSamplingRule(conf.SamplingRule)
+		for l := TRACE; l < AUDIT; l++ {
+			s.rules[l-TRACE] = rule
+		}
+	}
+	for name, rule := range conf.Levels {
+		if i, ok := levelIndex(GetLevel(name)); ok {
+			s.rules[i] = newSamplingRule(rule)
+		}
+	}
+	return s
+}
+
+func newSamplingRule(rule config.SamplingRule) *samplingRule {
+	return &samplingRule{initial: uint64(rule.Initial), thereafter: uint64(rule.Thereafter)}
+}
+
+// keep reports whether ent is to be written, counting it as dropped
+// otherwise.
+func (s *sampler) keep(ent zapcore.Entry) bool {
+	i, ok := levelIndex(ent.Level)
+	if !ok || s.rules[i] == nil {
+		return true
+	}
+	rule := s.rules[i]
+	n := s.counters[i][fnv32a(ent.Message)%samplingCounters].inc(ent.Time, s.tick)
+	if n <= rule.initial || (rule.thereafter > 0 && (n-rule.initial)%rule.thereafter == 0) {
+		return true
+	}
+	s.dropped[i].Add(1)
+	return false
+}
+
+// takeDropped returns the number of entries dropped per level since it was
+// last called, levels without drops left out.
+func (s *sampler) takeDropped() map[zapcore.Level]uint64 {
+	var dropped map[zapcore.Level]uint64
+	for i := range s.dropped {
+		if n := s.dropped[i].Swap(0); n > 0 {
+			if dropped == nil {
+				dropped = make(map[zapcore.Level]uint64)
+			}
+			dropped[TRACE+zapcore.Level(i)] = n
+		}
+	}
+	return dropped
+}
+
+// samplingCounter counts the entries of a tick, restarting when it is over.
+type samplingCounter struct {
+	resetAt atomic.Int64
+	n       atomic.Uint64
+}
+
+func (c *samplingCounter) inc(t time.Time, tick time.Duration) uint64 {
+	now := t.UnixNano()
+	resetAt := c.resetAt.Load()
+	if resetAt > now {
+		return c.n.Add(1)
+	}
+	// the first entry of a new tick restarts the count, the ones racing it
+	// count along
+	c.n.Store(1)
+	if !c.resetAt.CompareAndSwap(resetAt, now+tick.Nanoseconds()) {
+		return c.n.Add(1)
+	}
+	return 1
+}
+
+func fnv32a(s string) uint32 {
+	const (
+		offset32 = 2166136261
+		prime32  = 16777619
+	)
+	h := uint32(offset32)
+	for i := 0; i < len(s); i++ {
+		h ^= uint32(s[i])
+		h *= prime32
+	}
+	return h
+}
+
+// samplingCore drops the entries its sampler doesn't keep. Unlike zap's
+// sampler it decides on Write, as the atomCore above it never calls Check on
+// the cores it wraps.
+type samplingCore struct {
+	zapcore.Core
+	s *sampler
+}
+
+func newSamplingCore(core zapcore.Core, s *sampler) zapcore.Core {
+	return &samplingCore{Core: core, s: s}
+}
+
+func (c *samplingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &samplingCore{Core: c.Core.With(fields), s: c.s}
+}
+
+func (c *samplingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *samplingCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if !c.s.keep(ent) {
+		return nil
+	}
+	return c.Core.Write(ent, fields)
+}
+
+// reportSampled logs, every interval in which s dropped entries, how many it
+// dropped per level, as dropped_<level> fields next to their total. The
+// returned function stops reporting.
+func reportSampled(log *zap.Logger, s *sampler, interval time.Duration) func() error {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				dropped := s.takeDropped()
+				if len(dropped) == 0 {
+					continue
+				}
+				var total uint64
+				fields := make([]Field, 0, len(dropped)+1)
+				for l := TRACE; l <= AUDIT; l++ {
+					if n, ok := dropped[l]; ok {
+						fields = append(fields, zap.Uint64("dropped_"+lowercaseLevelName(l), n))
+						total += n
+					}
+				}
+				log.Info("Dropped log entries by sampling!", append(fields, zap.Uint64("dropped", total))...)
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() error {
+		once.Do(func() { close(stop) })
+		<-done
+		return nil
+	}
+}
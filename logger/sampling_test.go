@@ -0,0 +1,142 @@
+package logger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSamplerKeepsInitialThenEveryThereafter(t *testing.T) {
+	s := newSampler(&config.Sampling{SamplingRule: config.SamplingRule{Initial: 2, Thereafter: 3}, Tick: time.Minute})
+	now := time.Now()
+	var kept []int
+	for i := 1; i <= 10; i++ {
+		if s.keep(zapcore.Entry{Level: INFO, Message: "noisy", Time: now}) {
+			kept = append(kept, i)
+		}
+	}
+	// the first two, then every third
+	if want := []int{1, 2, 5, 8}; !equalInts(kept, want) {
+		t.Errorf("kept %v, want %v", kept, want)
+	}
+	if !s.keep(zapcore.Entry{Level: INFO, Message: "other", Time: now}) {
+		t.Error("the first entry of another message was dropped")
+	}
+	if !s.keep(zapcore.Entry{Level: INFO, Message: "noisy", Time: now.Add(time.Minute)}) {
+		t.Error("the first entry of the next tick was dropped")
+	}
+	if got := s.takeDropped(); len(got) != 1 || got[INFO] != 6 {
+		t.Errorf("takeDropped() = %v, want 6 INFO", got)
+	}
+	if got := s.takeDropped(); got != nil {
+		t.Errorf("takeDropped() = %v once taken, want nil", got)
+	}
+}
+
+func TestSamplerNeverDropsAudit(t *testing.T) {
+	s := newSampler(&config.Sampling{SamplingRule: config.SamplingRule{Initial: 1}, Tick: time.Minute})
+	for i := 0; i < 5; i++ {
+		if !s.keep(zapcore.Entry{Level: AUDIT, Message: "audited", Time: time.Now()}) {
+			t.Fatal("an AUDIT entry was dropped")
+		}
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSamplingReportsTheDroppedEntries(t *testing.T) {
+	s, path := newFileService(t, config.Logger{Sampling: &config.Sampling{
+		SamplingRule:   config.SamplingRule{Initial: 1, Thereafter: 10},
+		Tick:           time.Minute,
+		ReportInterval: 10 * time.Millisecond,
+	}})
+	for i := 0; i < 100; i++ {
+		s.Infoz("noisy")
+		s.Warnz("loud")
+	}
+
+	// the drops may be reported over more than one interval
+	var info, warn, total float64
+	deadline := time.Now().Add(5 * time.Second)
+	for total < 180 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		s.Sync()
+		info, warn, total = 0, 0, 0
+		for _, e := range readEntries(t, path) {
+			if e["message"] == "Dropped log entries by sampling!" {
+				n, _ := e["dropped_info"].(float64)
+				info += n
+				n, _ = e["dropped_warn"].(float64)
+				warn += n
+				total += e["dropped"].(float64)
+			}
+		}
+	}
+	// 1 kept, then every 10th: the 11th, 21st... 91st
+	if info != 90 || warn != 90 || total != 180 {
+		t.Errorf("reported %v INFO, %v WARN and %v in all dropped, want 90, 90 and 180", info, warn, total)
+	}
+
+	kept := 0
+	for _, e := range readEntries(t, path) {
+		if e["message"] == "noisy" {
+			kept++
+		}
+	}
+	if kept != 10 {
+		t.Errorf("kept %d entries, want 10", kept)
+	}
+}
+
+func TestSamplingPerLevel(t *testing.T) {
+	s, path := newFileService(t, config.Logger{LoggingLevel: "DEBUG", Sampling: &config.Sampling{
+		Levels: map[string]config.SamplingRule{"DEBUG": {Initial: 2, Thereafter: 0}, "INFO": {Initial: 1, Thereafter: 5}},
+		Tick:   time.Minute,
+	}})
+	for i := 0; i < 50; i++ {
+		s.Debugz("flood")
+		s.Infoz("chatty")
+		s.Errorz("failed")
+	}
+	s.Sync()
+
+	counts := map[string]int{}
+	for _, e := range readEntries(t, path) {
+		counts[e["message"].(string)]++
+	}
+	// INFO keeps the 1st, then the 6th, 11th... 46th
+	if counts["flood"] != 2 || counts["chatty"] != 10 || counts["failed"] != 50 {
+		t.Errorf("kept %v, want 2 DEBUG, 10 INFO and every ERROR", counts)
+	}
+}
+
+func TestSamplingLevelsReplaceTheRuleOfEveryLevel(t *testing.T) {
+	s := newSampler(&config.Sampling{
+		SamplingRule: config.SamplingRule{Initial: 1},
+		Levels:       map[string]config.SamplingRule{"ERROR": {Initial: 3}},
+		Tick:         time.Minute,
+	})
+	kept := map[zapcore.Level]int{}
+	for i := 0; i < 5; i++ {
+		for _, l := range []zapcore.Level{INFO, ERROR} {
+			if s.keep(zapcore.Entry{Level: l, Message: "m", Time: time.Now()}) {
+				kept[l]++
+			}
+		}
+	}
+	if kept[INFO] != 1 || kept[ERROR] != 3 {
+		t.Errorf("kept %d INFO and %d ERROR, want 1 and 3", kept[INFO], kept[ERROR])
+	}
+}
@@ -1,10 +1,13 @@
 package logger
 
 import (
+	"errors"
 	"fmt"
-	"net/url"
 	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/dazzling420/go-logger/config"
 	"go.uber.org/zap"
@@ -20,6 +23,8 @@ const (
 	PANIC  = zap.PanicLevel  // 4
 	FATAL  = zap.FatalLevel  // 5
 	DEBUG  = zap.DebugLevel  // -1
+	TRACE  = DEBUG - 1       // -2, zap has no trace level of its own
+	AUDIT  = FATAL + 2       // 7, above every other level so no level filters it out
 )
 
 func GetLevel(l string) zapcore.Level {
@@ -31,13 +36,15 @@ func GetLevel(l string) zapcore.Level {
 	case "ERROR":
 		return ERROR
 	case "DPANIC":
-		return PANIC
+		return DPANIC
 	case "PANIC":
 		return PANIC
 	case "FATAL":
 		return FATAL
 	case "DEBUG":
 		return DEBUG
+	case "TRACE":
+		return TRACE
 	default:
 		return INFO
 	}
@@ -93,6 +100,8 @@ var (
 	Duration    = zap.Duration
 	Durationp   = zap.Durationp
 	Any         = zap.Any
+	Err         = zap.Error
+	NamedError  = zap.NamedError
 )
 
 type Service interface {
@@ -100,6 +109,13 @@ type Service interface {
 	GetSDLogger() *zap.SugaredLogger
 	GetZapLogger() *zap.Logger
 
+	// The z methods hand their fields straight to zap's structured logger
+	// and allocate nothing of their own: an entry below the level costs a
+	// level check and the slice of its fields, if any, which escapes to the
+	// heap as it does with zap. The f and plain methods go through zap's
+	// sugared logger: their arguments are boxed into interfaces by the call
+	// and formatted with fmt, allocating for each entry that is written.
+	// Prefer the z methods on hot paths.
 	Errorf(format string, args ...interface{})
 	Error(args ...interface{})
 	Errorz(msg string, fields ...Field)
@@ -108,6 +124,14 @@ type Service interface {
 	Fatal(args ...interface{})
 	Fatalz(msg string, fields ...Field)
 
+	Panicf(format string, args ...interface{})
+	Panic(args ...interface{})
+	Panicz(msg string, fields ...Field)
+
+	DPanicf(format string, args ...interface{})
+	DPanic(args ...interface{})
+	DPanicz(msg string, fields ...Field)
+
 	Infof(format string, args ...interface{})
 	Info(args ...interface{})
 	Infoz(msg string, fields ...Field)
@@ -119,12 +143,36 @@ type Service interface {
 	Debugf(format string, args ...interface{})
 	Debug(args ...interface{})
 	Debugz(msg string, fields ...Field)
+
+	Tracef(format string, args ...interface{})
+	Trace(args ...interface{})
+	Tracez(msg string, fields ...Field)
+
+	Audit(msg string, fields ...Field)
+
+	// Printf and Println log at INFO, for libraries expecting a Printf-style
+	// logger.
+	Printf(format string, args ...interface{})
+	Println(args ...interface{})
 }
 
 // StandardLogger initializes the standard logger
 type standardLogger struct {
 	logger *zap.SugaredLogger
 	log    *zap.Logger
+
+	progress  *sync.Map // op name -> time.Time of the first Progress call
+	seen      *sync.Map // keys passed to FirstSeen
+	closers   []func() error
+	level     zap.AtomicLevel
+	logFile   *swappableSink // nil when LevelOutputs replaces LogFileName
+	sinks     *sinkSet       // the other outputs, nil when not built by NewService
+	overrides *fieldLevelOverrides
+	recent    *RingCore                      // nil unless RecentEntries is set
+	counts    *logCounts                     // nil when not built by NewService
+	conf      *atomic.Pointer[config.Logger] // the effective config, nil when not built by NewService
+
+	responseMessage bool // Error behaves like ErrorWithResponse
 }
 
 type lumberjackSink struct {
@@ -135,38 +183,35 @@ func (lumberjackSink) Sync() error {
 	return nil
 }
 
-type bufwriter chan []byte
-
-func (bw bufwriter) Write(p []byte) (int, error) {
-	bw <- p
-	return len(p), nil
-}
-
-var loggerPointer *standardLogger
-
-func SetLogger(l *standardLogger) {
-	loggerPointer = l
-}
-
-func GetLogger() *standardLogger {
-	return loggerPointer
+// newLumberjackSink returns a rotating file sink for fileName using the
+// rotation and compression settings from conf.
+func newLumberjackSink(fileName string, conf *config.Logger) lumberjackSink {
+	return lumberjackSink{
+		Logger: &lumberjack.Logger{
+			Filename:   fileName,
+			MaxSize:    conf.LogFileSizeCappingInMBs,
+			MaxBackups: conf.MaxLogBackupsCount,
+			MaxAge:     conf.MaxOldLogRetentionInDays,
+			Compress:   conf.OldLogsCompressionRequired,
+		},
+	}
 }
 
-func NewBufwriter(n int, logFile string) bufwriter {
-	w := make(bufwriter, n)
-	logwriter := &lumberjack.Logger{
-		Filename:   logFile,
-		MaxSize:    500, // megabytes
-		MaxBackups: 10,
-		MaxAge:     7, //days
-	}
-	go func(l *lumberjack.Logger, c bufwriter) {
-		for p := range c {
-			os.Stdout.Write(p)
-			l.Write(p)
+// applyKeys renames the standard keys of encoderConfig as conf asks.
+func applyKeys(encoderConfig *zapcore.EncoderConfig, conf *config.Logger) {
+	for _, k := range []struct {
+		key  *string
+		dest *string
+	}{
+		{conf.MessageKey, &encoderConfig.MessageKey},
+		{conf.LevelKey, &encoderConfig.LevelKey},
+		{conf.TimeKey, &encoderConfig.TimeKey},
+		{conf.CallerKey, &encoderConfig.CallerKey},
+	} {
+		if k.key != nil {
+			*k.dest = *k.key
 		}
-	}(logwriter, w)
-	return w
+	}
 }
 
 func getConfigFromInterface(confi interface{}) *config.Logger {
@@ -174,18 +219,14 @@ func getConfigFromInterface(confi interface{}) *config.Logger {
 	return &conf
 }
 
-// NewService initializes the standard logger
-func NewService(config interface{}) *standardLogger {
-	conf := getConfigFromInterface(config)
-
-	atom := zap.NewAtomicLevel()
-	atom.SetLevel(GetLevel(conf.LoggingLevel)) // level has been set
-
-	encoderConfig := zapcore.EncoderConfig{
+// newEncoderConfig returns the encoder settings every output of this package
+// starts from.
+func newEncoderConfig() zapcore.EncoderConfig {
+	return zapcore.EncoderConfig{
 		MessageKey: "message",
 
 		LevelKey:    "level",
-		EncodeLevel: zapcore.CapitalLevelEncoder,
+		EncodeLevel: CapitalLevelEncoder,
 
 		TimeKey:    "time",
 		EncodeTime: zapcore.ISO8601TimeEncoder,
@@ -196,53 +237,558 @@ func NewService(config interface{}) *standardLogger {
 		EncodeCaller: zapcore.FullCallerEncoder,
 		LineEnding:   zapcore.DefaultLineEnding,
 	}
+}
 
-	if strings.TrimSpace(conf.LogFileName) != "" {
-		ll := lumberjack.Logger{
-			Filename:   conf.LogFileName,
-			MaxSize:    conf.LogFileSizeCappingInMBs,
-			MaxBackups: conf.MaxLogBackupsCount,
-			MaxAge:     conf.MaxOldLogRetentionInDays,
-			Compress:   conf.OldLogsCompressionRequired,
+// encoderConfigFor returns the encoder settings of the outputs of a logger
+// configured with conf, before the encoding applies its own.
+func encoderConfigFor(conf *config.Logger) zapcore.EncoderConfig {
+	encoderConfig := newEncoderConfig()
+	applyKeys(&encoderConfig, conf)
+	encoderConfig.LineEnding = lineEnding(conf.LineEnding)
+	if conf.DisableCaller {
+		encoderConfig.CallerKey = ""
+	} else if conf.ShortCaller {
+		encoderConfig.EncodeCaller = zapcore.ShortCallerEncoder
+	} else if conf.TrimCallerPrefix != "" {
+		encoderConfig.EncodeCaller = newTrimmedCallerEncoder(conf.TrimCallerPrefix)
+	}
+	return encoderConfig
+}
+
+// defaultFields returns the fields conf asks to be added to every entry.
+func defaultFields(conf *config.Logger) []Field {
+	var fields []Field
+	if conf.ServiceName != "" {
+		fields = append(fields, zap.String("service_name", conf.ServiceName))
+	}
+	if conf.ServiceVersion != "" {
+		fields = append(fields, zap.String("service_version", conf.ServiceVersion))
+	}
+	if conf.Environment != "" {
+		fields = append(fields, zap.String("environment", conf.Environment))
+	}
+	if conf.IncludeHostPid {
+		if hostname, err := os.Hostname(); err == nil {
+			fields = append(fields, zap.String("hostname", hostname))
 		}
+		fields = append(fields, zap.Int("pid", os.Getpid()))
+	}
+	return fields
+}
+
+// NewService initializes the standard logger. opts are applied to the
+// underlying zap logger after the ones derived from config. When config can't
+// be built, see BuildService, the logger writes to stdout with the default
+// config instead and logs why.
+func NewService(conf interface{}, opts ...zap.Option) *standardLogger {
+	s, err := BuildService(conf, opts...)
+	if err != nil {
+		// the default config has no files to prepare and is valid
+		s, _ = BuildService(config.Logger{}, opts...)
+		s.log.Error("Was unable to build the logger from its config, logging to stdout!", zap.Error(err))
+	}
+	return s
+}
 
-		zap.RegisterSink("lumberjack", func(*url.URL) (zap.Sink, error) {
-			return lumberjackSink{
-				Logger: &ll,
-			}, nil
-		})
+// BuildService is NewService returning the error of config.Validate when
+// config is invalid, e.g. DisableStdout without another output, and the
+// errors creating the directories of the log files or applying LogFileMode to
+// them, which are only logged when FallbackToStderr is set.
+func BuildService(config interface{}, opts ...zap.Option) (*standardLogger, error) {
+	conf := getConfigFromInterface(config)
+	*conf = conf.WithDefaults()
+	if err := conf.Validate(); err != nil {
+		return nil, err
 	}
 
-	cfg := zap.Config{
-		Encoding:         "json",
-		Level:            atom,
-		OutputPaths:      []string{"stdout"},
-		ErrorOutputPaths: []string{"stderr"},
-		EncoderConfig:    encoderConfig,
+	atom := zap.NewAtomicLevel()
+	atom.SetLevel(GetLevel(conf.LoggingLevel)) // level has been set
+
+	encoderConfig := encoderConfigFor(conf)
+
+	counts := &logCounts{}
+	encoder := zapcore.Encoder(countingEncoder{Encoder: newEncoder(conf, encoderConfig), counts: counts})
+	// with DualEncoding the standard streams are written for people, in the
+	// console encoding, and the files for machines
+	streamEncoder := encoder
+	if conf.DualEncoding {
+		consoleConf := *conf
+		consoleConf.Encoding = EncodingConsole
+		streamEncoder = countingEncoder{Encoder: newEncoder(&consoleConf, encoderConfig), counts: counts}
 	}
 
-	if strings.TrimSpace(conf.LogFileName) != "" {
-		cfg.OutputPaths = append(cfg.OutputPaths, conf.LogFileName)
+	sinks := newSinkSet(conf)
+	sinks.header = csvHeader(conf, encoderConfig)
+	if !conf.DualEncoding {
+		sinks.streamHeader = sinks.header
 	}
 
-	logger, nerr := cfg.Build() // error shouldn't happen but still I am handling it
-	var sugar *zap.SugaredLogger
-	if nerr != nil {
-		sugar = zap.NewExample().Sugar()
-		sugar.Error("Was unable to create logger file!")
-		sugar.Error("Was unable to create desired logger, running on simple logger!")
+	var core zapcore.Core
+	var logFile *swappableSink
+	var logFileErr error
+	if len(conf.LevelOutputs) > 0 {
+		core = newLevelOutputsCore(encoder, conf, sinks)
 	} else {
-		sugar = logger.Sugar()
-		sugar.Error("Was unable to create logger file!")
+		// LogFileName is opened on its own so Reconfigure can replace it
+		logFile, logFileErr = newSwappableSink(conf, sinks.header)
+		var paths []string
+		for _, path := range conf.AdditionalLogFiles {
+			if normalizePath(path) != normalizePath(conf.LogFileName) {
+				paths = append(paths, path)
+			}
+		}
+		if conf.ErrorsToStderr {
+			core = newStdStreamsCore(streamEncoder, GetLevel(conf.StderrLevel), !conf.DisableStdout, sinks)
+			core = newTee(core, zapcore.NewCore(encoder.Clone(), zapcore.NewMultiWriteSyncer(sinks.openAll(paths), logFile), allLevels))
+		} else if conf.DualEncoding {
+			core = zapcore.NewCore(encoder, zapcore.NewMultiWriteSyncer(sinks.openAll(paths), logFile), allLevels)
+			if !conf.DisableStdout {
+				core = newTee(zapcore.NewCore(streamEncoder, sinks.open("stdout"), allLevels), core)
+			}
+		} else {
+			if !conf.DisableStdout {
+				paths = append([]string{"stdout"}, paths...)
+			}
+			core = zapcore.NewCore(encoder, zapcore.NewMultiWriteSyncer(sinks.openAll(paths), logFile), allLevels)
+		}
+	}
+
+	// errors are additionally written to their own file so they can be tailed alone
+	if strings.TrimSpace(conf.ErrorLogFileName) != "" {
+		core = newTee(core, zapcore.NewCore(encoder.Clone(), sinks.open(conf.ErrorLogFileName), errorLevels))
+	}
+
+	// audit entries are additionally written to their own file
+	if strings.TrimSpace(conf.AuditLogFileName) != "" {
+		core = newTee(core, zapcore.NewCore(encoder.Clone(), sinks.open(conf.AuditLogFileName), auditLevel))
+	}
+
+	// sinks are closed last so whatever the other closers log still gets out
+	closers := sinks.closers
+	if logFile != nil {
+		closers = append(closers, logFile.Close)
+	}
+
+	var syslogErr error
+	if strings.TrimSpace(conf.SyslogAddr) != "" {
+		var syslogCore zapcore.Core
+		var stop func() error
+		syslogCore, stop, syslogErr = newSyslogCore(conf.SyslogAddr, conf.SyslogTag, encoder.Clone(), allLevels)
+		if syslogErr == nil {
+			core = newTee(core, syslogCore)
+			closers = append(closers, stop)
+		}
+	}
+
+	var journaldErr error
+	if conf.Journald {
+		var journaldCore zapcore.Core
+		var stop func() error
+		journaldCore, stop, journaldErr = newJournaldCore("", conf.SyslogTag, allLevels)
+		if journaldErr == nil {
+			core = newTee(core, journaldCore)
+			closers = append(closers, stop)
+		}
+	}
+
+	var eventLogErr error
+	if strings.TrimSpace(conf.EventLogSource) != "" {
+		var eventLogCore zapcore.Core
+		var stop func() error
+		eventLogCore, stop, eventLogErr = newEventLogCore(conf.EventLogSource, encoder.Clone(), allLevels)
+		if eventLogErr == nil {
+			core = newTee(core, eventLogCore)
+			closers = append(closers, stop)
+		}
+	}
+
+	// the outputs above follow the level of the logger, FileSinks and the
+	// recent entries have their own, so only the former are measured
+	core = countingCore{Core: core, counts: counts}
+	if conf.StatsInterval > 0 {
+		var stop func() error
+		core, stop = newLatencyStatsCore(core, atom, conf.StatsInterval)
+		closers = append(closers, stop)
+	}
+	core = gatedCore{core}
+	fileSinkCores, stackLevel := newFileSinkCores(conf, encoderConfig, sinks)
+	independentCores := fileSinkCores
+	var recent *RingCore
+	if conf.RecentEntries > 0 {
+		recent = newRingCore(newEncoder(conf, encoderConfig), conf.RecentEntries, GetLevel(conf.RecentEntriesLevel))
+		independentCores = append(independentCores[:len(independentCores):len(independentCores)], recent)
+	}
+	if len(independentCores) > 0 {
+		core = newTee(append([]zapcore.Core{core}, independentCores...)...)
+	}
+
+	var sampler *sampler
+	if conf.Sampling != nil {
+		sampler = newSampler(conf.Sampling)
+		core = newSamplingCore(core, sampler)
+	}
+
+	if conf.SlowStackThreshold > 0 {
+		core = newSlowStackCore(core, conf.SlowStackField, conf.SlowStackThreshold)
+	}
+
+	if len(conf.QuietHours) > 0 {
+		core = newQuietCore(core, conf.QuietHours)
+	}
+
+	if conf.ErrorDemoteThreshold > 0 {
+		core = newDemoteCore(core, conf.ErrorDemoteThreshold, conf.ErrorDemoteWindow)
+	}
+
+	if conf.DedupWindow > 0 {
+		var stop func() error
+		core, stop = newDedupCore(core, conf.DedupWindow)
+		closers = append(closers, stop)
+	}
+
+	if conf.IncludeGoroutineID {
+		core = newGoroutineCore(core)
+	}
+
+	start := time.Now()
+	if conf.IncludeUptime {
+		core = newUptimeCore(core, start)
+	}
+
+	if rules := compileMaskRules(conf.MaskRules); len(rules) > 0 {
+		core = newMaskCore(core, rules)
+	}
+
+	if conf.MaxFieldLength > 0 {
+		core = newTruncateCore(core, conf.MaxFieldLength)
+	}
+
+	baseOpts := []zap.Option{
+		zap.WithCaller(!conf.DisableCaller),
+		zap.ErrorOutput(zapcore.Lock(os.Stderr)),
+		nilFieldOption(conf.NilFieldsAsNull),
+	}
+	if conf.Development {
+		baseOpts = append(baseOpts, zap.Development())
+	}
+	if fields := defaultFields(conf); len(fields) > 0 {
+		baseOpts = append(baseOpts, zap.Fields(fields...))
+	}
+	if conf.StackDedupWindow > 0 {
+		var stop func() error
+		core, stop = newStackDedupCore(core, conf.StackDedupWindow)
+		closers = append(closers, stop)
+		if ERROR < stackLevel {
+			stackLevel = ERROR
+		}
+	}
+	// stacks are captured for deduplication and FileSinks, the main outputs
+	// don't encode them
+	if stackLevel != zapcore.InvalidLevel {
+		baseOpts = append(baseOpts, zap.AddStacktrace(stackLevel))
+	}
+
+	// the level is applied last so Clone finds it on top
+	overrides := &fieldLevelOverrides{}
+	levelOpt := zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return newAtomCore(core, atom, zapcore.NewTee(independentCores...), overrides)
+	})
+	logger := zap.New(core, append(append(baseOpts, opts...), levelOpt)...)
+
+	if syslogErr != nil {
+		logger.Error("Was unable to connect to syslog, running without it!", zap.Error(syslogErr))
+	}
+	if journaldErr != nil {
+		logger.Error("Was unable to connect to journald, running without it!", zap.Error(journaldErr))
+	}
+	if eventLogErr != nil {
+		logger.Error("Was unable to open the Windows event log, running without it!", zap.Error(eventLogErr))
 	}
 
-	sugar = sugar.WithOptions(zap.AddCallerSkip(1))
-	defer sugar.Sync()
-	return &standardLogger{sugar, logger}
+	if conf.AsyncBuffer && conf.AsyncBufferDropOnFull {
+		closers = append(closers, reportDropped(logger, func() uint64 {
+			n := sinks.dropped()
+			if logFile != nil {
+				n += logFile.dropped()
+			}
+			return n
+		}, dropReportInterval))
+	}
+
+	if sampler != nil {
+		closers = append(closers, reportSampled(logger, sampler, conf.Sampling.ReportInterval))
+	}
+
+	s := newStandardLogger(logger, atom, closers)
+	s.logFile = logFile
+	s.sinks = sinks
+	s.overrides = overrides
+	s.recent = recent
+	s.counts = counts
+	s.conf = newConfigPointer(conf)
+	s.responseMessage = conf.ErrorResponseMessage
+	if err := errors.Join(append(sinks.errs, logFileErr)...); err != nil {
+		if !conf.FallbackToStderr {
+			s.Close()
+			return nil, err
+		}
+		logger.Error("Was unable to prepare the log files, falling back to stderr!", zap.Error(err))
+	}
+	if conf.IncludeUptime {
+		logger.Info("Logging started!", zap.Time("start_time", start))
+	}
+	defer s.logger.Sync()
+	return s, nil
+}
+
+// NewServiceFromCore returns a logger writing to core, for cores composed by
+// hand with sinks and encoders of their own, with the callers of entries
+// recorded. Its level starts at the lowest level core is enabled for and
+// SetLevel can only raise it above core's own levels. Nil errors and nil
+// struct pointers are dropped from the fields, as NewService does without
+// NilFieldsAsNull. opts are applied to the underlying zap logger. Close only
+// flushes core.
+func NewServiceFromCore(core zapcore.Core, opts ...zap.Option) *standardLogger {
+	// zapcore.LevelOf starts looking at DEBUG
+	level := TRACE
+	if !core.Enabled(TRACE) {
+		level = zapcore.LevelOf(core)
+	}
+	atom := zap.NewAtomicLevelAt(level)
+	overrides := &fieldLevelOverrides{}
+	levelOpt := zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return newAtomCore(core, atom, nil, overrides)
+	})
+	// entries atomCore lets through still go to the levels core is enabled for
+	core = gatedCore{levelFilteredCore{core}}
+	log := zap.New(core, append(append([]zap.Option{zap.AddCaller(), nilFieldOption(false)}, opts...), levelOpt)...)
+	s := newStandardLogger(log, atom, nil)
+	s.overrides = overrides
+	return s
+}
+
+// newStandardLogger returns a standardLogger logging through log, whose level
+// is controlled by level, running closers on Close.
+func newStandardLogger(log *zap.Logger, level zap.AtomicLevel, closers []func() error) *standardLogger {
+	// every wrapper method adds one frame, skip it so caller points at user code
+	log = log.WithOptions(zap.AddCallerSkip(1))
+	return &standardLogger{
+		logger:   log.Sugar(),
+		log:      log,
+		progress: &sync.Map{},
+		seen:     &sync.Map{},
+		closers:  closers,
+		level:    level,
+	}
+}
+
+// withLogger returns a copy of s that logs through log, sharing everything
+// else with s.
+func (s *standardLogger) withLogger(log *zap.Logger) *standardLogger {
+	child := *s
+	child.log = log
+	child.logger = log.Sugar()
+	return &child
+}
+
+// With returns a child logger carrying fields on every entry.
+func (s *standardLogger) With(fields ...Field) *standardLogger {
+	return s.withLogger(s.log.With(fields...))
+}
+
+// WithOptions returns a child logger with opts applied to its zap logger,
+// e.g. zap.Hooks, for what this package doesn't wrap. The options are applied
+// as is: they are the caller's responsibility, zap.AddCallerSkip is relative
+// to the methods of this logger and a zap.WrapCore hides its level from
+// Clone.
+func (s *standardLogger) WithOptions(opts ...zap.Option) Service {
+	return s.withLogger(s.log.WithOptions(opts...))
+}
+
+// Lazy returns a child logger carrying fields on every entry, like With,
+// but only encodes them once the child writes its first entry, to skip the
+// cost of expensive fields on loggers that mostly log below their level.
+// Entries are written when they pass the level check, so the fields are
+// encoded as soon as an entry passes it for any output, including a FileSink
+// whose level is below the one of the logger, and then only once.
+func (s *standardLogger) Lazy(fields ...Field) *standardLogger {
+	return s.withLogger(s.log.WithLazy(fields...))
+}
+
+// Clone returns a logger writing to the same outputs as s, through the same
+// cores, but with a level of its own starting at the current level of s, so
+// SetLevel on either leaves the other alone. Fields and options added to the
+// clone don't reach s either. Everything behind the level is still shared:
+// outputs with a level of their own such as FileSinks, the state of wrappers
+// like dedup windows, and Close, which closes the outputs for both.
+func (s *standardLogger) Clone() Service {
+	level := zap.NewAtomicLevelAt(s.level.Level())
+	log := s.log.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		if ac, ok := core.(*atomCore); ok {
+			return ac.withLevel(level)
+		}
+		// options added since the level was applied hide it, the clone can
+		// then only narrow the level of s
+		return newAtomCore(core, level, nil, s.overrides)
+	}))
+	clone := s.withLogger(log)
+	clone.level = level
+	clone.progress = &sync.Map{}
+	clone.seen = &sync.Map{}
+	return clone
+}
+
+// WithNamespace returns a child logger that nests all fields added after it,
+// both bound with With and passed per call, under the ns key.
+func (s *standardLogger) WithNamespace(ns string) *standardLogger {
+	return s.withLogger(s.log.With(zap.Namespace(ns)))
+}
+
+// WithError returns a child logger carrying err as a persistent "error"
+// field. A nil err adds no field.
+func (s *standardLogger) WithError(err error) Service {
+	if isNilError(err) {
+		return s
+	}
+	return s.withLogger(s.log.With(zap.Error(err)))
+}
+
+// SetLevel changes the level of the outputs following LoggingLevel, for this
+// logger and every logger sharing its outputs.
+func (s *standardLogger) SetLevel(level string) {
+	s.level.SetLevel(GetLevel(level))
+}
+
+// Reconfigure applies conf to s while it is in use: its logging level, and
+// its log file and rotation settings, the current file being flushed and
+// closed first. An empty LogFileName stops writing to a file. The other
+// settings need a new logger, and the file can't be changed when s was built
+// with LevelOutputs. Loggers sharing the level or outputs of s, such as its
+// children, see the changes too.
+func (s *standardLogger) Reconfigure(conf config.Logger) error {
+	conf = conf.WithDefaults()
+	if err := conf.Validate(); err != nil {
+		return err
+	}
+	s.SetLevel(conf.LoggingLevel)
+	if s.logFile == nil {
+		s.storeReconfigured(conf)
+		return nil
+	}
+	err := s.logFile.swap(&conf)
+	s.storeReconfigured(conf)
+	return err
+}
+
+// storeReconfigured records the settings of conf Reconfigure applied for
+// ConfigHandler.
+func (s *standardLogger) storeReconfigured(conf config.Logger) {
+	if s.conf == nil {
+		return
+	}
+	if s.logFile == nil {
+		// only the level was applied
+		old := *s.conf.Load()
+		old.LoggingLevel = conf.LoggingLevel
+		s.conf.Store(&old)
+		return
+	}
+	applied := reconfigured(*s.conf.Load(), conf)
+	s.conf.Store(&applied)
+}
+
+// WatchConfig applies the config file at path to s with Reconfigure every
+// time the file changes, see config.Watch.
+func (s *standardLogger) WatchConfig(path string) (stop func(), err error) {
+	return config.Watch(path, func(conf config.Logger) {
+		if err := s.Reconfigure(conf); err != nil {
+			s.log.Error("Was unable to apply the changed config!", zap.Error(err))
+		}
+	})
+}
+
+// Rotate moves the current log files aside, as rotation by size does, and
+// starts new ones. Circular files and the standard streams are left alone.
+func (s *standardLogger) Rotate() error {
+	var errs []error
+	if s.sinks != nil {
+		errs = append(errs, s.sinks.rotate())
+	}
+	if s.logFile != nil {
+		errs = append(errs, s.logFile.rotate())
+	}
+	return errors.Join(errs...)
+}
+
+// rotatable reports whether s writes to a file Rotate can rotate.
+func (s *standardLogger) rotatable() bool {
+	return (s.sinks != nil && len(s.sinks.rotators) > 0) || (s.logFile != nil && s.logFile.rotatable())
+}
+
+// Audit logs msg at the AUDIT level, which no level filters out, so audit
+// entries reach every output and, when AuditLogFileName is set, that file
+// too. Deduplication never drops them.
+func (s *standardLogger) Audit(msg string, fields ...Field) {
+	s.log.Log(AUDIT, msg, fields...)
+}
+
+// Stats returns the number of entries logged so far through the outputs
+// following the level of the logger, per level, and their size once encoded.
+// An entry written to several outputs, e.g. stdout and a log file, counts
+// once but its bytes count for each output. FileSinks aren't counted. Stats
+// is empty for loggers not built by NewService.
+func (s *standardLogger) Stats() Stats {
+	if s.counts == nil {
+		return Stats{}
+	}
+	return s.counts.stats()
+}
+
+// DumpRecent returns the last RecentEntries entries logged at or above
+// RecentEntriesLevel, oldest first, whatever the level of the logger, e.g.
+// to report them from a panic handler. It returns nil when RecentEntries
+// isn't set.
+func (s *standardLogger) DumpRecent() []string {
+	if s.recent == nil {
+		return nil
+	}
+	return s.recent.DumpRecent()
+}
+
+// IsEnabled reports whether an entry at level would be logged, so costly
+// fields can be skipped when it wouldn't.
+func (s *standardLogger) IsEnabled(level zapcore.Level) bool {
+	return s.log.Check(level, "") != nil
+}
+
+// DebugEnabled reports whether Debug entries are logged.
+func (s *standardLogger) DebugEnabled() bool {
+	return s.IsEnabled(DEBUG)
+}
+
+// InfoEnabled reports whether Info entries are logged.
+func (s *standardLogger) InfoEnabled() bool {
+	return s.IsEnabled(INFO)
+}
+
+// Sync flushes any buffered log entries.
+func (s *standardLogger) Sync() error {
+	return s.log.Sync()
+}
+
+// Close stops the background work started by NewService and flushes the
+// logger. The logger must not be used after Close.
+func (s *standardLogger) Close() error {
+	var errs []error
+	for i := len(s.closers) - 1; i >= 0; i-- {
+		errs = append(errs, s.closers[i]())
+	}
+	errs = append(errs, s.Sync())
+	return errors.Join(errs...)
 }
 
 func (s *standardLogger) GetZapLogger() *zap.Logger {
-	return s.log
+	return s.log.WithOptions(zap.AddCallerSkip(-1))
 }
 
 func (s *standardLogger) GetSDLogger() *zap.SugaredLogger {
@@ -257,19 +803,47 @@ func (s *standardLogger) Errorf(format string, args ...interface{}) {
 	s.logger.Errorf(format, args...)
 }
 
+// Error logs args at ERROR, like ErrorWithResponse when the logger was
+// built with ErrorResponseMessage.
 func (s *standardLogger) Error(args ...interface{}) {
-	reponseMessage := "unknown"
-	if err, ok := args[len(args)-1].(error); ok {
-		errString := err.Error()
-		fmt.Println("errString", errString)
-		args = append(args[:len(args)-1], " ", errString)
-		reponseMessage = err.Error()
+	if s.responseMessage {
+		msg, fields := responseMessage(args)
+		s.log.Error(msg, fields...)
+		return
+	}
+	s.logger.Error(args...)
+}
+
+// ErrorWithResponse logs args at ERROR with a response_message field. When
+// the last of args is an error, its message is the response_message, it is
+// appended to the message after a space and an error_detail field describes
+// its chain, see ErrorDetail. Otherwise response_message is "unknown".
+func (s *standardLogger) ErrorWithResponse(args ...interface{}) {
+	msg, fields := responseMessage(args)
+	s.log.Error(msg, fields...)
+}
+
+// responseMessage returns the message and fields ErrorWithResponse logs for
+// args.
+func responseMessage(args []interface{}) (string, []Field) {
+	err, ok := lastError(args)
+	if !ok {
+		return fmt.Sprint(args...), []Field{zap.String("response_message", "unknown")}
+	}
+	args = append(args[:len(args)-1:len(args)-1], " ", err.Error())
+	return fmt.Sprint(args...), []Field{zap.String("response_message", err.Error()), ErrorDetail(err)}
+}
+
+// lastError returns the trailing argument when it is a non-nil error.
+func lastError(args []interface{}) (error, bool) {
+	if len(args) == 0 {
+		return nil, false
+	}
+	err, ok := args[len(args)-1].(error)
+	if !ok || isNilError(err) {
+		return nil, false
 	}
-	s.logger.WithOptions(zap.Fields(zap.Field{
-		Key:    "response_message",
-		Type:   15,
-		String: reponseMessage,
-	})).Error(args...)
+	return err, true
 }
 
 func (s *standardLogger) Errorz(msg string, fields ...Field) {
@@ -288,6 +862,32 @@ func (s *standardLogger) Fatalz(msg string, fields ...Field) {
 	s.log.Fatal(msg, fields...)
 }
 
+func (s *standardLogger) Panicf(format string, args ...interface{}) {
+	s.logger.Panicf(format, args...)
+}
+
+func (s *standardLogger) Panic(args ...interface{}) {
+	s.logger.Panic(args...)
+}
+
+func (s *standardLogger) Panicz(msg string, fields ...Field) {
+	s.log.Panic(msg, fields...)
+}
+
+// DPanicf, DPanic and DPanicz log at DPANIC, which panics after logging in
+// development mode only.
+func (s *standardLogger) DPanicf(format string, args ...interface{}) {
+	s.logger.DPanicf(format, args...)
+}
+
+func (s *standardLogger) DPanic(args ...interface{}) {
+	s.logger.DPanic(args...)
+}
+
+func (s *standardLogger) DPanicz(msg string, fields ...Field) {
+	s.log.DPanic(msg, fields...)
+}
+
 func (s *standardLogger) Infof(format string, args ...interface{}) {
 	s.logger.Infof(format, args...)
 }
@@ -324,7 +924,6 @@ func (s *standardLogger) Warnz(msg string, fields ...Field) {
 }
 
 func (s *standardLogger) Debugf(format string, args ...interface{}) {
-	fmt.Println(format, args)
 	s.logger.Debugf(format, args...)
 }
 
@@ -336,11 +935,24 @@ func (s *standardLogger) Debugz(msg string, fields ...Field) {
 	s.log.Debug(msg, fields...)
 }
 
+func (s *standardLogger) Tracef(format string, args ...interface{}) {
+	s.logger.Logf(TRACE, format, args...)
+}
+
+func (s *standardLogger) Trace(args ...interface{}) {
+	s.logger.Log(TRACE, args...)
+}
+
+func (s *standardLogger) Tracez(msg string, fields ...Field) {
+	s.log.Log(TRACE, msg, fields...)
+}
+
 func (s *standardLogger) Printf(format string, args ...interface{}) {
 	s.logger.Infof(format, args...)
 }
 
+// Println logs args at INFO, always spaced as fmt.Sprintln does, without its
+// trailing newline.
 func (s *standardLogger) Println(args ...interface{}) {
-	s.logger.Info(args...)
-	s.logger.Info("\n")
+	s.logger.Infoln(args...)
 }
@@ -0,0 +1,718 @@
+package logger
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestBuildServiceRejectsInvalidConfig(t *testing.T) {
+	for name, conf := range map[string]config.Logger{
+		"disable_stdout without another output": {DisableStdout: true},
+		"unknown logging_level":                 {LoggingLevel: "LOUD"},
+		"unknown line_ending":                   {LineEnding: "cr"},
+		"invalid level color":                   {LevelColors: map[string]string{"WARN": "orange"}},
+		"invalid quiet hours":                   {QuietHours: []config.QuietHours{{Start: "25:00", End: "06:00"}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			svc, err := BuildService(conf)
+			if err == nil {
+				svc.Close()
+				t.Fatal("BuildService accepted an invalid config")
+			}
+			if svc != nil {
+				t.Error("BuildService returned a logger along with its error")
+			}
+		})
+	}
+}
+
+func TestNewService(t *testing.T) {
+	path := tempLog(t, "app.log")
+	svc := NewService(config.Logger{LogFileName: path, DisableStdout: true})
+	svc.Infoz("written")
+	svc.Close()
+
+	if got := messages(readEntries(t, path)); len(got) != 1 || got[0] != "written" {
+		t.Errorf("app.log = %q, want [written]", got)
+	}
+}
+
+func TestNewServiceFallsBackToStdout(t *testing.T) {
+	stdout := captureStdout(t, func() {
+		svc := NewService(config.Logger{DisableStdout: true})
+		svc.Infoz("rescued")
+		svc.Close()
+	})
+	if !strings.Contains(stdout, "Was unable to build the logger from its config") {
+		t.Errorf("stdout = %q, want the error reported", stdout)
+	}
+	if !strings.Contains(stdout, `"message":"rescued"`) {
+		t.Errorf("stdout = %q, want the entry", stdout)
+	}
+}
+
+func TestDisableStdoutWritesOnlyToTheFile(t *testing.T) {
+	path := tempLog(t, "app.log")
+	out := captureStdout(t, func() {
+		svc, err := BuildService(config.Logger{LogFileName: path, DisableStdout: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		svc.Infoz("to the file")
+		svc.Close()
+	})
+
+	if out != "" {
+		t.Errorf("stdout got %q, want nothing", out)
+	}
+	if got := messages(readEntries(t, path)); len(got) != 1 || got[0] != "to the file" {
+		t.Errorf("log file messages = %q, want [to the file]", got)
+	}
+}
+
+func TestStdoutIsKeptByDefault(t *testing.T) {
+	path := tempLog(t, "app.log")
+	out := captureStdout(t, func() {
+		svc, err := BuildService(config.Logger{LogFileName: path})
+		if err != nil {
+			t.Fatal(err)
+		}
+		svc.Infoz("to both")
+		svc.Close()
+	})
+
+	if !strings.Contains(out, "to both") {
+		t.Errorf("stdout got %q, want the entry", out)
+	}
+}
+
+func TestReconfigureRejectsInvalidConfig(t *testing.T) {
+	svc, err := BuildService(config.Logger{LogFileName: tempLog(t, "app.log"), DisableStdout: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer svc.Close()
+
+	if err := svc.Reconfigure(config.Logger{LoggingLevel: "LOUD"}); err == nil {
+		t.Error("Reconfigure accepted an unknown logging_level")
+	}
+	if !svc.InfoEnabled() {
+		t.Error("a rejected config changed the level")
+	}
+}
+
+func TestCallerPointsAtTheCaller(t *testing.T) {
+	svc, path := newFileService(t, config.Logger{})
+	svc.Infoz("structured")
+	svc.Infof("sugared %d", 1)
+	svc.Info("sugared")
+
+	entries := readEntries(t, path)
+	if len(entries) != 3 {
+		t.Fatalf("logged %d entries, want 3", len(entries))
+	}
+	for _, e := range entries {
+		caller, _ := e["caller"].(string)
+		if !strings.Contains(caller, "/logger/service_test.go:") {
+			t.Errorf("%v: caller = %q, want the full path of this file", e["message"], caller)
+		}
+	}
+}
+
+func TestShortCaller(t *testing.T) {
+	svc, path := newFileService(t, config.Logger{ShortCaller: true})
+	svc.Infoz("short")
+
+	entries := readEntries(t, path)
+	if len(entries) != 1 {
+		t.Fatalf("logged %d entries, want 1", len(entries))
+	}
+	if caller, _ := entries[0]["caller"].(string); !strings.HasPrefix(caller, "logger/service_test.go:") {
+		t.Errorf("caller = %q, want logger/service_test.go:<line>", caller)
+	}
+}
+
+func TestDisableCaller(t *testing.T) {
+	svc, path := newFileService(t, config.Logger{DisableCaller: true})
+	svc.Infoz("no caller")
+	svc.Errorf("still none")
+
+	entries := readEntries(t, path)
+	if len(entries) != 2 {
+		t.Fatalf("logged %d entries, want 2", len(entries))
+	}
+	for _, e := range entries {
+		if caller, ok := e["caller"]; ok {
+			t.Errorf("%v: caller = %v, want none", e["message"], caller)
+		}
+	}
+}
+
+func TestErrorLogFileName(t *testing.T) {
+	errPath := tempLog(t, "error.log")
+	svc, path := newFileService(t, config.Logger{ErrorLogFileName: errPath})
+	svc.Infoz("info")
+	svc.Warnz("warn")
+	svc.Errorz("error")
+	svc.Audit("audit")
+	svc.Sync()
+
+	if got := strings.Join(messages(readEntries(t, path)), ","); got != "info,warn,error,audit" {
+		t.Errorf("main log = %s, want every entry", got)
+	}
+	if got := strings.Join(messages(readEntries(t, errPath)), ","); got != "error" {
+		t.Errorf("error log = %s, want the ERROR entry alone", got)
+	}
+}
+
+func TestWithNamespace(t *testing.T) {
+	svc, path := newFileService(t, config.Logger{})
+	svc.With(String("service", "api")).WithNamespace("http").With(String("method", "GET")).Infoz("request", Int("status", 200))
+	svc.Infoz("outside")
+
+	entries := readEntries(t, path)
+	if len(entries) != 2 {
+		t.Fatalf("logged %d entries, want 2", len(entries))
+	}
+	http, _ := entries[0]["http"].(map[string]interface{})
+	if entries[0]["service"] != "api" || http["method"] != "GET" || http["status"] != float64(200) {
+		t.Errorf("entry = %v, want service at the top level and method and status under http", entries[0])
+	}
+	if _, ok := entries[0]["method"]; ok {
+		t.Errorf("entry = %v, method escaped the namespace", entries[0])
+	}
+	if _, ok := entries[1]["http"]; ok {
+		t.Errorf("entry = %v, the parent logger got the namespace", entries[1])
+	}
+}
+
+func TestTraceLevel(t *testing.T) {
+	for _, tt := range []struct {
+		level string
+		want  string
+	}{
+		{"TRACE", "tracez,tracef,trace,debug"},
+		{"DEBUG", "debug"},
+	} {
+		svc, path := newFileService(t, config.Logger{LoggingLevel: tt.level})
+		svc.Tracez("tracez", Int("n", 1))
+		svc.Tracef("trace%s", "f")
+		svc.Trace("trace")
+		svc.Debugz("debug")
+
+		entries := readEntries(t, path)
+		if got := strings.Join(messages(entries), ","); got != tt.want {
+			t.Errorf("at %s logged %s, want %s", tt.level, got, tt.want)
+			continue
+		}
+		if tt.level == "TRACE" && entries[0]["level"] != "TRACE" {
+			t.Errorf("level = %v, want TRACE", entries[0]["level"])
+		}
+	}
+}
+
+func TestGetLevel(t *testing.T) {
+	for name, want := range map[string]zapcore.Level{
+		"TRACE": TRACE, "DEBUG": DEBUG, "INFO": INFO, "WARN": WARN, "ERROR": ERROR,
+		"DPANIC": DPANIC, "PANIC": PANIC, "FATAL": FATAL, "unknown": INFO,
+	} {
+		if got := GetLevel(name); got != want {
+			t.Errorf("GetLevel(%q) = %v, want %v", name, got, want)
+		}
+	}
+	if LevelName(TRACE) != "TRACE" || LevelName(AUDIT) != "AUDIT" || LevelName(WARN) != "WARN" {
+		t.Errorf("LevelName = %s, %s, %s", LevelName(TRACE), LevelName(AUDIT), LevelName(WARN))
+	}
+}
+
+func TestErrFieldsAndWithError(t *testing.T) {
+	svc, path := newFileService(t, config.Logger{})
+	boom := errors.New("boom")
+	svc.Errorz("failed", Err(boom), NamedError("cause", io.EOF))
+	svc.WithError(boom).Warnz("degraded")
+	svc.WithError(nil).Warnz("fine")
+	var typedNil *os.PathError
+	svc.WithError(typedNil).Warnz("also fine")
+
+	entries := readEntries(t, path)
+	if len(entries) != 4 {
+		t.Fatalf("logged %d entries, want 4", len(entries))
+	}
+	if entries[0]["error"] != "boom" || entries[0]["cause"] != "EOF" {
+		t.Errorf("entry = %v, want error=boom cause=EOF", entries[0])
+	}
+	if entries[1]["error"] != "boom" {
+		t.Errorf("entry = %v, want the error of WithError", entries[1])
+	}
+	for _, e := range entries[2:] {
+		if _, ok := e["error"]; ok {
+			t.Errorf("entry = %v, want no error field for a nil error", e)
+		}
+	}
+}
+
+func TestDefaultFields(t *testing.T) {
+	svc, path := newFileService(t, config.Logger{
+		ServiceName:    "api",
+		ServiceVersion: "1.2.3",
+		Environment:    "staging",
+		IncludeHostPid: true,
+	})
+	svc.Infoz("started")
+	svc.Sync()
+
+	entries := readEntries(t, path)
+	if len(entries) != 1 {
+		t.Fatalf("wrote %d entries, want 1", len(entries))
+	}
+	e := entries[0]
+	hostname, _ := os.Hostname()
+	if e["service_name"] != "api" || e["service_version"] != "1.2.3" || e["environment"] != "staging" {
+		t.Errorf("entry = %v, want the service fields", e)
+	}
+	if e["hostname"] != hostname || e["pid"] != float64(os.Getpid()) {
+		t.Errorf("entry = %v, want hostname %s and pid %d", e, hostname, os.Getpid())
+	}
+}
+
+func TestDefaultFieldsLeftOutWhenUnset(t *testing.T) {
+	svc, path := newFileService(t, config.Logger{})
+	svc.Infoz("started")
+	svc.Sync()
+
+	for _, key := range []string{"service_name", "service_version", "environment", "hostname", "pid"} {
+		if v, ok := readEntries(t, path)[0][key]; ok {
+			t.Errorf("%s = %v, want it left out", key, v)
+		}
+	}
+}
+
+// recovered returns what f panicked with, nil when it did not.
+func recovered(f func()) (v interface{}) {
+	defer func() { v = recover() }()
+	f()
+	return nil
+}
+
+func TestPanic(t *testing.T) {
+	svc, path := newFileService(t, config.Logger{})
+	if v := recovered(func() { svc.Panicz("broken", zap.Int("n", 1)) }); v != "broken" {
+		t.Errorf("Panicz panicked with %v, want the message", v)
+	}
+	if v := recovered(func() { svc.Panicf("broken %d", 2) }); v != "broken 2" {
+		t.Errorf("Panicf panicked with %v, want the message", v)
+	}
+	svc.Sync()
+
+	entries := readEntries(t, path)
+	if len(entries) != 2 || entries[0]["level"] != "PANIC" || entries[0]["n"] != float64(1) || entries[1]["message"] != "broken 2" {
+		t.Errorf("entries = %v, want both panics logged", entries)
+	}
+}
+
+func TestDPanicPanicsInDevelopmentOnly(t *testing.T) {
+	svc, path := newFileService(t, config.Logger{})
+	if v := recovered(func() { svc.DPanicz("odd") }); v != nil {
+		t.Errorf("DPanicz panicked with %v outside development", v)
+	}
+	dev, devPath := newFileService(t, config.Logger{Development: true})
+	if v := recovered(func() { dev.DPanicz("odd") }); v != "odd" {
+		t.Errorf("DPanicz panicked with %v in development, want the message", v)
+	}
+	svc.Sync()
+	dev.Sync()
+
+	for _, p := range []string{path, devPath} {
+		if entries := readEntries(t, p); len(entries) != 1 || entries[0]["level"] != "DPANIC" {
+			t.Errorf("entries = %v, want the DPANIC entry", entries)
+		}
+	}
+}
+
+func TestPackageLevelPanicWithoutALogger(t *testing.T) {
+	useGlobal(t, nil)
+	if v := recovered(func() { Panicf("no %s", "logger") }); v != "no logger" {
+		t.Errorf("Panicf panicked with %v, want the message", v)
+	}
+}
+
+func TestIsEnabled(t *testing.T) {
+	svc, _ := newFileService(t, config.Logger{LoggingLevel: "INFO"})
+	if svc.DebugEnabled() || !svc.InfoEnabled() || !svc.IsEnabled(ERROR) || svc.IsEnabled(TRACE) {
+		t.Error("IsEnabled does not follow the INFO level")
+	}
+	svc.SetLevel("DEBUG")
+	if !svc.DebugEnabled() {
+		t.Error("DebugEnabled() = false after SetLevel(DEBUG)")
+	}
+}
+
+func TestCloneHasALevelOfItsOwn(t *testing.T) {
+	svc, path := newFileService(t, config.Logger{LoggingLevel: "INFO"})
+	clone := svc.Clone().(*standardLogger)
+	clone.SetLevel("DEBUG")
+	svc.Debugz("parent debug")
+	clone.Debugz("clone debug")
+	svc.SetLevel("ERROR")
+	svc.Infoz("parent info")
+	clone.Infoz("clone info")
+	clone.With(zap.String("only", "clone")).Infoz("clone field")
+	svc.Errorz("parent error")
+	svc.Sync()
+
+	entries := readEntries(t, path)
+	if got := strings.Join(messages(entries), ","); got != "clone debug,clone info,clone field,parent error" {
+		t.Errorf("messages = %s", got)
+	}
+	if last := entries[len(entries)-1]; last["only"] != nil {
+		t.Errorf("the field of the clone reached the parent: %v", last)
+	}
+}
+
+func TestWatchConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	logPath := filepath.Join(dir, "app.log")
+	write := func(level string) {
+		content := "logger:\n  logging_level: " + level + "\n  log_file_name: " + logPath + "\n  disable_stdout: true\n"
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("INFO")
+	conf, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc, err := BuildService(conf.Logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer svc.Close()
+	stop, err := svc.WatchConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	write("DEBUG")
+	deadline := time.Now().Add(5 * time.Second)
+	for !svc.DebugEnabled() {
+		if time.Now().After(deadline) {
+			t.Fatal("the changed level was not applied")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestReconfigure(t *testing.T) {
+	dir := t.TempDir()
+	first, second := filepath.Join(dir, "first.log"), filepath.Join(dir, "second.log")
+	conf := config.Logger{LogFileName: first, DisableStdout: true}
+	svc, err := BuildService(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer svc.Close()
+	child := svc.With(zap.String("child", "yes"))
+	child.Debugz("below the level")
+	child.Infoz("to first")
+
+	conf.LogFileName = second
+	conf.LoggingLevel = "DEBUG"
+	if err := svc.Reconfigure(conf); err != nil {
+		t.Fatal(err)
+	}
+	child.Debugz("to second")
+	svc.Sync()
+
+	if got := strings.Join(messages(readEntries(t, first)), ","); got != "to first" {
+		t.Errorf("first.log = %s, want to first", got)
+	}
+	if got := strings.Join(messages(readEntries(t, second)), ","); got != "to second" {
+		t.Errorf("second.log = %s, want to second", got)
+	}
+}
+
+func TestReconfigureWithoutALogFile(t *testing.T) {
+	path := tempLog(t, "app.log")
+	out := captureStdout(t, func() {
+		svc, err := BuildService(config.Logger{LogFileName: path})
+		if err != nil {
+			t.Fatal(err)
+		}
+		svc.Infoz("to both")
+		if err := svc.Reconfigure(config.Logger{}); err != nil {
+			t.Error(err)
+		}
+		svc.Infoz("to stdout")
+		svc.Close()
+	})
+
+	if got := strings.Join(messages(readEntries(t, path)), ","); got != "to both" {
+		t.Errorf("app.log = %s, want the entry before Reconfigure alone", got)
+	}
+	if !strings.Contains(out, "to both") || !strings.Contains(out, "to stdout") {
+		t.Errorf("stdout = %q, want both entries", out)
+	}
+}
+
+// countingMarshaler counts how many times it is encoded.
+type countingMarshaler struct {
+	n *int
+}
+
+func (m countingMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	*m.n++
+	enc.AddString("costly", "yes")
+	return nil
+}
+
+func TestLazy(t *testing.T) {
+	svc, path := newFileService(t, config.Logger{LoggingLevel: "INFO"})
+	var encoded int
+	child := svc.Lazy(zap.Object("expensive", countingMarshaler{&encoded}))
+	child.Debugz("below the level")
+	if encoded != 0 {
+		t.Fatalf("fields encoded %d times before an entry was written", encoded)
+	}
+	child.Infoz("first")
+	child.Infoz("second")
+	svc.Sync()
+
+	if encoded != 1 {
+		t.Errorf("fields encoded %d times, want once", encoded)
+	}
+	for _, e := range readEntries(t, path) {
+		if obj, _ := e["expensive"].(map[string]interface{}); obj["costly"] != "yes" {
+			t.Errorf("%v = %v, want the lazy field", e["message"], e)
+		}
+	}
+}
+
+func TestTrimCallerPrefix(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for prefix, want := range map[string]string{
+		wd + "/":      "service_test.go:",
+		"/elsewhere/": "logger/service_test.go:", // the short form
+	} {
+		svc, path := newFileService(t, config.Logger{TrimCallerPrefix: prefix})
+		svc.Infoz("trimmed")
+		svc.Sync()
+
+		if caller, _ := readEntries(t, path)[0]["caller"].(string); !strings.HasPrefix(caller, want) {
+			t.Errorf("caller = %q with the prefix %s, want %s<line>", caller, prefix, want)
+		}
+	}
+}
+
+func TestRenamedKeys(t *testing.T) {
+	msg, level, empty := "msg", "severity", ""
+	svc, path := newFileService(t, config.Logger{MessageKey: &msg, LevelKey: &level, TimeKey: &empty})
+	svc.Warnz("renamed")
+	svc.Sync()
+
+	e := readEntries(t, path)[0]
+	if e["msg"] != "renamed" || e["severity"] != "WARN" || e["caller"] == nil {
+		t.Errorf("entry = %v, want msg and severity, caller as is", e)
+	}
+	for _, key := range []string{"message", "level", "time"} {
+		if _, ok := e[key]; ok {
+			t.Errorf("entry has the key %s", key)
+		}
+	}
+}
+
+func TestRenamedKeysAreTheDefaultCSVColumns(t *testing.T) {
+	msg, empty := "msg", ""
+	path := tempLog(t, "app.csv")
+	svc, err := BuildService(config.Logger{LogFileName: path, DisableStdout: true, Encoding: EncodingCSV, MessageKey: &msg, TimeKey: &empty, CallerKey: &empty})
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.Infoz("hi")
+	svc.Close()
+
+	if got, want := readFile(t, path), "level,msg\nINFO,hi\n"; got != want {
+		t.Errorf("file = %q, want %q", got, want)
+	}
+}
+
+func TestPrintfAndPrintln(t *testing.T) {
+	svc, logs := NewTestLogger()
+	// a Printf-style logger, as libraries expect
+	var printer interface {
+		Printf(format string, args ...interface{})
+		Println(args ...interface{})
+	} = svc
+	printer.Printf("%d requests", 3)
+	printer.Println("served", 3, "requests")
+
+	entries := logs.AllUntimed()
+	if len(entries) != 2 {
+		t.Fatalf("logged %d entries, want 2", len(entries))
+	}
+	want := []string{"3 requests", "served 3 requests"}
+	for i, e := range entries {
+		if e.Level != INFO || e.Message != want[i] {
+			t.Errorf("entry %d = %s %q, want INFO %q", i, LevelName(e.Level), e.Message, want[i])
+		}
+		if !strings.HasSuffix(e.Caller.File, "service_test.go") {
+			t.Errorf("entry %d caller = %s, want this file", i, e.Caller.File)
+		}
+	}
+}
+
+func TestWithOptions(t *testing.T) {
+	svc, logs := NewTestLogger()
+	var hooked []string
+	child := svc.(*standardLogger).WithOptions(zap.Hooks(func(e zapcore.Entry) error {
+		hooked = append(hooked, e.Message)
+		return nil
+	}), zap.Fields(zap.String("component", "db")))
+
+	child.Infoz("structured")
+	child.Infof("sugared %d", 1)
+	svc.Infoz("parent")
+
+	if got := strings.Join(hooked, ","); got != "structured,sugared 1" {
+		t.Errorf("hook saw %s, want the entries of the child only", got)
+	}
+	entries := logs.AllUntimed()
+	if len(entries) != 3 {
+		t.Fatalf("logged %d entries, want 3", len(entries))
+	}
+	for i, e := range entries[:2] {
+		if e.ContextMap()["component"] != "db" {
+			t.Errorf("entry %d fields = %v, want component=db", i, e.ContextMap())
+		}
+		if !strings.HasSuffix(e.Caller.File, "service_test.go") {
+			t.Errorf("entry %d caller = %s, want this file", i, e.Caller.File)
+		}
+	}
+	if _, ok := entries[2].ContextMap()["component"]; ok {
+		t.Error("the options applied to the parent")
+	}
+}
+
+func TestErrorLogsPlainlyByDefault(t *testing.T) {
+	s, path := newFileService(t, config.Logger{})
+	s.Error("request failed: ", errors.New("timeout"))
+	s.Sync()
+
+	entries := readEntries(t, path)
+	if len(entries) != 1 || entries[0]["message"] != "request failed: timeout" {
+		t.Fatalf("entries = %v, want the args as the message", entries)
+	}
+	for _, key := range []string{"response_message", "error_detail"} {
+		if _, ok := entries[0][key]; ok {
+			t.Errorf("%s added without ErrorResponseMessage", key)
+		}
+	}
+}
+
+func TestErrorResponseMessage(t *testing.T) {
+	s, path := newFileService(t, config.Logger{ErrorResponseMessage: true})
+	args := []interface{}{"request failed:", errors.New("timeout")}
+	s.Error(args...)
+	s.Error("no error at hand")
+	s.Sync()
+
+	entries := readEntries(t, path)
+	if len(entries) != 2 {
+		t.Fatalf("logged %d entries, want 2", len(entries))
+	}
+	if e := entries[0]; e["message"] != "request failed: timeout" || e["response_message"] != "timeout" {
+		t.Errorf("entry = %v, want the error as the response_message", e)
+	}
+	if e := entries[1]; e["message"] != "no error at hand" || e["response_message"] != "unknown" {
+		t.Errorf("entry = %v, want an unknown response_message", e)
+	}
+	if len(args) != 2 || args[0] != "request failed:" {
+		t.Errorf("args = %v, changed by Error", args)
+	}
+}
+
+func TestErrorWithResponse(t *testing.T) {
+	svc, logs := NewTestLogger()
+	s := svc.(*standardLogger)
+	var nilErr *os.PathError
+	s.ErrorWithResponse("open:", nilErr)
+	s.ErrorWithResponse("open:", &os.PathError{Op: "open", Path: "/etc/app", Err: os.ErrNotExist})
+
+	entries := logs.AllUntimed()
+	if len(entries) != 2 {
+		t.Fatalf("logged %d entries, want 2", len(entries))
+	}
+	if got := entries[0].ContextMap()["response_message"]; got != "unknown" {
+		t.Errorf("response_message = %v for a nil error, want unknown", got)
+	}
+	e := entries[1]
+	if e.Level != ERROR || e.Message != "open: open /etc/app: file does not exist" || e.ContextMap()["response_message"] != "open /etc/app: file does not exist" {
+		t.Errorf("entry = %s %q %v", LevelName(e.Level), e.Message, e.ContextMap())
+	}
+	if !strings.HasSuffix(e.Caller.File, "service_test.go") {
+		t.Errorf("caller = %s, want this file", e.Caller.File)
+	}
+}
+
+func TestNewServiceFromCore(t *testing.T) {
+	core, logs := observer.New(allLevels)
+	s := NewServiceFromCore(core, zap.Fields(zap.String("app", "api")))
+
+	s.Tracez("tracez")
+	s.Debugf("debug%s", "f")
+	s.Info("info")
+	s.Warnz("warnz")
+	s.Errorz("errorz")
+	s.Audit("audit")
+	s.Printf("printf")
+
+	want := []string{"TRACE tracez", "DEBUG debugf", "INFO info", "WARN warnz", "ERROR errorz", "AUDIT audit", "INFO printf"}
+	entries := logs.AllUntimed()
+	if len(entries) != len(want) {
+		t.Fatalf("logged %d entries, want %d", len(entries), len(want))
+	}
+	for i, e := range entries {
+		if got := LevelName(e.Level) + " " + e.Message; got != want[i] {
+			t.Errorf("entry %d = %s, want %s", i, got, want[i])
+		}
+		if !strings.HasSuffix(e.Caller.File, "service_test.go") {
+			t.Errorf("entry %d caller = %s, want this file", i, e.Caller.File)
+		}
+		if e.ContextMap()["app"] != "api" {
+			t.Errorf("entry %d fields = %v, want the options applied", i, e.ContextMap())
+		}
+	}
+}
+
+func TestNewServiceFromCoreKeepsTheLevelsOfTheCore(t *testing.T) {
+	core, logs := observer.New(WARN)
+	s := NewServiceFromCore(core)
+	if got := s.level.Level(); got != WARN {
+		t.Errorf("level = %s, want the level of the core", LevelName(got))
+	}
+
+	s.SetLevel("DEBUG")
+	s.Infoz("below the core")
+	s.SetLevel("ERROR")
+	s.Warnz("below the logger")
+	s.Errorz("kept")
+
+	if got := observedMessages(logs); len(got) != 1 || got[0] != "kept" {
+		t.Errorf("logged %q, want only the ERROR entry", got)
+	}
+}
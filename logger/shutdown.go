@@ -0,0 +1,17 @@
+package logger
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Shutdown logs a final shutdown entry at INFO recording how long the
+// shutdown took and how many in-flight entries were flushed. Call it once
+// draining is done, right before Close.
+func (s *standardLogger) Shutdown(dur time.Duration, flushed int) {
+	s.log.Info("shutdown",
+		zap.Duration("shutdown_duration", dur),
+		zap.Int("flushed", flushed),
+	)
+}
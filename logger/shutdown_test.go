@@ -0,0 +1,21 @@
+package logger
+
+import (
+	"testing"
+	"time"
+)
+
+func TestShutdown(t *testing.T) {
+	svc, logs := NewTestLogger()
+	svc.(*standardLogger).Shutdown(1500*time.Millisecond, 3)
+
+	entries := logs.AllUntimed()
+	if len(entries) != 1 {
+		t.Fatalf("logged %d entries, want 1", len(entries))
+	}
+	e := entries[0]
+	f := e.ContextMap()
+	if e.Level != INFO || e.Message != "shutdown" || f["shutdown_duration"] != 1500*time.Millisecond || f["flushed"] != int64(3) {
+		t.Errorf("logged %s %q %v, want INFO shutdown with its duration and flushed count", LevelName(e.Level), e.Message, f)
+	}
+}
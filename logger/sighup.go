@@ -0,0 +1,44 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"go.uber.org/zap"
+)
+
+// HandleSIGHUP rotates the log files of svc, see Rotate, every time the
+// process receives SIGHUP, which lets external tools like logrotate ask for a
+// new file. It does nothing when svc writes to no file. stop removes the
+// handler.
+func HandleSIGHUP(svc Service) (stop func()) {
+	s := svc.GetLogger()
+	if !s.rotatable() {
+		return func() {}
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range signals {
+			if err := s.Rotate(); err != nil {
+				s.log.Error("Was unable to rotate the log files!", zap.Error(err))
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(signals)
+			<-done
+		})
+	}
+}
@@ -0,0 +1,46 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/dazzling420/go-logger/config"
+)
+
+func TestHandleSIGHUP(t *testing.T) {
+	svc, path := newFileService(t, config.Logger{})
+	stop := HandleSIGHUP(svc)
+	defer stop()
+
+	svc.Infoz("before")
+	svc.Sync()
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if files, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "*.log")); len(files) == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("the log file was not rotated on SIGHUP")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	svc.Infoz("after")
+	svc.Sync()
+
+	if got := messages(readEntries(t, path)); len(got) != 1 || got[0] != "after" {
+		t.Errorf("app.log = %q, want [after]", got)
+	}
+}
+
+func TestHandleSIGHUPWithoutAFile(t *testing.T) {
+	svc, _ := NewTestLogger()
+	stop := HandleSIGHUP(svc)
+	stop()
+}
@@ -0,0 +1,9 @@
+//go:build windows || plan9
+
+package logger
+
+// HandleSIGHUP does nothing, there is no SIGHUP on this platform. Call Rotate
+// to rotate the log files instead.
+func HandleSIGHUP(svc Service) (stop func()) {
+	return func() {}
+}
@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithSlowStack returns an option, for NewService or zap loggers, that adds a
+// stacktrace field to every entry whose duration field named field exceeds
+// threshold, to show where a slow operation was waiting. Only fields passed
+// with the entry are inspected, not those bound with With.
+func WithSlowStack(field string, threshold time.Duration) zap.Option {
+	return zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return newSlowStackCore(core, field, threshold)
+	})
+}
+
+func newSlowStackCore(core zapcore.Core, field string, threshold time.Duration) zapcore.Core {
+	return &slowStackCore{Core: core, field: field, threshold: threshold}
+}
+
+type slowStackCore struct {
+	zapcore.Core
+	field     string
+	threshold time.Duration
+}
+
+func (c *slowStackCore) With(fields []zapcore.Field) zapcore.Core {
+	return &slowStackCore{Core: c.Core.With(fields), field: c.field, threshold: c.threshold}
+}
+
+func (c *slowStackCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *slowStackCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if c.slow(fields) {
+		fields = append(fields[:len(fields):len(fields)], zap.String("stacktrace", callerStack()))
+	}
+	return c.Core.Write(ent, fields)
+}
+
+func (c *slowStackCore) slow(fields []zapcore.Field) bool {
+	for _, f := range fields {
+		if f.Key == c.field && f.Type == zapcore.DurationType {
+			return time.Duration(f.Integer) > c.threshold
+		}
+	}
+	return false
+}
+
+// callerStack formats the stack of the calling goroutine like zap does,
+// leaving out the frames of zap and this package that lead to the write.
+func callerStack() string {
+	pcs := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+
+	var sb strings.Builder
+	skipping := true
+	for {
+		frame, more := frames.Next()
+		if skipping && isLoggingFrame(frame.Function) {
+			if !more {
+				break
+			}
+			continue
+		}
+		skipping = false
+		if sb.Len() > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(frame.Function)
+		sb.WriteString("\n\t")
+		sb.WriteString(frame.File)
+		sb.WriteByte(':')
+		sb.WriteString(strconv.Itoa(frame.Line))
+		if !more {
+			break
+		}
+	}
+	return sb.String()
+}
+
+func isLoggingFrame(function string) bool {
+	return strings.HasPrefix(function, "go.uber.org/zap.") ||
+		strings.HasPrefix(function, "go.uber.org/zap/zapcore.") ||
+		strings.HasPrefix(function, "github.com/dazzling420/go-logger/logger.")
+}
@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap"
+)
+
+func TestSlowStackThreshold(t *testing.T) {
+	svc, path := newFileService(t, config.Logger{SlowStackThreshold: time.Second})
+	svc.Infoz("slow", zap.Duration("duration", 2*time.Second))
+	svc.Infoz("fast", zap.Duration("duration", time.Millisecond))
+	svc.Infoz("other field", zap.Duration("elapsed", time.Hour))
+	svc.Sync()
+
+	entries := readEntries(t, path)
+	if len(entries) != 3 {
+		t.Fatalf("wrote %d entries, want 3", len(entries))
+	}
+	stack, _ := entries[0]["stacktrace"].(string)
+	if stack == "" {
+		t.Fatalf("slow entry = %v, want a stacktrace", entries[0])
+	}
+	if strings.HasPrefix(stack, "go.uber.org/zap") {
+		t.Errorf("the stacktrace starts in zap:\n%s", stack)
+	}
+	for _, e := range entries[1:] {
+		if _, ok := e["stacktrace"]; ok {
+			t.Errorf("%v has a stacktrace", e["message"])
+		}
+	}
+}
+
+func TestWithSlowStack(t *testing.T) {
+	svc, path := newFileService(t, config.Logger{}, WithSlowStack("elapsed", time.Second))
+	svc.Infoz("slow", zap.Duration("elapsed", 2*time.Second))
+	svc.Infoz("default field", zap.Duration("duration", time.Hour))
+	svc.Sync()
+
+	entries := readEntries(t, path)
+	if len(entries) != 2 {
+		t.Fatalf("wrote %d entries, want 2", len(entries))
+	}
+	if _, ok := entries[0]["stacktrace"]; !ok {
+		t.Errorf("slow entry = %v, want a stacktrace", entries[0])
+	}
+	if _, ok := entries[1]["stacktrace"]; ok {
+		t.Errorf("%v has a stacktrace", entries[1])
+	}
+}
@@ -0,0 +1,33 @@
+package logger
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func newSpanID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// StartSpan logs the start of a span named name and returns its generated id
+// along with an end function. Calling end logs the end of the span with the
+// same span_id and parent_span_id plus the elapsed duration.
+func (s *standardLogger) StartSpan(name, parentID string) (spanID string, end func(fields ...Field)) {
+	spanID = newSpanID()
+	spanFields := []Field{zap.String("span", name), zap.String("span_id", spanID)}
+	if parentID != "" {
+		spanFields = append(spanFields, zap.String("parent_span_id", parentID))
+	}
+	log := s.log.With(spanFields...)
+	start := time.Now()
+	log.Info("span started")
+
+	return spanID, func(fields ...Field) {
+		log.Info("span ended", append(fields[:len(fields):len(fields)], zap.Duration("duration", time.Since(start)))...)
+	}
+}
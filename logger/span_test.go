@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestStartSpan(t *testing.T) {
+	svc, logs := NewTestLogger()
+	log := svc.(*standardLogger)
+
+	parentID, endParent := log.StartSpan("request", "")
+	childID, endChild := log.StartSpan("query", parentID)
+	endChild(String("table", "users"))
+	endParent()
+
+	if !regexp.MustCompile(`^[0-9a-f]{16}$`).MatchString(parentID) || childID == parentID {
+		t.Fatalf("span ids = %q and %q, want two distinct 16 hex digit ids", parentID, childID)
+	}
+
+	entries := logs.AllUntimed()
+	if len(entries) != 4 {
+		t.Fatalf("logged %d entries, want 4", len(entries))
+	}
+	for i, want := range []struct {
+		message, span, id, parent string
+	}{
+		{"span started", "request", parentID, ""},
+		{"span started", "query", childID, parentID},
+		{"span ended", "query", childID, parentID},
+		{"span ended", "request", parentID, ""},
+	} {
+		e := entries[i]
+		fields := e.ContextMap()
+		parent, hasParent := fields["parent_span_id"]
+		if e.Message != want.message || fields["span"] != want.span || fields["span_id"] != want.id ||
+			hasParent != (want.parent != "") || (hasParent && parent != want.parent) {
+			t.Errorf("entry %d = %q %v, want %q for span %s id %s parent %q", i, e.Message, fields, want.message, want.span, want.id, want.parent)
+		}
+	}
+
+	ended := entries[2].ContextMap()
+	if ended["table"] != "users" {
+		t.Errorf("end fields = %v, want table=users", ended)
+	}
+	if _, ok := ended["duration"].(time.Duration); !ok {
+		t.Errorf("end fields = %v, want a duration", ended)
+	}
+	if _, ok := entries[0].ContextMap()["duration"]; ok {
+		t.Error("the start entry has a duration")
+	}
+}
+
+func TestStartSpanLeavesTheFieldsPassedAlone(t *testing.T) {
+	svc, _ := NewTestLogger()
+	_, end := svc.(*standardLogger).StartSpan("request", "")
+	fields := make([]Field, 1, 2)
+	fields[0] = String("table", "users")
+	end(fields...)
+
+	if spare := fields[:2][1]; spare.Key != "" {
+		t.Errorf("end wrote %s past the fields passed", spare.Key)
+	}
+}
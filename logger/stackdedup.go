@@ -0,0 +1,120 @@
+package logger
+
+import (
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// stackDedup tracks the stacktraces seen within the current window.
+type stackDedup struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	pending map[uint64]*stackWindow
+	closed  bool
+}
+
+// stackWindow is an open suppression window for one stacktrace.
+type stackWindow struct {
+	core       zapcore.Core // core that wrote the first entry
+	entry      zapcore.Entry
+	below      bool // whether the first entry was below the level of the logger
+	suppressed int
+	timer      *time.Timer
+}
+
+// newStackDedupCore wraps core so that entries carrying a stacktrace already
+// seen within window are dropped. When the window of a stacktrace closes and
+// entries were dropped, the first entry's message is logged again with a
+// suppressed_count field. The returned function closes all open windows.
+func newStackDedupCore(core zapcore.Core, window time.Duration) (zapcore.Core, func() error) {
+	sd := &stackDedup{window: window, pending: map[uint64]*stackWindow{}}
+	return &stackDedupCore{Core: core, dedup: sd}, sd.close
+}
+
+// seen reports whether the stack of ent is already in an open window, opening
+// one otherwise.
+func (sd *stackDedup) seen(core zapcore.Core, ent zapcore.Entry, fields []zapcore.Field) bool {
+	h := fnv.New64a()
+	h.Write([]byte(ent.Stack))
+	key := h.Sum64()
+
+	sd.mu.Lock()
+	defer sd.mu.Unlock()
+	if sd.closed {
+		return false
+	}
+	if w, ok := sd.pending[key]; ok {
+		w.suppressed++
+		return true
+	}
+	w := &stackWindow{core: core, entry: ent, below: isBelowLevel(fields)}
+	w.timer = time.AfterFunc(sd.window, func() { sd.expire(key) })
+	sd.pending[key] = w
+	return false
+}
+
+func (sd *stackDedup) expire(key uint64) {
+	sd.mu.Lock()
+	w, ok := sd.pending[key]
+	delete(sd.pending, key)
+	sd.mu.Unlock()
+	if ok {
+		w.report()
+	}
+}
+
+func (sd *stackDedup) close() error {
+	sd.mu.Lock()
+	pending := sd.pending
+	sd.pending = map[uint64]*stackWindow{}
+	sd.closed = true
+	sd.mu.Unlock()
+
+	for _, w := range pending {
+		w.timer.Stop()
+		w.report()
+	}
+	return nil
+}
+
+func (w *stackWindow) report() {
+	if w.suppressed == 0 {
+		return
+	}
+	ent := w.entry
+	ent.Time = time.Now()
+	fields := []zapcore.Field{zap.Int("suppressed_count", w.suppressed)}
+	// the report reaches the outputs the first entry did, no others
+	if w.below {
+		fields = append(fields, belowLevelField)
+	}
+	w.core.Write(ent, fields)
+}
+
+type stackDedupCore struct {
+	zapcore.Core
+	dedup *stackDedup
+}
+
+func (c *stackDedupCore) With(fields []zapcore.Field) zapcore.Core {
+	return &stackDedupCore{Core: c.Core.With(fields), dedup: c.dedup}
+}
+
+func (c *stackDedupCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *stackDedupCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if ent.Level >= ERROR && ent.Level != AUDIT && ent.Stack != "" && c.dedup.seen(c.Core, ent, fields) {
+		return nil
+	}
+	return c.Core.Write(ent, fields)
+}
@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestStackDedupSuppressesRepeatedStacks(t *testing.T) {
+	obs, logs := observer.New(allLevels)
+	core, closeDedup := newStackDedupCore(obs, time.Hour)
+	log := zap.New(core)
+
+	const stack = "main.f\n\tmain.go:1"
+	for i := 0; i < 3; i++ {
+		if ce := log.Check(ERROR, "failed"); ce != nil {
+			ce.Entry.Stack = stack
+			ce.Write()
+		}
+	}
+	// a different stack opens its own window
+	if ce := log.Check(ERROR, "failed elsewhere"); ce != nil {
+		ce.Entry.Stack = "main.g\n\tmain.go:2"
+		ce.Write()
+	}
+	closeDedup()
+
+	if n := logs.FilterMessage("failed").Len(); n != 2 {
+		t.Errorf("%d entries of the repeated stack, want the first and the report", n)
+	}
+	if n := logs.FilterField(zap.Int("suppressed_count", 2)).Len(); n != 1 {
+		t.Errorf("%d reports with suppressed_count 2, want 1", n)
+	}
+	if n := logs.FilterMessage("failed elsewhere").Len(); n != 1 {
+		t.Errorf("%d entries of the other stack, want 1", n)
+	}
+}
+
+func TestStackDedupReportKeepsBelowLevelEntriesOutOfMainOutput(t *testing.T) {
+	mainLog := tempLog(t, "main.log")
+	allLog := tempLog(t, "all.log")
+	svc, err := BuildService(config.Logger{
+		LogFileName:      mainLog,
+		LoggingLevel:     "FATAL",
+		DisableStdout:    true,
+		StackDedupWindow: time.Hour,
+		FileSinks:        []config.FileSink{{FileName: allLog, Level: "DEBUG"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		svc.Errorz("failed")
+	}
+	svc.Close()
+
+	if entries := readEntries(t, mainLog); len(entries) != 0 {
+		t.Errorf("ERROR entries in the FATAL main output: %v", entries)
+	}
+	var reports int
+	for _, e := range readEntries(t, allLog) {
+		if e["suppressed_count"] == float64(1) {
+			reports++
+		}
+	}
+	if reports != 1 {
+		t.Errorf("%d reports in the file sink, want 1", reports)
+	}
+}
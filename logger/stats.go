@@ -0,0 +1,133 @@
+package logger
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxLatencySamples bounds the number of write latencies kept per interval,
+// older samples are overwritten once it is reached.
+const maxLatencySamples = 4096
+
+// latencyStats collects write latencies and reports them periodically.
+type latencyStats struct {
+	core  zapcore.Core         // unwrapped core the report is written to
+	level zapcore.LevelEnabler // level of the logger, which outputs don't apply
+
+	mu      sync.Mutex
+	samples []time.Duration
+	next    int
+	count   int64
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// newLatencyStatsCore wraps core so the time spent writing each entry is
+// sampled and a logger_stats entry with the p50/p99 latencies is logged every
+// interval. The report is written straight to core so it is never measured
+// itself, and only while level, that of the logger, enables INFO: the outputs
+// of core leave the level to the logger. The returned stop function ends the
+// reporting.
+func newLatencyStatsCore(core zapcore.Core, level zapcore.LevelEnabler, interval time.Duration) (zapcore.Core, func() error) {
+	ls := &latencyStats{
+		core:    core,
+		level:   level,
+		samples: make([]time.Duration, 0, maxLatencySamples),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go ls.run(interval)
+	return &latencyStatsCore{Core: core, stats: ls}, ls.close
+}
+
+func (ls *latencyStats) record(d time.Duration) {
+	ls.mu.Lock()
+	if len(ls.samples) < maxLatencySamples {
+		ls.samples = append(ls.samples, d)
+	} else {
+		ls.samples[ls.next] = d
+		ls.next = (ls.next + 1) % maxLatencySamples
+	}
+	ls.count++
+	ls.mu.Unlock()
+}
+
+func (ls *latencyStats) run(interval time.Duration) {
+	defer close(ls.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			ls.report()
+		case <-ls.stop:
+			return
+		}
+	}
+}
+
+func (ls *latencyStats) report() {
+	ls.mu.Lock()
+	samples := make([]time.Duration, len(ls.samples))
+	copy(samples, ls.samples)
+	count := ls.count
+	ls.samples, ls.next, ls.count = ls.samples[:0], 0, 0
+	ls.mu.Unlock()
+
+	if count == 0 || !ls.level.Enabled(INFO) {
+		return
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	ls.core.Write(zapcore.Entry{
+		Level:   INFO,
+		Time:    time.Now(),
+		Message: "logger_stats",
+	}, []zapcore.Field{
+		zap.Int64("writes", count),
+		zap.Duration("write_latency_p50", percentile(samples, 50)),
+		zap.Duration("write_latency_p99", percentile(samples, 99)),
+	})
+}
+
+func (ls *latencyStats) close() error {
+	ls.stopOnce.Do(func() { close(ls.stop) })
+	<-ls.done
+	return nil
+}
+
+// percentile returns the p-th percentile of the sorted durations.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	return sorted[(len(sorted)-1)*p/100]
+}
+
+type latencyStatsCore struct {
+	zapcore.Core
+	stats *latencyStats
+}
+
+func (c *latencyStatsCore) With(fields []zapcore.Field) zapcore.Core {
+	return &latencyStatsCore{Core: c.Core.With(fields), stats: c.stats}
+}
+
+func (c *latencyStatsCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *latencyStatsCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	start := time.Now()
+	err := c.Core.Write(ent, fields)
+	c.stats.record(time.Since(start))
+	return err
+}
@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLatencyStatsReport(t *testing.T) {
+	obs, logs := observer.New(allLevels)
+	core, stop := newLatencyStatsCore(obs, zap.NewAtomicLevelAt(INFO), time.Hour)
+	defer stop()
+	log := zap.New(core)
+
+	log.Info("one")
+	log.Info("two")
+	core.(*latencyStatsCore).stats.report()
+
+	reports := logs.FilterMessage("logger_stats").All()
+	if len(reports) != 1 {
+		t.Fatalf("%d logger_stats entries, want 1", len(reports))
+	}
+	fields := reports[0].ContextMap()
+	if fields["writes"] != int64(2) {
+		t.Errorf("writes = %v, want 2", fields["writes"])
+	}
+	for _, key := range []string{"write_latency_p50", "write_latency_p99"} {
+		if _, ok := fields[key]; !ok {
+			t.Errorf("report has no %s", key)
+		}
+	}
+
+	// nothing was written since, so there is nothing to report
+	core.(*latencyStatsCore).stats.report()
+	if n := logs.FilterMessage("logger_stats").Len(); n != 1 {
+		t.Errorf("%d logger_stats entries after an idle interval, want 1", n)
+	}
+}
+
+func TestLatencyStatsFollowsTheLevelOfTheLogger(t *testing.T) {
+	for _, tt := range []struct {
+		level  string
+		report bool
+	}{
+		{"INFO", true},
+		{"WARN", false},
+	} {
+		t.Run(tt.level, func(t *testing.T) {
+			mainLog := tempLog(t, "main.log")
+			sinkLog := tempLog(t, "sink.log")
+			svc, err := BuildService(config.Logger{
+				LogFileName:   mainLog,
+				LoggingLevel:  tt.level,
+				DisableStdout: true,
+				StatsInterval: 5 * time.Millisecond,
+				FileSinks:     []config.FileSink{{FileName: sinkLog, Level: "DEBUG"}},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 5; i++ {
+				svc.Warnz("busy")
+				svc.Debugz("only in the sink")
+				time.Sleep(5 * time.Millisecond)
+			}
+			svc.Close()
+
+			var reported bool
+			var writes float64
+			for _, e := range readEntries(t, mainLog) {
+				if e["message"] == "logger_stats" {
+					reported = true
+					writes += e["writes"].(float64)
+				}
+			}
+			if reported != tt.report {
+				t.Errorf("logger_stats written = %v, want %v", reported, tt.report)
+			}
+			// the entries only the FileSink writes aren't measured
+			if writes > 5 {
+				t.Errorf("reported %v writes, want at most the 5 entries of the main log", writes)
+			}
+			for _, msg := range messages(readEntries(t, sinkLog)) {
+				if msg == "logger_stats" {
+					t.Error("logger_stats written to the FileSink")
+				}
+			}
+		})
+	}
+}
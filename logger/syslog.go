@@ -0,0 +1,84 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"log/syslog"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// syslogCore forwards encoded entries to a syslog daemon, mapping each level
+// to the matching syslog severity.
+type syslogCore struct {
+	zapcore.LevelEnabler
+	enc zapcore.Encoder
+	w   *syslog.Writer
+}
+
+// newSyslogCore connects to the syslog daemon at addr, either "network://host:port"
+// (udp, tcp), a bare "host:port" using udp or "local" for the local daemon.
+// The returned function closes the connection.
+func newSyslogCore(addr, tag string, enc zapcore.Encoder, enab zapcore.LevelEnabler) (zapcore.Core, func() error, error) {
+	network, raddr := "udp", addr
+	if i := strings.Index(addr, "://"); i >= 0 {
+		network, raddr = addr[:i], addr[i+3:]
+	}
+	if addr == "local" {
+		network, raddr = "", ""
+	}
+
+	w, err := syslog.Dial(network, raddr, syslog.LOG_INFO|syslog.LOG_USER, tag)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &syslogCore{LevelEnabler: enab, enc: enc, w: w}, w.Close, nil
+}
+
+func (c *syslogCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return &syslogCore{LevelEnabler: c.LevelEnabler, enc: enc, w: c.w}
+}
+
+func (c *syslogCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *syslogCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	msg := strings.TrimRight(buf.String(), "\r\n")
+	buf.Free()
+
+	switch {
+	case ent.Level <= DEBUG:
+		return c.w.Debug(msg)
+	case ent.Level == INFO:
+		return c.w.Info(msg)
+	case ent.Level == WARN:
+		return c.w.Warning(msg)
+	case ent.Level == ERROR:
+		return c.w.Err(msg)
+	case ent.Level == DPANIC:
+		return c.w.Crit(msg)
+	case ent.Level == PANIC:
+		return c.w.Alert(msg)
+	case ent.Level == AUDIT:
+		return c.w.Notice(msg)
+	default:
+		return c.w.Emerg(msg)
+	}
+}
+
+func (c *syslogCore) Sync() error {
+	return nil
+}
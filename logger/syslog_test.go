@@ -0,0 +1,99 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap/zapcore"
+)
+
+// listenSyslog returns the address of a UDP syslog daemon and the messages it
+// receives.
+func listenSyslog(t *testing.T) (string, <-chan string) {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	msgs := make(chan string, 16)
+	go func() {
+		buf := make([]byte, 64<<10)
+		for {
+			n, _, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			msgs <- string(buf[:n])
+		}
+	}()
+	return "udp://" + conn.LocalAddr().String(), msgs
+}
+
+func receive(t *testing.T, msgs <-chan string) string {
+	t.Helper()
+	select {
+	case msg := <-msgs:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("no syslog message received")
+		return ""
+	}
+}
+
+func TestSyslogSeverities(t *testing.T) {
+	addr, msgs := listenSyslog(t)
+	core, closeSyslog, err := newSyslogCore(addr, "app", zapcore.NewJSONEncoder(newEncoderConfig()), allLevels)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closeSyslog()
+
+	// the priority is the user facility (1) times 8 plus the severity
+	for _, tt := range []struct {
+		level    zapcore.Level
+		priority string
+	}{
+		{TRACE, "<15>"},
+		{DEBUG, "<15>"},
+		{INFO, "<14>"},
+		{WARN, "<12>"},
+		{ERROR, "<11>"},
+		{DPANIC, "<10>"},
+		{PANIC, "<9>"},
+		{FATAL, "<8>"},
+		{AUDIT, "<13>"},
+	} {
+		if err := core.Write(zapcore.Entry{Level: tt.level, Message: "at " + LevelName(tt.level)}, nil); err != nil {
+			t.Fatal(err)
+		}
+		msg := receive(t, msgs)
+		if !strings.HasPrefix(msg, tt.priority) || !strings.Contains(msg, " app[") {
+			t.Errorf("%s: message = %q, want priority %s and tag app", LevelName(tt.level), msg, tt.priority)
+		}
+		if !strings.HasSuffix(strings.TrimRight(msg, "\n"), `"message":"at `+LevelName(tt.level)+`"}`) {
+			t.Errorf("%s: message = %q, want the JSON entry without its line ending", LevelName(tt.level), msg)
+		}
+	}
+}
+
+func TestSyslogAddr(t *testing.T) {
+	addr, msgs := listenSyslog(t)
+	svc, err := BuildService(config.Logger{SyslogAddr: addr, SyslogTag: "svc", DisableStdout: true, LoggingLevel: "WARN"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer svc.Close()
+
+	svc.Infoz("below the level")
+	svc.With(String("user", "bob")).Warnz("careful")
+	msg := receive(t, msgs)
+	if !strings.HasPrefix(msg, "<12>") || !strings.Contains(msg, "svc[") || !strings.Contains(msg, `"user":"bob"`) || !strings.Contains(msg, "careful") {
+		t.Errorf("message = %q, want the WARN entry with its context", msg)
+	}
+}
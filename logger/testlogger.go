@@ -0,0 +1,25 @@
+package logger
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// NewTestLogger returns a Service recording its entries in memory instead of
+// writing them anywhere, for tests of code that logs. Every level is recorded
+// until changed with SetLevel. Nil errors and nil struct pointers are dropped
+// from the fields as by NewService. opts are applied to the underlying zap
+// logger.
+func NewTestLogger(opts ...zap.Option) (Service, *observer.ObservedLogs) {
+	atom := zap.NewAtomicLevelAt(TRACE)
+	overrides := &fieldLevelOverrides{}
+	core, logs := observer.New(allLevels)
+	levelOpt := zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return newAtomCore(core, atom, nil, overrides)
+	})
+	log := zap.New(gatedCore{core}, append(append([]zap.Option{zap.AddCaller(), nilFieldOption(false)}, opts...), levelOpt)...)
+	s := newStandardLogger(log, atom, nil)
+	s.overrides = overrides
+	return s, logs
+}
@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestNewTestLogger(t *testing.T) {
+	svc, logs := NewTestLogger(zap.Fields(zap.String("test", t.Name())))
+	svc.Tracez("trace")
+	svc.Infoz("info", zap.Int("n", 1))
+	svc.(*standardLogger).SetLevel("WARN")
+	svc.Infoz("below the level")
+	svc.Warnz("warn")
+
+	entries := logs.AllUntimed()
+	if len(entries) != 3 {
+		t.Fatalf("recorded %d entries, want 3", len(entries))
+	}
+	for i, want := range []string{"trace", "info", "warn"} {
+		e := entries[i]
+		if e.Message != want || e.ContextMap()["test"] != t.Name() {
+			t.Errorf("entry %d = %q %v, want %q with the option's field", i, e.Message, e.ContextMap(), want)
+		}
+		if !strings.HasSuffix(e.Caller.File, "testlogger_test.go") {
+			t.Errorf("entry %d caller = %s, want this file", i, e.Caller.File)
+		}
+	}
+	if entries[1].ContextMap()["n"] != int64(1) {
+		t.Errorf("fields = %v, want n=1", entries[1].ContextMap())
+	}
+}
+
+func TestCloneOfATestLogger(t *testing.T) {
+	svc, logs := NewTestLogger()
+	clone := svc.(*standardLogger).Clone().(*standardLogger)
+	clone.SetLevel("ERROR")
+	svc.Infoz("parent")
+	clone.Infoz("clone below its level")
+
+	if entries := logs.AllUntimed(); len(entries) != 1 || entries[0].Message != "parent" {
+		t.Errorf("entries = %v, want the parent's alone", entries)
+	}
+}
@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// truncatedMarker is appended to truncated messages and fields.
+const truncatedMarker = "…(truncated)"
+
+// WithMaxFieldLength returns an option, for NewService or zap loggers, that
+// truncates the message and string fields longer than max bytes, appending
+// "…(truncated)" and a <key>_original_length field, message_original_length
+// for the message, holding the length before truncation.
+func WithMaxFieldLength(max int) zap.Option {
+	return zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return newTruncateCore(core, max)
+	})
+}
+
+func newTruncateCore(core zapcore.Core, max int) zapcore.Core {
+	return &truncateCore{Core: core, max: max}
+}
+
+type truncateCore struct {
+	zapcore.Core
+	max int
+}
+
+func (c *truncateCore) With(fields []zapcore.Field) zapcore.Core {
+	return &truncateCore{Core: c.Core.With(c.truncateFields(fields)), max: c.max}
+}
+
+func (c *truncateCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *truncateCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	fields = c.truncateFields(fields)
+	if len(ent.Message) > c.max {
+		fields = append(fields[:len(fields):len(fields)], zap.Int("message_original_length", len(ent.Message)))
+		ent.Message = c.truncate(ent.Message)
+	}
+	return c.Core.Write(ent, fields)
+}
+
+// truncateFields returns fields with the string fields longer than max
+// truncated, copying fields only when one is.
+func (c *truncateCore) truncateFields(fields []zapcore.Field) []zapcore.Field {
+	var out []zapcore.Field
+	for i, f := range fields {
+		var s string
+		switch f.Type {
+		case zapcore.StringType:
+			s = f.String
+		case zapcore.ByteStringType:
+			s = string(f.Interface.([]byte))
+		}
+		if len(s) <= c.max {
+			if out != nil {
+				out = append(out, f)
+			}
+			continue
+		}
+		if out == nil {
+			out = append(make([]zapcore.Field, 0, len(fields)+1), fields[:i]...)
+		}
+		out = append(out, zap.String(f.Key, c.truncate(s)), zap.Int(f.Key+"_original_length", len(s)))
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+// truncate cuts s to max bytes, without splitting a character, and appends
+// truncatedMarker.
+func (c *truncateCore) truncate(s string) string {
+	n := c.max
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + truncatedMarker
+}
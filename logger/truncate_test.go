@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/dazzling420/go-logger/config"
+	"go.uber.org/zap"
+)
+
+func TestMaxFieldLength(t *testing.T) {
+	svc, path := newFileService(t, config.Logger{MaxFieldLength: 5})
+	svc.With(zap.String("bound", "0123456789")).Infoz("a long message",
+		zap.String("short", "abc"),
+		zap.String("long", "abcdefgh"),
+		zap.ByteString("bytes", []byte("xxxxxxxx")),
+		zap.Int("number", 123456789),
+	)
+	svc.Sync()
+
+	entries := readEntries(t, path)
+	if len(entries) != 1 {
+		t.Fatalf("wrote %d entries, want 1", len(entries))
+	}
+	e := entries[0]
+	for key, want := range map[string]interface{}{
+		"message":                 "a lon" + truncatedMarker,
+		"message_original_length": float64(14),
+		"bound":                   "01234" + truncatedMarker,
+		"bound_original_length":   float64(10),
+		"short":                   "abc",
+		"long":                    "abcde" + truncatedMarker,
+		"long_original_length":    float64(8),
+		"bytes":                   "xxxxx" + truncatedMarker,
+		"number":                  float64(123456789),
+	} {
+		if e[key] != want {
+			t.Errorf("%s = %v, want %v", key, e[key], want)
+		}
+	}
+	if _, ok := e["short_original_length"]; ok {
+		t.Error("short_original_length added for a field within the limit")
+	}
+}
+
+func TestMaxFieldLengthKeepsCharactersWhole(t *testing.T) {
+	svc, logs := NewTestLogger(WithMaxFieldLength(2))
+	svc.Infoz("ok", zap.String("word", "héllo")) // é takes bytes 1 and 2, the limit falls inside it
+
+	f := logs.AllUntimed()[0].ContextMap()
+	if f["word"] != "h"+truncatedMarker || f["word_original_length"] != int64(6) {
+		t.Errorf("fields = %v, want the word cut after a whole character", f)
+	}
+}
@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithUptime returns an option, for NewService or zap loggers, that adds an
+// uptime field to every entry, the time elapsed between start and the entry.
+func WithUptime(start time.Time) zap.Option {
+	return zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return newUptimeCore(core, start)
+	})
+}
+
+func newUptimeCore(core zapcore.Core, start time.Time) zapcore.Core {
+	return &uptimeCore{Core: core, start: start}
+}
+
+type uptimeCore struct {
+	zapcore.Core
+	start time.Time
+}
+
+func (c *uptimeCore) With(fields []zapcore.Field) zapcore.Core {
+	return &uptimeCore{Core: c.Core.With(fields), start: c.start}
+}
+
+func (c *uptimeCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *uptimeCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	fields = append(fields[:len(fields):len(fields)], zap.Duration("uptime", ent.Time.Sub(c.start)))
+	return c.Core.Write(ent, fields)
+}
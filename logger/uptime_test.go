@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dazzling420/go-logger/config"
+)
+
+func TestIncludeUptime(t *testing.T) {
+	before := time.Now()
+	s, path := newFileService(t, config.Logger{IncludeUptime: true})
+	s.Infoz("first")
+	time.Sleep(10 * time.Millisecond)
+	s.Infoz("second")
+	s.Sync()
+
+	entries := readEntries(t, path)
+	if len(entries) != 3 {
+		t.Fatalf("logged %d entries, want the start and 2", len(entries))
+	}
+	started := entries[0]
+	if started["message"] != "Logging started!" {
+		t.Errorf("first entry = %v, want the start logged", started)
+	}
+	start, err := time.Parse(time.RFC3339Nano, started["start_time"].(string))
+	if err != nil || start.Before(before.Truncate(time.Millisecond)) || start.After(time.Now()) {
+		t.Errorf("start_time = %v, want the time the logger was built", started["start_time"])
+	}
+
+	// durations are encoded in nanoseconds
+	first, ok1 := entries[1]["uptime"].(float64)
+	second, ok2 := entries[2]["uptime"].(float64)
+	if !ok1 || !ok2 || first < 0 {
+		t.Fatalf("uptime = %v and %v, want durations", entries[1]["uptime"], entries[2]["uptime"])
+	}
+	if time.Duration(second-first) < 10*time.Millisecond {
+		t.Errorf("uptime went from %v to %v, want it to grow by the time slept", time.Duration(first), time.Duration(second))
+	}
+}
+
+func TestWithUptime(t *testing.T) {
+	start := time.Now().Add(-time.Hour)
+	svc, logs := NewTestLogger(WithUptime(start))
+	svc.Infoz("an hour in")
+
+	uptime, ok := logs.All()[0].ContextMap()["uptime"].(time.Duration)
+	if !ok || uptime < time.Hour || uptime > time.Hour+time.Minute {
+		t.Errorf("uptime = %v, want about an hour", logs.All()[0].ContextMap()["uptime"])
+	}
+}
@@ -0,0 +1,29 @@
+package logger
+
+import (
+	"io"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zapio"
+)
+
+// Writer returns a writer logging every line written to it as an entry at
+// level, for libraries that only take an io.Writer. Lines are buffered until
+// their newline, the writer also implements io.Closer to log an unfinished
+// last line. The entries carry no caller, it would point at this package.
+func (s *standardLogger) Writer(level zapcore.Level) io.Writer {
+	return &zapio.Writer{Log: s.log.WithOptions(zap.WithCaller(false)), Level: level}
+}
+
+// InfoWriter returns a Writer logging at INFO, e.g. for the info stream of a
+// library taking one writer per stream.
+func (s *standardLogger) InfoWriter() io.Writer {
+	return s.Writer(INFO)
+}
+
+// ErrorWriter returns a Writer logging at ERROR, e.g. for
+// http.Server.ErrorLog through log.New.
+func (s *standardLogger) ErrorWriter() io.Writer {
+	return s.Writer(ERROR)
+}
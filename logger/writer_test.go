@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"testing"
+)
+
+func TestLevelWriters(t *testing.T) {
+	svc, logs := NewTestLogger()
+	s := svc.(*standardLogger)
+
+	fmt.Fprint(s.InfoWriter(), "first line\nsecond ")
+	errLog := log.New(s.ErrorWriter(), "", 0)
+	errLog.Print("http: TLS handshake error")
+	w := s.Writer(WARN)
+	io.WriteString(w, "unfinished")
+	w.(io.Closer).Close()
+
+	want := []string{"INFO first line", "ERROR http: TLS handshake error", "WARN unfinished"}
+	entries := logs.AllUntimed()
+	if len(entries) != len(want) {
+		t.Fatalf("logged %d entries, want %d", len(entries), len(want))
+	}
+	for i, e := range entries {
+		if got := LevelName(e.Level) + " " + e.Message; got != want[i] {
+			t.Errorf("entry %d = %s, want %s", i, got, want[i])
+		}
+		if e.Caller.Defined {
+			t.Errorf("entry %d has caller %s", i, e.Caller)
+		}
+	}
+}
+
+func TestLevelWriterFollowsTheLevel(t *testing.T) {
+	svc, logs := NewTestLogger()
+	s := svc.(*standardLogger)
+	s.SetLevel("ERROR")
+
+	fmt.Fprintln(s.InfoWriter(), "dropped")
+	fmt.Fprintln(s.ErrorWriter(), "kept")
+
+	if got := observedMessages(logs); len(got) != 1 || got[0] != "kept" {
+		t.Errorf("logged %q, want only the ERROR line", got)
+	}
+}